@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kode4food/ale/core/bootstrap"
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/env"
+	"github.com/kode4food/ale/runtime/bytecode"
+)
+
+// Error messages
+const (
+	ErrCompileUsage = "usage: ale compile <source file> [output file]"
+)
+
+// CompiledExt is the extension given to compiled files when the name of
+// the output file isn't provided
+const CompiledExt = ".alec"
+
+// CompileFile compiles the source file named on the command line into a
+// file that can be run without being read or compiled again. The forms
+// are evaluated as they're compiled, but in an environment whose
+// standard in/out/err streams are rerouted to and from /dev/null
+func CompileFile() {
+	defer exitWithError()
+
+	if len(os.Args) < 3 {
+		fmt.Println(ErrCompileUsage)
+		os.Exit(-1)
+	}
+	filename := os.Args[2]
+	out := compiledFilename(filename)
+	if len(os.Args) > 3 {
+		out = os.Args[3]
+	}
+
+	buffer, err := ioutil.ReadFile(filename)
+	if err != nil {
+		fmt.Println(fmt.Errorf(ErrFileNotFound, filename))
+		os.Exit(-1)
+	}
+	e := bootstrap.DevNullEnvironment()
+	bootstrap.Into(e)
+	m := bytecode.Compile(e.GetQualified(UserDomain), data.String(buffer))
+	res, err := bytecode.Marshal(m)
+	if err != nil {
+		panic(err)
+	}
+	if err := ioutil.WriteFile(out, res, 0644); err != nil {
+		panic(err)
+	}
+}
+
+func compiledFilename(filename string) string {
+	ext := filepath.Ext(filename)
+	return strings.TrimSuffix(filename, ext) + CompiledExt
+}
+
+func evalCompiled(ns env.Namespace, b []byte) data.Value {
+	m, err := bytecode.Unmarshal(ns, b)
+	if err != nil {
+		panic(err)
+	}
+	return m.Run()
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/kode4food/ale/core/bootstrap"
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/runtime/bytecode"
+	"github.com/kode4food/ale/runtime/disasm"
+	"github.com/kode4food/ale/runtime/vm"
+)
+
+// Error messages
+const (
+	ErrDisasmUsage = "usage: ale disasm <source or compiled file>"
+)
+
+// DisassembleFile prints a listing of the code that each top-level form
+// of the file named on the command line was compiled into. The forms
+// are evaluated, but in an environment whose standard in/out/err streams
+// are rerouted to and from /dev/null. Code that was loaded from a
+// compiled file, or that belongs to the core, only includes its
+// optimized instructions
+func DisassembleFile() {
+	defer exitWithError()
+
+	if len(os.Args) < 3 {
+		fmt.Println(ErrDisasmUsage)
+		os.Exit(-1)
+	}
+	filename := os.Args[2]
+	buffer, err := ioutil.ReadFile(filename)
+	if err != nil {
+		fmt.Println(fmt.Errorf(ErrFileNotFound, filename))
+		os.Exit(-1)
+	}
+
+	e := bootstrap.DevNullEnvironment()
+	bootstrap.Into(e)
+	ns := e.GetQualified(UserDomain)
+	vm.RetainSource(true)
+
+	var m bytecode.Module
+	if bytecode.IsCompiled(buffer) {
+		if m, err = bytecode.Unmarshal(ns, buffer); err != nil {
+			panic(err)
+		}
+		m.Run()
+	} else {
+		m = bytecode.Compile(ns, data.String(buffer))
+		m.Link()
+	}
+
+	for i, l := range m {
+		listing, err := disasm.Value(l)
+		if err != nil {
+			panic(err)
+		}
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf(";; form %d\n%s", i, listing)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/kode4food/ale/env"
 	"github.com/kode4food/ale/eval"
 	"github.com/kode4food/ale/read"
+	"github.com/kode4food/ale/runtime/bytecode"
 )
 
 // Error messages
@@ -24,7 +25,8 @@ func EvaluateStdIn() {
 	evalBuffer(ns, buffer)
 }
 
-// EvaluateFile reads the specific source file and evaluates it
+// EvaluateFile reads the specific source file and evaluates it. If it's
+// a file that was produced by CompileFile, it's run as is
 func EvaluateFile() {
 	defer exitWithError()
 
@@ -32,6 +34,8 @@ func EvaluateFile() {
 	if buffer, err := ioutil.ReadFile(filename); err != nil {
 		fmt.Println(fmt.Errorf(ErrFileNotFound, filename))
 		os.Exit(-1)
+	} else if bytecode.IsCompiled(buffer) {
+		evalCompiled(ns, buffer)
 	} else {
 		evalBuffer(ns, buffer)
 	}
@@ -2,7 +2,18 @@ package main
 
 import "os"
 
+var commands = map[string]func(){
+	"compile": CompileFile,
+	"disasm":  DisassembleFile,
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			cmd()
+			return
+		}
+	}
 	if isStdInPiped() {
 		EvaluateStdIn()
 	} else if len(os.Args) < 2 {
@@ -22,6 +22,7 @@ import (
 	"github.com/kode4food/ale/internal/console"
 	"github.com/kode4food/ale/internal/markdown"
 	"github.com/kode4food/ale/read"
+	"github.com/kode4food/ale/runtime/vm"
 )
 
 type (
@@ -64,8 +65,10 @@ var (
 	ns = bootstrap.TopLevelEnvironment().GetQualified(UserDomain)
 )
 
-// NewREPL instantiates a new REPL instance
+// NewREPL instantiates a new REPL instance. The functions that are
+// defined in it retain their unoptimized code, for disassemble
 func NewREPL() *REPL {
+	vm.RetainSource(true)
 	repl := new(REPL)
 
 	rl, err := readline.NewEx(&readline.Config{
@@ -2,6 +2,7 @@ package generate
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/kode4food/ale/compiler/encoder"
 	"github.com/kode4food/ale/data"
@@ -56,12 +57,22 @@ func Vector(e encoder.Encoder, v data.Vector) {
 	callFunction(e, f, v.Values())
 }
 
-// Object encodes an object
+// Object encodes an object. Its pairs are encoded in the order of their
+// keys, rather than in the order of their hash codes, which are seeded
+// differently by every process. This keeps compiled code reproducible
 func Object(e encoder.Encoder, a data.Object) {
-	args := data.Values{}
+	var pairs []data.Pair
 	for f, r, ok := a.Split(); ok; f, r, ok = r.Split() {
-		v := f.(data.Pair)
-		args = append(args, v.Car(), v.Cdr())
+		pairs = append(pairs, f.(data.Pair))
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		l := data.MaybeQuoteString(pairs[i].Car())
+		r := data.MaybeQuoteString(pairs[j].Car())
+		return l < r
+	})
+	args := make(data.Values, 0, len(pairs)*2)
+	for _, p := range pairs {
+		args = append(args, p.Car(), p.Cdr())
 	}
 	f := resolveBuiltIn(e, objectSym)
 	callApplicative(e, f, args)
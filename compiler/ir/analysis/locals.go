@@ -0,0 +1,19 @@
+package analysis
+
+import "github.com/kode4food/ale/runtime/isa"
+
+// CalculateLocalCount returns the number of locals that the provided
+// instructions refer to, based on the highest index that they load or
+// store
+func CalculateLocalCount(code isa.Instructions) int {
+	res := 0
+	for _, inst := range code {
+		switch inst.Opcode {
+		case isa.Load, isa.Store:
+			if idx := int(inst.Args[0]); idx >= res {
+				res = idx + 1
+			}
+		}
+	}
+	return res
+}
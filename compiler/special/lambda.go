@@ -78,7 +78,9 @@ func (le *lambdaEncoder) makeLambda() *vm.Lambda {
 		le.makeLambdaCases(le.cases)
 	}
 	res := vm.LambdaFromEncoder(le)
-	res.ArityChecker = le.makeArityChecker()
+	lower, upper := le.arityRange()
+	res.Arity = []int{lower, upper}
+	res.ArityChecker = data.MakeChecker(res.Arity...)
 	return res
 }
 
@@ -97,7 +99,7 @@ func (le *lambdaEncoder) makeLambdaCases(cases lambdaCases) {
 	)
 }
 
-func (le *lambdaEncoder) makeArityChecker() data.ArityChecker {
+func (le *lambdaEncoder) arityRange() (int, int) {
 	v0 := le.cases[0]
 	lower, upper := v0.arityRange()
 	for _, s := range le.cases[1:] {
@@ -109,7 +111,7 @@ func (le *lambdaEncoder) makeArityChecker() data.ArityChecker {
 		}
 		upper = util.IntMax(u, upper)
 	}
-	return data.MakeChecker(lower, upper)
+	return lower, upper
 }
 
 func (le *lambdaEncoder) makePredicate(c *lambdaCase) {
@@ -1,12 +1,15 @@
 package bootstrap
 
 import (
+	"io"
 	"os"
+	"sync"
 
 	"github.com/kode4food/ale/compiler/encoder"
 	"github.com/kode4food/ale/core/internal/builtin"
 	"github.com/kode4food/ale/data"
 	"github.com/kode4food/ale/env"
+	"github.com/kode4food/ale/internal/clock"
 	"github.com/kode4food/ale/internal/stream"
 	"github.com/kode4food/ale/macro"
 )
@@ -24,16 +27,28 @@ type (
 	funcMap    map[data.Name]data.Function
 )
 
-// Into sets up initial built-ins and assets
+var (
+	devNullOnce sync.Once
+	devNullFile *os.File
+)
+
+// Into sets up initial built-ins and assets. The assets are loaded from
+// the core's pre-built image, unless it can't be decoded
 func Into(e *env.Environment) {
-	b := &bootstrap{
+	b := newBootstrap(e)
+	b.builtIns()
+	if !b.image() {
+		b.assets()
+	}
+}
+
+func newBootstrap(e *env.Environment) *bootstrap {
+	return &bootstrap{
 		environment: e,
 		macroMap:    macroMap{},
 		specialMap:  specialMap{},
 		funcMap:     funcMap{},
 	}
-	b.builtIns()
-	b.assets()
 }
 
 // TopLevelEnvironment configures an environment that could be used
@@ -54,11 +69,47 @@ func TopLevelEnvironment() *env.Environment {
 // isolated from the top-level of the system. All I/O is rerouted to
 // and from /dev/null
 func DevNullEnvironment() *env.Environment {
+	f := devNull()
+	return StreamEnvironment(f, f, f)
+}
+
+// StreamEnvironment configures an environment whose standard in/out/err
+// file streams are the provided readers and writers
+func StreamEnvironment(in io.Reader, out, err io.Writer) *env.Environment {
 	e := env.NewEnvironment()
-	ns := e.GetRoot()
-	devNull, _ := os.OpenFile(os.DevNull, os.O_RDWR, 0666)
-	ns.Declare("*in*").Bind(builtin.MakeReader(devNull, stream.LineInput))
-	ns.Declare("*out*").Bind(builtin.MakeWriter(devNull, stream.StrOutput))
-	ns.Declare("*err*").Bind(builtin.MakeWriter(devNull, stream.StrOutput))
+	bindStreams(e.GetRoot(), in, out, err)
 	return e
 }
+
+func bindStreams(ns env.Namespace, in io.Reader, out, err io.Writer) {
+	ns.Declare("*in*").Bind(builtin.MakeReader(in, stream.LineInput))
+	ns.Declare("*out*").Bind(builtin.MakeWriter(out, stream.StrOutput))
+	ns.Declare("*err*").Bind(builtin.MakeWriter(err, stream.StrOutput))
+}
+
+// environmentFunctions returns the functions that every environment
+// binds for itself, so that the keywords derived in one environment
+// aren't related in another, and replacing the clock in one environment
+// doesn't affect the timers of another
+func environmentFunctions() map[data.Name]data.Function {
+	res := builtin.HierarchyFunctions(data.NewHierarchy())
+	for n, f := range builtin.ClockFunctions(clock.NewRef(clock.Real)) {
+		res[n] = f
+	}
+	return res
+}
+
+func bindEnvironmentFunctions(ns env.Namespace) {
+	for n, f := range environmentFunctions() {
+		ns.Declare(n).Bind(f)
+	}
+}
+
+// devNull returns the handle to /dev/null that's shared by all of the
+// environments whose streams are rerouted there. It's opened only once
+func devNull() *os.File {
+	devNullOnce.Do(func() {
+		devNullFile, _ = os.OpenFile(os.DevNull, os.O_RDWR, 0666)
+	})
+	return devNullFile
+}
@@ -98,69 +98,185 @@ func (b *bootstrap) availableFunctions() {
 		">":  builtin.Gt,
 		">=": builtin.Gte,
 
+		"alts":         builtin.Alts,
 		"append":       builtin.Append,
 		"apply":        builtin.Apply,
 		"assoc":        builtin.Assoc,
+		"assoc-in":     builtin.AssocIn,
 		"car":          builtin.Car,
 		"cdr":          builtin.Cdr,
 		"chan":         builtin.Chan,
 		"cons":         builtin.Cons,
-		"current-time": builtin.CurrentTime,
 		"defer":        builtin.Defer,
+		"disassemble*": builtin.Disassemble,
 		"dissoc":       builtin.Dissoc,
 		"promise":      builtin.Promise,
 		"eq":           builtin.IsIdentical,
 		"first":        builtin.First,
+		"fold":         builtin.Fold,
 		"gensym":       builtin.GenSym,
 		"get":          builtin.Get,
+		"get-in":       builtin.GetIn,
+		"filter*":      builtin.Filter,
 		"go*":          builtin.Go,
+		"keys":         builtin.Keys,
 		"lazy-seq*":    builtin.LazySequence,
 		"length":       builtin.Length,
 		"list":         builtin.List,
 		"macro":        builtin.Macro,
+		"map*":         builtin.Map,
+		"merge":        builtin.Merge,
+		"merge-chan":   builtin.MergeChan,
+		"merge-with":   builtin.MergeWith,
 		"mod":          builtin.Mod,
+		"mult":         builtin.Mult,
 		"nth":          builtin.Nth,
 		"object":       builtin.Object,
+		"pfilter":      builtin.ParallelFilter,
+		"pmap":         builtin.ParallelMap,
+		"pub":          builtin.Publish,
 		"raise":        builtin.Raise,
 		"read":         builtin.Read,
+		"record-type":  builtin.RecordType,
+		"reduce":       builtin.Reduce,
 		"recover":      builtin.Recover,
+		"rename-keys":  builtin.RenameKeys,
 		"rest":         builtin.Rest,
 		"reverse":      builtin.Reverse,
+		"select*":      builtin.Select,
+		"select-keys":  builtin.SelectKeys,
+		"split-chan":   builtin.SplitChan,
 		"str!":         builtin.ReaderStr,
 		"str":          builtin.Str,
+		"sub":          builtin.Subscribe,
 		"sym":          builtin.Sym,
+		"tap":          builtin.Tap,
+		"vals":         builtin.Vals,
 		"vector":       builtin.Vector,
 
-		"is-appender":   builtin.IsAppender,
-		"is-apply":      builtin.IsApply,
-		"is-atom":       builtin.IsAtom,
-		"is-boolean":    builtin.IsBoolean,
-		"is-cons":       builtin.IsCons,
-		"is-counted":    builtin.IsCounted,
-		"is-empty":      builtin.IsEmpty,
-		"is-indexed":    builtin.IsIndexed,
-		"is-keyword":    builtin.IsKeyword,
-		"is-list":       builtin.IsList,
-		"is-local":      builtin.IsLocal,
-		"is-macro":      builtin.IsMacro,
-		"is-mapped":     builtin.IsMapped,
-		"is-nan":        builtin.IsNaN,
-		"is-neg-inf":    builtin.IsNegInf,
-		"is-number":     builtin.IsNumber,
-		"is-object":     builtin.IsObject,
-		"is-pair":       builtin.IsPair,
-		"is-pos-inf":    builtin.IsPosInf,
-		"is-promise":    builtin.IsPromise,
-		"is-qualified":  builtin.IsQualified,
-		"is-resolved":   builtin.IsResolved,
-		"is-reversible": builtin.IsReverser,
-		"is-seq":        builtin.IsSeq,
-		"is-special":    builtin.IsSpecial,
-		"is-string":     builtin.IsString,
-		"is-symbol":     builtin.IsSymbol,
-		"is-vector":     builtin.IsVector,
+		"assoc!":      builtin.AssocBang,
+		"conj!":       builtin.ConjBang,
+		"dissoc!":     builtin.DissocBang,
+		"persistent!": builtin.Persistent,
+		"transient":   builtin.Transient,
+
+		"reduced":     builtin.Reduced,
+		"sequence":    builtin.Sequence,
+		"transduce":   builtin.Transduce,
+		"transducer*": builtin.Transducer,
+
+		"cancel":          builtin.Cancel,
+		"check-cancelled": builtin.CheckCancelled,
+		"generate*":       builtin.Generate,
+		"scope":           builtin.Scope,
+
+		"catch":        builtin.Catch,
+		"deliver":      builtin.Deliver,
+		"deref":        builtin.Deref,
+		"future*":      builtin.Future,
+		"promise-all":  builtin.PromiseAll,
+		"promise-any":  builtin.PromiseAny,
+		"promise-race": builtin.PromiseRace,
+		"then":         builtin.Then,
+
+		"schedule":       builtin.Schedule,
+		"schedule-cron":  builtin.ScheduleCron,
+		"schedule-every": builtin.ScheduleEvery,
+
+		"demonitor":           builtin.Demonitor,
+		"exit":                builtin.Exit,
+		"link":                builtin.Link,
+		"monitor":             builtin.Monitor,
+		"register":            builtin.Register,
+		"registered":          builtin.Registered,
+		"self":                builtin.Self,
+		"spawn":               builtin.Spawn,
+		"supervisor":          builtin.Supervisor,
+		"supervisor-children": builtin.SupervisorChildren,
+		"trap-exits":          builtin.TrapExits,
+		"unlink":              builtin.Unlink,
+		"unregister":          builtin.Unregister,
+		"whereis":             builtin.Whereis,
+
+		"connect-node":    builtin.ConnectNode,
+		"node-addr":       builtin.NodeAddr,
+		"node-name":       builtin.NodeName,
+		"node-peers":      builtin.NodePeers,
+		"node-register":   builtin.NodeRegister,
+		"node-unregister": builtin.NodeUnregister,
+		"node-whereis":    builtin.NodeWhereis,
+		"start-node":      builtin.StartNode,
+		"stop-node":       builtin.StopNode,
+
+		"acquire":     builtin.Acquire,
+		"await":       builtin.Await,
+		"count-down":  builtin.CountDown,
+		"countdown":   builtin.Countdown,
+		"lock":        builtin.Lock,
+		"mutex":       builtin.Mutex,
+		"read-lock":   builtin.ReadLock,
+		"read-unlock": builtin.ReadUnlock,
+		"release":     builtin.Release,
+		"rw-mutex":    builtin.RWMutex,
+		"semaphore":   builtin.Semaphore,
+		"try-acquire": builtin.TryAcquire,
+		"try-lock":    builtin.TryLock,
+		"unlock":      builtin.Unlock,
+
+		"add-method":                builtin.AddMethod,
+		"extend-protocol*":          builtin.ExtendProtocol,
+		"make-hierarchy":            builtin.MakeHierarchy,
+		"prefer-method":             builtin.PreferMethod,
+		"protocol":                  builtin.Protocol,
+		"protocol-method":           builtin.ProtocolMethod,
+		"record-accessor":           builtin.RecordAccessor,
+		"record-constructor":        builtin.RecordConstructor,
+		"record-object-constructor": builtin.RecordObjectConstructor,
+		"remove-method":             builtin.RemoveMethod,
+		"satisfies":                 builtin.Satisfies,
+
+		"is-appender":    builtin.IsAppender,
+		"is-apply":       builtin.IsApply,
+		"is-atom":        builtin.IsAtom,
+		"is-boolean":     builtin.IsBoolean,
+		"is-cancelled":   builtin.IsCancelled,
+		"is-cons":        builtin.IsCons,
+		"is-counted":     builtin.IsCounted,
+		"is-empty":       builtin.IsEmpty,
+		"is-error":       builtin.IsError,
+		"is-indexed":     builtin.IsIndexed,
+		"is-keyword":     builtin.IsKeyword,
+		"is-list":        builtin.IsList,
+		"is-local":       builtin.IsLocal,
+		"is-macro":       builtin.IsMacro,
+		"is-alive":       builtin.IsAlive,
+		"is-mapped":      builtin.IsMapped,
+		"is-multimethod": builtin.IsMultimethod,
+		"is-nan":         builtin.IsNaN,
+		"is-neg-inf":     builtin.IsNegInf,
+		"is-number":      builtin.IsNumber,
+		"is-object":      builtin.IsObject,
+		"is-pair":        builtin.IsPair,
+		"is-pos-inf":     builtin.IsPosInf,
+		"is-process":     builtin.IsProcess,
+		"is-promise":     builtin.IsPromise,
+		"is-protocol":    builtin.IsProtocol,
+		"is-qualified":   builtin.IsQualified,
+		"is-record":      builtin.IsRecord,
+		"is-record-type": builtin.IsRecordType,
+		"is-reduced":     builtin.IsReduced,
+		"is-resolved":    builtin.IsResolved,
+		"is-reversible":  builtin.IsReverser,
+		"is-seq":         builtin.IsSeq,
+		"is-special":     builtin.IsSpecial,
+		"is-string":      builtin.IsString,
+		"is-symbol":      builtin.IsSymbol,
+		"is-transient":   builtin.IsTransient,
+		"is-vector":      builtin.IsVector,
 	})
 
+	b.functions(environmentFunctions())
+
 	b.macros(map[data.Name]macro.Call{
 		"syntax-quote": macro.SyntaxQuote,
 	})
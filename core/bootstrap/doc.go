@@ -1,3 +1,8 @@
 // Package bootstrap performs initial setup of the environment.
 // Create a new `*env.Environment` and then pass it to `Into`.
+//
+// The core library is loaded from an image that's compiled ahead of
+// time. After changing the core's source, run `go generate` to rebuild
+// the image. Until then, the image no longer matches the hash of the
+// source, so the source is evaluated instead.
 package bootstrap
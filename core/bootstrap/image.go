@@ -0,0 +1,107 @@
+package bootstrap
+
+import (
+	"bytes"
+	"crypto/sha256"
+	_ "embed" // required for the core image
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/kode4food/ale/core"
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/env"
+	"github.com/kode4food/ale/runtime/bytecode"
+)
+
+//go:generate go run ./internal/mkimage core.alec
+
+// coreImage is the core's source, compiled ahead of time by BuildImage
+//
+//go:embed core.alec
+var coreImage []byte
+
+var (
+	sourceHashOnce sync.Once
+	sourceHashSum  []byte
+)
+
+// IntoFromSource sets up initial built-ins and then evaluates the core's
+// source, rather than loading its pre-built image
+func IntoFromSource(e *env.Environment) {
+	b := newBootstrap(e)
+	b.builtIns()
+	b.assets()
+}
+
+// BuildImage compiles the core's source into the image that Into loads.
+// The image starts with a hash of the source that it was compiled from,
+// and building it from the same source always produces the same bytes
+func BuildImage() ([]byte, error) {
+	b := newBootstrap(DevNullEnvironment())
+	b.builtIns()
+	ns := b.environment.GetRoot()
+	var res bytecode.Module
+	for _, filename := range core.Names() {
+		src, err := core.Get(filename)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, bytecode.Compile(ns, data.String(src))...)
+	}
+	m, err := bytecode.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, sourceHash()...), m...), nil
+}
+
+// IsCurrentImage returns whether the provided image was built from the
+// core's current source
+func IsCurrentImage(img []byte) bool {
+	return bytes.HasPrefix(img, sourceHash())
+}
+
+// sourceHash returns the hash of the core's source, which is only
+// calculated once
+func sourceHash() []byte {
+	sourceHashOnce.Do(func() {
+		h := sha256.New()
+		for _, filename := range core.Names() {
+			src, err := core.Get(filename)
+			if err != nil {
+				panic(err)
+			}
+			fmt.Fprintf(h, "%s\x00%d\x00", filename, len(src))
+			h.Write(src)
+		}
+		sourceHashSum = h.Sum(nil)
+	})
+	return sourceHashSum
+}
+
+// image loads the pre-built core image into the root namespace. If the
+// image wasn't built from the core's current source, or it can't be
+// decoded, such as when it was built for a different version of the
+// bytecode, the source is evaluated instead
+func (b *bootstrap) image() bool {
+	if !IsCurrentImage(coreImage) {
+		return false
+	}
+	ns := b.environment.GetRoot()
+	m, err := bytecode.Unmarshal(ns, coreImage[sha256.Size:])
+	if err != nil {
+		return false
+	}
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			fmt.Fprint(os.Stderr, "\nBootstrap Error\n\n")
+			fmt.Fprintf(os.Stderr, "  core image: %v\n\n", rec)
+			os.Exit(-1)
+		}
+	}()
+
+	m.Run()
+	return true
+}
@@ -0,0 +1,76 @@
+package bootstrap_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/kode4food/ale/core/bootstrap"
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/env"
+	"github.com/kode4food/ale/internal/assert"
+)
+
+func describe(e env.Entry) string {
+	if !e.IsBound() {
+		return fmt.Sprintf("%s unbound", e.Name())
+	}
+	switch v := e.Value().(type) {
+	case data.Function:
+		return fmt.Sprintf("%s %T %s", e.Name(), v, v.Convention())
+	case data.Value:
+		return fmt.Sprintf("%s %T %s", e.Name(), v, v)
+	default:
+		return fmt.Sprintf("%s %T", e.Name(), v)
+	}
+}
+
+func TestImageMatchesSource(t *testing.T) {
+	as := assert.New(t)
+
+	fromSource := bootstrap.DevNullEnvironment()
+	bootstrap.IntoFromSource(fromSource)
+	fromImage := bootstrap.DevNullEnvironment()
+	bootstrap.Into(fromImage)
+
+	// the streams are bound before bootstrapping, once per environment
+	streams := map[data.Name]bool{}
+	for _, n := range bootstrap.DevNullEnvironment().GetRoot().Declared() {
+		streams[n] = true
+	}
+
+	src := fromSource.GetRoot()
+	img := fromImage.GetRoot()
+	as.Equal(src.Declared(), img.Declared())
+	for _, n := range src.Declared() {
+		if streams[n] {
+			continue
+		}
+		s, _ := src.Resolve(n)
+		i, _ := img.Resolve(n)
+		as.Equal(describe(s), describe(i))
+	}
+}
+
+func TestImageIsCurrent(t *testing.T) {
+	as := assert.New(t)
+
+	// regenerate core.alec with `go generate` if this fails
+	embedded, err := ioutil.ReadFile("core.alec")
+	as.Nil(err)
+	as.True(bootstrap.IsCurrentImage(embedded))
+
+	built, err := bootstrap.BuildImage()
+	as.Nil(err)
+	as.True(bootstrap.IsCurrentImage(built))
+
+	built[0]++
+	as.False(bootstrap.IsCurrentImage(built))
+}
+
+func BenchmarkBootstrappingFromSource(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		e := bootstrap.DevNullEnvironment()
+		bootstrap.IntoFromSource(e)
+	}
+}
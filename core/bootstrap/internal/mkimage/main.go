@@ -0,0 +1,26 @@
+// Command mkimage compiles the core's source into the image that the
+// bootstrap package embeds. It's run by `go generate`
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/kode4food/ale/core/bootstrap"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: mkimage <output>")
+		os.Exit(-1)
+	}
+	b, err := bootstrap.BuildImage()
+	if err == nil {
+		err = ioutil.WriteFile(os.Args[1], b, 0644)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(-1)
+	}
+}
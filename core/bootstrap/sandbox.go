@@ -0,0 +1,246 @@
+package bootstrap
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/env"
+	"github.com/kode4food/ale/ffi"
+)
+
+type (
+	// Capability identifies a group of root names that grant access to
+	// the same kind of resource
+	Capability string
+
+	// Sandbox builds environments that restrict which root names the
+	// code compiled in them can refer to. Everything is allowed until
+	// it's denied. Rules for individual names take precedence over the
+	// rules for the Capabilities that include them
+	Sandbox struct {
+		names    map[data.Name]bool
+		groups   map[Capability]bool
+		bindings map[data.Name]data.Value
+		shared   *Shared
+	}
+
+	restricted struct {
+		env.Namespace
+		environment *env.Environment
+		denied      map[data.Name]bool
+	}
+)
+
+// Capabilities
+const (
+	IO          = Capability("io")
+	OS          = Capability("os")
+	Concurrency = Capability("concurrency")
+	FFI         = Capability("ffi")
+	Eval        = Capability("eval")
+)
+
+// Error messages
+const (
+	ErrSymbolDenied      = "symbol is not allowed in this sandbox: %s"
+	ErrUnknownCapability = "unknown capability: %s"
+)
+
+var capabilities = map[Capability][]data.Name{
+	IO: {
+		"*in*", "*out*", "*err*", "pr", "prn", "print", "println",
+		"with-open", "connect-node", "node-addr", "node-name",
+		"node-peers", "node-register", "node-unregister", "node-whereis",
+		"start-node", "stop-node",
+	},
+	OS: {
+		"*env*", "*args*", "current-time", "time",
+	},
+	Concurrency: {
+		"go*", "go", "go-in", "future*", "future", "generate*", "generate",
+		"generate-in", "chan", "select*", "select", "alts", "timeout",
+		"mult", "tap", "pub", "sub", "merge-chan", "split-chan", "pmap",
+		"pfilter", "fold", "deliver", "deref", "then", "promise-all",
+		"promise-any", "promise-race", "scope", "cancel",
+		"check-cancelled", "is-cancelled",
+		"advance-clock", "after", "every", "schedule", "schedule-cron",
+		"schedule-every", "scheduler", "sleep", "ticker", "use-clock",
+		"with-fake-clock", "demonitor", "exit", "link", "monitor",
+		"register", "registered", "self", "spawn", "supervisor",
+		"supervisor-children", "trap-exits", "unlink", "unregister",
+		"whereis", "acquire", "await", "count-down", "countdown", "lock",
+		"mutex", "read-lock", "read-unlock", "release", "rw-mutex",
+		"semaphore", "try-acquire", "try-lock", "unlock", "with-lock",
+		"with-read-lock", "with-permit",
+	},
+	Eval: {
+		"eval", "read", "macroexpand", "macroexpand-1", "disassemble",
+		"disassemble*",
+	},
+}
+
+// NewSandbox returns a Sandbox that allows everything
+func NewSandbox() *Sandbox {
+	return &Sandbox{
+		names:    map[data.Name]bool{},
+		groups:   map[Capability]bool{},
+		bindings: map[data.Name]data.Value{},
+	}
+}
+
+// CapabilityNames returns the root names that the Capability includes
+func CapabilityNames(c Capability) []data.Name {
+	return append([]data.Name{}, capabilities[c]...)
+}
+
+// Allow allows the code in the Sandbox to refer to the provided names
+func (s *Sandbox) Allow(names ...data.Name) *Sandbox {
+	for _, n := range names {
+		s.names[n] = true
+	}
+	return s
+}
+
+// Deny stops the code in the Sandbox from referring to the provided
+// names
+func (s *Sandbox) Deny(names ...data.Name) *Sandbox {
+	for _, n := range names {
+		s.names[n] = false
+	}
+	return s
+}
+
+// AllowGroup allows the code in the Sandbox to refer to the names that
+// the provided Capabilities include
+func (s *Sandbox) AllowGroup(caps ...Capability) *Sandbox {
+	return s.setGroups(caps, true)
+}
+
+// DenyGroup stops the code in the Sandbox from referring to the names
+// that the provided Capabilities include
+func (s *Sandbox) DenyGroup(caps ...Capability) *Sandbox {
+	return s.setGroups(caps, false)
+}
+
+func (s *Sandbox) setGroups(caps []Capability, allowed bool) *Sandbox {
+	for _, c := range caps {
+		if _, ok := capabilities[c]; !ok && c != FFI {
+			panic(fmt.Errorf(ErrUnknownCapability, c))
+		}
+		s.groups[c] = allowed
+	}
+	return s
+}
+
+// Bind wraps the provided Go value and binds it to a root name in the
+// environments that the Sandbox builds. The name is included in the FFI
+// Capability
+func (s *Sandbox) Bind(name data.Name, v interface{}) error {
+	w, err := ffi.Wrap(v)
+	if err != nil {
+		return err
+	}
+	s.bindings[name] = w
+	return nil
+}
+
+// Share derives the environments that the Sandbox builds from the
+// provided Shared root, rather than bootstrapping each of them
+func (s *Sandbox) Share(r *Shared) *Sandbox {
+	s.shared = r
+	return s
+}
+
+// Environment builds a new bootstrapped environment that's restricted
+// by the Sandbox's rules. Its standard in/out/err streams are rerouted
+// to and from /dev/null
+func (s *Sandbox) Environment() *env.Environment {
+	f := devNull()
+	return s.StreamEnvironment(f, f, f)
+}
+
+// StreamEnvironment builds a new bootstrapped environment that's
+// restricted by the Sandbox's rules, and whose standard in/out/err file
+// streams are the provided readers and writers
+func (s *Sandbox) StreamEnvironment(
+	in io.Reader, out, err io.Writer,
+) *env.Environment {
+	var inner *env.Environment
+	if s.shared != nil {
+		inner = s.shared.StreamEnvironment(in, out, err)
+	} else {
+		inner = StreamEnvironment(in, out, err)
+		Into(inner)
+	}
+	return s.build(inner)
+}
+
+func (s *Sandbox) build(inner *env.Environment) *env.Environment {
+	root := inner.GetRoot()
+	for n, v := range s.bindings {
+		root.Declare(n).Bind(v)
+	}
+
+	e := env.NewEnvironment()
+	r := &restricted{
+		Namespace:   root,
+		environment: e,
+		denied:      s.deniedNames(),
+	}
+	e.Get(env.RootDomain, func() env.Namespace {
+		return r
+	})
+	return e
+}
+
+func (s *Sandbox) deniedNames() map[data.Name]bool {
+	res := map[data.Name]bool{}
+	for c, allowed := range s.groups {
+		if allowed {
+			continue
+		}
+		for _, n := range s.groupNames(c) {
+			res[n] = true
+		}
+	}
+	for n, allowed := range s.names {
+		if allowed {
+			delete(res, n)
+			continue
+		}
+		res[n] = true
+	}
+	return res
+}
+
+func (s *Sandbox) groupNames(c Capability) []data.Name {
+	if c != FFI {
+		return capabilities[c]
+	}
+	res := make([]data.Name, 0, len(s.bindings))
+	for n := range s.bindings {
+		res = append(res, n)
+	}
+	return res
+}
+
+func (r *restricted) Environment() *env.Environment {
+	return r.environment
+}
+
+func (r *restricted) Declare(n data.Name) env.Entry {
+	r.checkAllowed(n)
+	return r.Namespace.Declare(n)
+}
+
+func (r *restricted) Resolve(n data.Name) (env.Entry, bool) {
+	r.checkAllowed(n)
+	return r.Namespace.Resolve(n)
+}
+
+func (r *restricted) checkAllowed(n data.Name) {
+	if r.denied[n] {
+		panic(fmt.Errorf(ErrSymbolDenied, n))
+	}
+}
@@ -0,0 +1,99 @@
+package bootstrap_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kode4food/ale/core/bootstrap"
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/env"
+	"github.com/kode4food/ale/eval"
+	"github.com/kode4food/ale/internal/assert"
+)
+
+func denied(as *assert.Wrapper, ns env.Namespace, src data.String) {
+	defer as.ExpectPanic("symbol is not allowed in this sandbox")
+	eval.String(ns, src)
+}
+
+func TestSandboxCapabilities(t *testing.T) {
+	as := assert.New(t)
+
+	e := bootstrap.NewSandbox().
+		DenyGroup(bootstrap.IO, bootstrap.Concurrency, bootstrap.Eval).
+		Allow("chan").
+		Deny("str!").
+		Environment()
+	ns := e.GetAnonymous()
+
+	as.Number(6, eval.String(ns, "(+ 1 2 3)"))
+	as.String("[1 2 3]", eval.String(ns, "(seq->vector (map inc [0 1 2]))"))
+	as.True(eval.String(ns, "(is-object (chan))"))
+
+	denied(as, ns, `(println "hello")`)
+	denied(as, ns, `(go (+ 1 2))`)
+	denied(as, ns, `(ale/go* (lambda () 1))`)
+	denied(as, ns, `(eval '(+ 1 2))`)
+	denied(as, ns, `(str! "x")`)
+
+	// the core's macros that aren't about concurrency still work
+	as.Number(42, eval.String(ns, "(force (delay 42))"))
+	as.Number(42, eval.String(ns, "(force (lazy (delay 42)))"))
+	as.True(eval.String(ns, `
+		(derive :sandbox-square :sandbox-shape)
+		(isa? :sandbox-square :sandbox-shape)
+	`))
+
+	// denied symbols fail when compiled, not when called
+	denied(as, ns, `(lambda () (println "never"))`)
+
+	// the restrictions only apply to the sandbox's environment
+	as.Number(3, eval.String(ns, "(define (f) (+ 1 2)) (f)"))
+	as.String(`"x"`, eval.String(
+		bootstrap.NewSandbox().Environment().GetAnonymous(),
+		`(str! "x")`,
+	))
+
+	defer as.ExpectPanic(fmt.Sprintf(bootstrap.ErrUnknownCapability, "net"))
+	bootstrap.NewSandbox().DenyGroup("net")
+}
+
+func TestSandboxFFI(t *testing.T) {
+	as := assert.New(t)
+
+	s := bootstrap.NewSandbox()
+	as.Nil(s.Bind("double", func(i int) int { return i * 2 }))
+	as.Number(42, eval.String(s.Environment().GetAnonymous(), "(double 21)"))
+
+	s.DenyGroup(bootstrap.FFI)
+	denied(as, s.Environment().GetAnonymous(), "(double 21)")
+
+	s.Allow("double")
+	as.Number(8, eval.String(s.Environment().GetAnonymous(), "(double 4)"))
+}
+
+func TestCapabilityNames(t *testing.T) {
+	as := assert.New(t)
+
+	e := bootstrap.DevNullEnvironment()
+	bootstrap.Into(e)
+	root := e.GetRoot()
+	groups := map[data.Name]bootstrap.Capability{}
+	for _, c := range []bootstrap.Capability{
+		bootstrap.IO, bootstrap.OS, bootstrap.Concurrency, bootstrap.Eval,
+	} {
+		names := bootstrap.CapabilityNames(c)
+		as.NotEqual(0, len(names))
+		for _, n := range names {
+			_, dup := groups[n]
+			as.False(dup)
+			groups[n] = c
+			if n == "*env*" || n == "*args*" {
+				continue
+			}
+			_, ok := root.Resolve(n)
+			as.True(ok)
+		}
+	}
+	as.Equal(0, len(bootstrap.CapabilityNames(bootstrap.FFI)))
+}
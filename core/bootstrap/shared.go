@@ -0,0 +1,73 @@
+package bootstrap
+
+import (
+	"io"
+
+	"github.com/kode4food/ale/core"
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/env"
+	"github.com/kode4food/ale/runtime/bytecode"
+)
+
+// Shared is a bootstrapped root namespace that many environments can be
+// derived from. Deriving an environment doesn't copy the root. Each one
+// declares its own names, including its standard in/out/err streams,
+// its default hierarchy and its clock, without being able to alter the
+// shared root or the other environments
+type Shared struct {
+	environment *env.Environment
+	streams     []byte
+}
+
+// streamsAsset holds the core functions that refer to the standard
+// streams. They're defined again in every derived environment, so that
+// they refer to that environment's streams rather than the shared ones
+const streamsAsset = "12_print.ale"
+
+// NewShared bootstraps a new Shared root. Its own standard in/out/err
+// streams are rerouted to and from /dev/null
+func NewShared() *Shared {
+	e := DevNullEnvironment()
+	Into(e)
+
+	src, err := core.Get(streamsAsset)
+	if err != nil {
+		panic(err)
+	}
+	scratch := e.Derive().GetRoot()
+	streams, err := bytecode.Marshal(
+		bytecode.Compile(scratch, data.String(src)),
+	)
+	if err != nil {
+		panic(err)
+	}
+	return &Shared{
+		environment: e,
+		streams:     streams,
+	}
+}
+
+// Environment derives a new environment from the Shared root. Its
+// standard in/out/err streams are rerouted to and from /dev/null
+func (s *Shared) Environment() *env.Environment {
+	f := devNull()
+	return s.StreamEnvironment(f, f, f)
+}
+
+// StreamEnvironment derives a new environment from the Shared root,
+// whose standard in/out/err file streams are the provided readers and
+// writers
+func (s *Shared) StreamEnvironment(
+	in io.Reader, out, err io.Writer,
+) *env.Environment {
+	e := s.environment.Derive()
+	root := e.GetRoot()
+	bindStreams(root, in, out, err)
+	bindEnvironmentFunctions(root)
+	m, uerr := bytecode.Unmarshal(root, s.streams)
+	if uerr != nil {
+		panic(uerr)
+	}
+	m.Run()
+	return e
+}
@@ -0,0 +1,104 @@
+package bootstrap_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/kode4food/ale/core/bootstrap"
+	"github.com/kode4food/ale/core/internal/builtin"
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/eval"
+	"github.com/kode4food/ale/internal/assert"
+)
+
+func TestSharedEnvironments(t *testing.T) {
+	as := assert.New(t)
+
+	s := bootstrap.NewShared()
+	var out1, out2 bytes.Buffer
+	e1 := s.StreamEnvironment(strings.NewReader("line"), &out1, &out1)
+	e2 := s.StreamEnvironment(strings.NewReader(""), &out2, &out2)
+	ns1 := e1.GetAnonymous()
+	ns2 := e2.GetQualified("user")
+
+	eval.String(ns1, `(println "first" 1)`)
+	eval.String(ns2, `(prn "second" 2) (: *err* :write "err")`)
+	as.String("first 1\n", data.String(out1.String()))
+	as.String("\"second\" 2\nerr", data.String(out2.String()))
+	as.String("line", eval.String(ns1, `(first *in*)`))
+
+	eval.String(ns1, `(define shadowed 1)`)
+	eval.String(e1.GetRoot(), `(define root-def 2)`)
+	as.Number(1, eval.String(ns1, `shadowed`))
+	as.Number(2, eval.String(ns1, `root-def`))
+
+	_, ok := e2.GetRoot().Resolve("root-def")
+	as.False(ok)
+	_, ok = s.Environment().GetRoot().Resolve("root-def")
+	as.False(ok)
+}
+
+func TestSharedRootUnchanged(t *testing.T) {
+	as := assert.New(t)
+
+	s := bootstrap.NewShared()
+	_, ok := s.Environment().GetRoot().Resolve("*env*")
+	as.False(ok)
+
+	e := s.Environment()
+	root := e.GetRoot()
+	eval.String(root, `(define *env* {:name "child"})`)
+	as.String(`{:name "child"}`, eval.String(e.GetAnonymous(), `*env*`))
+
+	_, ok = s.Environment().GetRoot().Resolve("*env*")
+	as.False(ok)
+}
+
+func TestSharedHierarchies(t *testing.T) {
+	as := assert.New(t)
+
+	s := bootstrap.NewShared()
+	ns1 := s.Environment().GetAnonymous()
+	ns2 := s.Environment().GetAnonymous()
+
+	eval.String(ns1, `(derive :circle :shape)`)
+	as.True(eval.String(ns1, `(isa? :circle :shape)`))
+	as.False(eval.String(ns2, `(isa? :circle :shape)`))
+	as.False(eval.String(ns2, `(is-a :circle :shape)`))
+}
+
+func TestSharedClocks(t *testing.T) {
+	as := assert.New(t)
+
+	s := bootstrap.NewShared()
+	ns1 := s.Environment().GetAnonymous()
+	ns2 := s.Environment().GetAnonymous()
+
+	eval.String(ns1, `(use-clock :fake)`)
+	as.Equal(data.Nil, eval.String(ns1, `(advance-clock 10)`))
+
+	defer as.ExpectPanic(builtin.ErrNotFakeClock)
+	eval.String(ns2, `(advance-clock 10)`)
+}
+
+func TestSharedSandbox(t *testing.T) {
+	as := assert.New(t)
+
+	ns := bootstrap.NewSandbox().
+		DenyGroup(bootstrap.IO).
+		Share(bootstrap.NewShared()).
+		Environment().
+		GetAnonymous()
+
+	as.Number(6, eval.String(ns, "(+ 1 2 3)"))
+	denied(as, ns, `(println "hello")`)
+}
+
+func BenchmarkSharedEnvironment(b *testing.B) {
+	s := bootstrap.NewShared()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		s.Environment()
+	}
+}
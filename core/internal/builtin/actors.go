@@ -0,0 +1,272 @@
+package builtin
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/actor"
+)
+
+// Error messages
+const (
+	ErrNotProcess      = "value is not a process: %s"
+	ErrNotMailbox      = "value is not a mailbox: %s"
+	ErrNotSupervisor   = "process is not a supervisor: %s"
+	ErrUnknownStrategy = "unknown supervision strategy: %s"
+	ErrUnknownRestart  = "unknown restart type: %s"
+	ErrChildStart      = "child spec requires a start function: %s"
+	ErrInvalidName     = "process name must be a keyword: %s"
+)
+
+// Supervisor and child spec keys
+const (
+	StrategyKey  = data.Keyword("strategy")
+	IntensityKey = data.Keyword("intensity")
+	PeriodKey    = data.Keyword("period")
+	IDKey        = data.Keyword("id")
+	StartKey     = data.Keyword("start")
+	RestartKey   = data.Keyword("restart")
+)
+
+var strategies = map[data.Keyword]actor.Strategy{
+	"one-for-one": actor.OneForOne,
+	"one-for-all": actor.OneForAll,
+}
+
+var restarts = map[data.Keyword]actor.Restart{
+	"permanent": actor.Permanent,
+	"transient": actor.Transient,
+	"temporary": actor.Temporary,
+}
+
+// Spawn starts an actor. The provided function is called with the
+// actor's mailbox, a lazy sequence of the messages it receives. If a
+// monitor function is provided, it is called with any error that the
+// actor raises. Returns the actor's process, which can be called to
+// send it messages
+var Spawn = data.Applicative(func(args ...data.Value) data.Value {
+	fn := args[0].(data.Function)
+	size := 16
+	if len(args) > 1 {
+		size = int(args[1].(data.Integer))
+	}
+	var monitor data.Function
+	if len(args) > 2 {
+		monitor = args[2].(data.Function)
+	}
+	return actor.Spawn(func(p *actor.Process) {
+		if monitor != nil {
+			defer func() {
+				if rec := recover(); rec != nil {
+					monitor.Call(recoveredError(rec))
+					panic(rec)
+				}
+			}()
+		}
+		fn.Call(p.Mailbox())
+	}, size)
+}, 1, 3)
+
+// Self returns the process that owns the provided mailbox
+var Self = data.Applicative(func(args ...data.Value) data.Value {
+	if m, ok := args[0].(*actor.Mailbox); ok {
+		return m.Process()
+	}
+	panic(fmt.Errorf(ErrNotMailbox, args[0]))
+}, 1)
+
+// Link links two processes so that when either exits, the other
+// receives an exit signal
+var Link = data.Applicative(func(args ...data.Value) data.Value {
+	actor.Link(process(args[0]), process(args[1]))
+	return data.Nil
+}, 2)
+
+// Unlink removes the link between two processes
+var Unlink = data.Applicative(func(args ...data.Value) data.Value {
+	actor.Unlink(process(args[0]), process(args[1]))
+	return data.Nil
+}, 2)
+
+// Monitor arranges for the first process to receive a :down message
+// when the second process exits
+var Monitor = data.Applicative(func(args ...data.Value) data.Value {
+	actor.Monitor(process(args[0]), process(args[1]))
+	return data.Nil
+}, 2)
+
+// Demonitor stops the first process from monitoring the second
+var Demonitor = data.Applicative(func(args ...data.Value) data.Value {
+	actor.Demonitor(process(args[0]), process(args[1]))
+	return data.Nil
+}, 2)
+
+// TrapExits sets whether a process receives exit signals from linked
+// processes as :exit messages, rather than being killed by them
+var TrapExits = data.Applicative(func(args ...data.Value) data.Value {
+	trap := len(args) == 1 || data.Truthy(args[1])
+	process(args[0]).TrapExits(trap)
+	return data.Nil
+}, 1, 2)
+
+// Exit sends an exit signal with the provided reason to a process
+var Exit = data.Applicative(func(args ...data.Value) data.Value {
+	process(args[0]).Signal(nil, args[1])
+	return data.Nil
+}, 2)
+
+// IsAlive returns whether a process has yet to exit
+var IsAlive = data.Applicative(func(args ...data.Value) data.Value {
+	return data.Bool(process(args[0]).IsAlive())
+}, 1)
+
+// IsProcess returns whether the provided value is a process
+var IsProcess = data.Applicative(func(args ...data.Value) data.Value {
+	_, ok := args[0].(*actor.Process)
+	return data.Bool(ok)
+}, 1)
+
+// Register associates a name with a process until it exits
+var Register = data.Applicative(func(args ...data.Value) data.Value {
+	if err := actor.Register(processName(args[0]), process(args[1])); err != nil {
+		panic(err)
+	}
+	return args[1]
+}, 2)
+
+// Unregister removes a name's association with a process, returning
+// whether it had one
+var Unregister = data.Applicative(func(args ...data.Value) data.Value {
+	return data.Bool(actor.Unregister(processName(args[0])))
+}, 1)
+
+// Whereis returns the process that is registered with a name, or nil
+var Whereis = data.Applicative(func(args ...data.Value) data.Value {
+	if p, ok := actor.Whereis(processName(args[0])); ok {
+		return p
+	}
+	return data.Nil
+}, 1)
+
+// Registered returns the names that are currently registered
+var Registered = data.Applicative(func(_ ...data.Value) data.Value {
+	names := actor.Registered()
+	res := make(data.Values, len(names))
+	for i, n := range names {
+		res[i] = n
+	}
+	return data.NewVector(res...)
+}, 0)
+
+// Supervisor starts a supervisor process for the provided child specs.
+// Each spec is an object with an :id, a :start function that returns
+// a process, and an optional :restart type. The optional options
+// object may include a :strategy, :intensity and :period
+var Supervisor = data.Applicative(func(args ...data.Value) data.Value {
+	spec := actor.SupervisorSpec{
+		Strategy:  actor.OneForOne,
+		Intensity: actor.DefaultIntensity,
+		Period:    actor.DefaultPeriod,
+	}
+	children := args[len(args)-1].(data.Sequence)
+	if len(args) == 2 {
+		supervisorOptions(&spec, args[0].(data.Object))
+	}
+	for f, r, ok := children.Split(); ok; f, r, ok = r.Split() {
+		spec.Children = append(spec.Children, childSpec(f))
+	}
+	p, reason := actor.NewSupervisor(spec)
+	if reason != nil {
+		panic(recoveredPanic(reason))
+	}
+	return p
+}, 1, 2)
+
+// SupervisorChildren returns a vector of the [id process] pairs of a
+// supervisor's running children
+var SupervisorChildren = data.Applicative(func(args ...data.Value) data.Value {
+	c, ok := actor.Children(process(args[0]))
+	if !ok {
+		panic(fmt.Errorf(ErrNotSupervisor, args[0]))
+	}
+	res := make(data.Values, len(c))
+	for i, e := range c {
+		res[i] = data.NewVector(e.ID, e.Process)
+	}
+	return data.NewVector(res...)
+}, 1)
+
+func supervisorOptions(spec *actor.SupervisorSpec, o data.Object) {
+	if v, ok := o.Get(StrategyKey); ok {
+		s, ok := strategies[v.(data.Keyword)]
+		if !ok {
+			panic(fmt.Errorf(ErrUnknownStrategy, v))
+		}
+		spec.Strategy = s
+	}
+	if v, ok := o.Get(IntensityKey); ok {
+		spec.Intensity = int(v.(data.Integer))
+	}
+	if v, ok := o.Get(PeriodKey); ok {
+		spec.Period = duration(v)
+	}
+}
+
+func childSpec(v data.Value) actor.ChildSpec {
+	o := v.(data.Object)
+	id, _ := o.Get(IDKey)
+	start, ok := o.Get(StartKey)
+	if !ok {
+		panic(fmt.Errorf(ErrChildStart, v))
+	}
+	fn := start.(data.Function)
+	res := actor.ChildSpec{
+		ID: id,
+		Start: func() *actor.Process {
+			return process(fn.Call())
+		},
+	}
+	if r, ok := o.Get(RestartKey); ok {
+		rt, ok := restarts[r.(data.Keyword)]
+		if !ok {
+			panic(fmt.Errorf(ErrUnknownRestart, r))
+		}
+		res.Restart = rt
+	}
+	return res
+}
+
+func process(v data.Value) *actor.Process {
+	if p, ok := v.(*actor.Process); ok {
+		return p
+	}
+	panic(fmt.Errorf(ErrNotProcess, v))
+}
+
+func processName(v data.Value) data.Keyword {
+	if k, ok := v.(data.Keyword); ok {
+		return k
+	}
+	panic(fmt.Errorf(ErrInvalidName, v))
+}
+
+// recoveredError converts a value recovered from a panic into the value
+// that a recover function is called with
+func recoveredError(rec interface{}) data.Value {
+	if err, ok := rec.(data.Error); ok {
+		return err
+	}
+	if err, ok := rec.(error); ok {
+		return data.String(err.Error())
+	}
+	return rec.(data.Value)
+}
+
+// recoveredPanic converts an exit reason back into a value to raise
+func recoveredPanic(reason data.Value) interface{} {
+	if err, ok := reason.(data.Error); ok {
+		return err
+	}
+	return errors.New(reason.String())
+}
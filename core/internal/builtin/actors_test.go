@@ -0,0 +1,133 @@
+package builtin_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kode4food/ale/core/internal/builtin"
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+)
+
+const forwarder = `
+	(define (forward out)
+	  (spawn (lambda (mbox)
+	           (for-each [m mbox] ((:emit out) m)))))
+`
+
+func TestSpawnEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`
+		(let* ([out (chan)]
+		       [p   (spawn (lambda (mbox)
+		                     ((:emit out) (eq (self mbox) (first mbox)))))])
+		  (p p)
+		  [(first (:seq out)) (process? p) (process? out)])
+	`, S("[#t #t #f]"))
+	as.EvalTo(`
+		(let* ([out (chan)]
+		       [p   (spawn (lambda (mbox) (raise "boom"))
+		                   4
+		                   (lambda (err) ((:emit out) err)))])
+		  (first (:seq out)))
+	`, S("boom"))
+}
+
+func TestLinkEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(forwarder+`
+		(let* ([out  (chan)]
+		       [w    (forward out)]
+		       [a    (spawn (lambda (mbox) (first mbox)))]
+		       [b    (spawn (lambda (mbox) (first mbox)))]
+		       [_    (monitor w b)]
+		       [_    (link a b)]
+		       [_    (exit a :crashed)]
+		       [down (first (:seq out))])
+		  [(:type down) (eq (:process down) b) (:reason down) (alive? a)])
+	`, S("[:down #t :crashed #f]"))
+	as.EvalTo(forwarder+`
+		(let* ([out (chan)]
+		       [w   (forward out)]
+		       [a   (spawn (lambda (mbox) (raise "oops")) 0)])
+		  (trap-exits w)
+		  (link w a)
+		  (let [msg (first (:seq out))]
+		    [(:type msg) (:reason msg) (alive? w)]))
+	`, S(`[:exit "oops" #t]`))
+}
+
+func TestRegistryEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`
+		(let [p (spawn (lambda (mbox) (first mbox)))]
+		  (register :registry-eval p)
+		  (let [found (eq p (whereis :registry-eval))]
+		    (unregister :registry-eval)
+		    [found (whereis :registry-eval)]))
+	`, S("[#t ()]"))
+	as.PanicWith(`
+		(let [p (spawn (lambda (mbox) (first mbox)))]
+		  (register :registry-dup p)
+		  (register :registry-dup p))
+	`, fmt.Errorf("name is already registered: :registry-dup"))
+}
+
+func TestSupervisorEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(forwarder+`
+		(define (worker mbox)
+		  (if (eq (first mbox) :crash)
+		      (raise "crashed")
+		      (worker (rest mbox))))
+
+		(let* ([out  (chan)]
+		       [w    (forward out)]
+		       [sup  (supervisor
+		               {:strategy :one-for-all :intensity 5}
+		               [{:id :a :start (lambda () (spawn worker))}
+		                {:id :b :start (lambda () (spawn worker))}])]
+		       [old  (supervisor-children sup)]
+		       [_    (monitor w ((old 0) 1))]
+		       [_    (((old 1) 1) :crash)]
+		       [down (first (:seq out))]
+		       [new  ((lambda-rec restarted ()
+		                (let [c (supervisor-children sup)]
+		                  (if (and (= 2 (length c))
+		                           (not (eq ((old 0) 1) ((c 0) 1))))
+		                      c
+		                      (begin (sleep 1) (restarted))))))])
+		  (exit sup :kill)
+		  [(:reason down)
+		   (seq->vector (map (lambda (c) (c 0)) new))
+		   (alive? ((old 1) 1))])
+	`, S("[:shutdown [:a :b] #f]"))
+	as.EvalTo(forwarder+`
+		(let* ([out (chan)]
+		       [w   (forward out)]
+		       [sup (supervisor
+		              {:intensity 0}
+		              [{:id :a :start (lambda () (spawn (lambda (m) (raise "x"))))}])])
+		  (monitor w sup)
+		  (:reason (first (:seq out))))
+	`, K("restart-limit"))
+}
+
+func TestActorErrors(t *testing.T) {
+	as := assert.New(t)
+	as.PanicWith(`(link 1 2)`, fmt.Errorf(builtin.ErrNotProcess, I(1)))
+	as.PanicWith(`(self [])`, fmt.Errorf(builtin.ErrNotMailbox, data.EmptyVector))
+	as.PanicWith(`(whereis "name")`, fmt.Errorf(builtin.ErrInvalidName, `name`))
+	as.PanicWith(`(supervisor {:strategy :all-for-none} [])`,
+		fmt.Errorf(builtin.ErrUnknownStrategy, ":all-for-none"),
+	)
+	as.PanicWith(`(supervisor [{:id :a :restart :sometimes :start +}])`,
+		fmt.Errorf(builtin.ErrUnknownRestart, ":sometimes"),
+	)
+	as.PanicWith(`(supervisor [{:id :a}])`,
+		fmt.Errorf(builtin.ErrChildStart, "{:id :a}"),
+	)
+	defer as.ExpectPanic("process is not a supervisor")
+	as.Eval(`(supervisor-children (spawn first))`)
+}
@@ -8,8 +8,12 @@ import (
 	"github.com/kode4food/ale/read"
 )
 
-// Raise will cause a panic
+// Raise will cause a panic. If an object is raised, the panic will be
+// a structured error
 var Raise = data.Applicative(func(args ...data.Value) data.Value {
+	if o, ok := args[0].(data.Object); ok {
+		panic(data.MakeError(o))
+	}
 	err := args[0].(data.String)
 	panic(errors.New(string(err)))
 }, 1)
@@ -21,6 +25,10 @@ var Recover = data.Applicative(func(args ...data.Value) (res data.Value) {
 
 	defer func() {
 		if rec := recover(); rec != nil {
+			if err, ok := rec.(data.Error); ok {
+				res = rescue.Call(err)
+				return
+			}
 			err := rec.(error).Error()
 			res = rescue.Call(data.String(err))
 		}
@@ -29,6 +37,12 @@ var Recover = data.Applicative(func(args ...data.Value) (res data.Value) {
 	return body.Call()
 }, 2)
 
+// IsError returns whether the provided value is a structured error
+var IsError = data.Applicative(func(args ...data.Value) data.Value {
+	_, ok := args[0].(data.Error)
+	return data.Bool(ok)
+}, 1)
+
 // Defer invokes a cleanup function, no matter what has happened
 var Defer = data.Applicative(func(args ...data.Value) (res data.Value) {
 	body := args[0].(data.Function)
@@ -60,6 +60,25 @@ func TestRecover(t *testing.T) {
 	as.True(triggered)
 }
 
+func TestRaiseError(t *testing.T) {
+	as := assert.New(t)
+	var triggered = false
+	builtin.Recover.Call(
+		data.Applicative(func(_ ...data.Value) data.Value {
+			builtin.Raise.Call(O(C(K("type"), K("bad")), C(K("message"), S("no"))))
+			return S("wrong")
+		}, 0),
+		data.Applicative(func(args ...data.Value) data.Value {
+			err := args[0].(data.Error)
+			as.String("no", err.Error())
+			as.Equal(K("bad"), as.MustGet(err, data.TypeKey))
+			triggered = true
+			return data.Nil
+		}, 1),
+	)
+	as.True(triggered)
+}
+
 func TestDefer(t *testing.T) {
 	as := assert.New(t)
 	var triggered = false
@@ -0,0 +1,117 @@
+package builtin
+
+import (
+	"fmt"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/stream"
+)
+
+// Error messages
+const (
+	ErrUnknownBufferPolicy = "unknown buffer policy: %s"
+	ErrDroppingBufferSize  = "policy %s requires a positive buffer size"
+	ErrNotPublication      = "can't subscribe to a topic of a mult: %s"
+)
+
+var bufferPolicies = map[data.Keyword]stream.BufferPolicy{
+	"block":       stream.Block,
+	"drop-oldest": stream.DropOldest,
+	"drop-newest": stream.DropNewest,
+}
+
+// Mult returns a broadcast that distributes every value of a channel
+// or sequence to all of the channels that tap into it
+var Mult = data.Applicative(func(args ...data.Value) data.Value {
+	return stream.NewMult(channelSource(args[0]))
+}, 1)
+
+// Tap returns a new channel that receives every value of a broadcast.
+// A buffer size and policy may be provided for the channel
+var Tap = data.Applicative(func(args ...data.Value) data.Value {
+	b := args[0].(*stream.Broadcast)
+	size, p := bufferArgs(args[1:])
+	return makeReadChannel(b.Subscribe(nil, size, p))
+}, 1, 3)
+
+// Publish returns a broadcast that distributes the values of a channel or
+// sequence to the subscribers of their topic. The topic of a value is
+// the result of calling the provided function with it
+var Publish = data.Applicative(func(args ...data.Value) data.Value {
+	src := channelSource(args[0])
+	topic := args[1].(data.Function)
+	return stream.NewPub(src, func(v data.Value) data.Value {
+		return topic.Call(v)
+	})
+}, 2)
+
+// Subscribe returns a new channel that receives the values of a publication
+// that belong to a topic. A buffer size and policy may be provided for
+// the channel
+var Subscribe = data.Applicative(func(args ...data.Value) data.Value {
+	b := args[0].(*stream.Broadcast)
+	if !b.HasTopics() {
+		panic(fmt.Errorf(ErrNotPublication, b))
+	}
+	size, p := bufferArgs(args[2:])
+	return makeReadChannel(b.Subscribe(args[1], size, p))
+}, 2, 4)
+
+// MergeChan returns a channel that receives the values of several
+// channels or sequences as they become available
+var MergeChan = data.Applicative(func(args ...data.Value) data.Value {
+	sources := make([]data.Sequence, len(args))
+	for i, a := range args {
+		sources[i] = channelSource(a)
+	}
+	return makeReadChannel(stream.Merge(0, sources...))
+}, 1, data.OrMore)
+
+// SplitChan returns a vector of two channels. Values of a channel or
+// sequence for which a predicate returns true are written to the
+// first, and the rest are written to the second
+var SplitChan = data.Applicative(func(args ...data.Value) data.Value {
+	pred := args[0].(data.Function)
+	src := channelSource(args[1])
+	size, p := bufferArgs(args[2:])
+	te, ts, fe, fs := stream.Split(src, func(v data.Value) bool {
+		return data.Truthy(pred.Call(v))
+	}, size, p)
+	return data.NewVector(makeReadChannel(te, ts), makeReadChannel(fe, fs))
+}, 2, 4)
+
+func channelSource(v data.Value) data.Sequence {
+	if m, ok := v.(data.Mapped); ok {
+		if s, ok := m.Get(stream.SequenceKey); ok {
+			return s.(data.Sequence)
+		}
+	}
+	return v.(data.Sequence)
+}
+
+func bufferArgs(args data.Values) (int, stream.BufferPolicy) {
+	var size int
+	if len(args) > 0 {
+		size = int(args[0].(data.Integer))
+	}
+	if len(args) < 2 {
+		return size, stream.Block
+	}
+	k := args[1].(data.Keyword)
+	p, ok := bufferPolicies[k]
+	if !ok {
+		panic(fmt.Errorf(ErrUnknownBufferPolicy, k))
+	}
+	if p != stream.Block && size <= 0 {
+		panic(fmt.Errorf(ErrDroppingBufferSize, k))
+	}
+	return size, p
+}
+
+func makeReadChannel(e stream.Emitter, s data.Sequence) data.Object {
+	return data.NewObject(
+		data.NewCons(data.TypeKey, stream.ChannelType),
+		data.NewCons(stream.CloseKey, bindCloser(e)),
+		data.NewCons(stream.SequenceKey, s),
+	)
+}
@@ -0,0 +1,71 @@
+package builtin_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kode4food/ale/core/internal/builtin"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+)
+
+func TestMultEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`
+		(let* ([ch (chan)]
+		       [m  (mult ch)]
+		       [t1 (tap m 10)]
+		       [t2 (tap m 10)])
+		  (go ((:emit ch) 1 2 3)
+		      ((:close ch)))
+		  [(seq->vector (:seq t1)) (seq->vector (:seq t2))])
+	`, S("[[1 2 3] [1 2 3]]"))
+	as.EvalTo(`
+		(let* ([ch   (chan)]
+		       [m    (mult ch)]
+		       [slow (tap m 2 :drop-oldest)]
+		       [fast (tap m)])
+		  (go (apply (:emit ch) (range 10))
+		      ((:close ch)))
+		  (let* ([f (seq->vector (:seq fast))]
+		         [s (seq->vector (:seq slow))])
+		    [f s]))
+	`, S("[[0 1 2 3 4 5 6 7 8 9] [8 9]]"))
+}
+
+func TestPubSubEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`
+		(let* ([ch    (chan)]
+		       [p     (pub ch :topic)]
+		       [news  (sub p :news 10)]
+		       [sport (sub p :sport 10 :drop-newest)])
+		  (go ((:emit ch) {:topic :news :id 1}
+		                  {:topic :sport :id 2}
+		                  {:topic :news :id 3})
+		      ((:close ch)))
+		  [(seq->vector (map :id (:seq news)))
+		   (seq->vector (map :id (:seq sport)))])
+	`, S("[[1 3] [2]]"))
+}
+
+func TestMergeSplitEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`
+		(reduce + (:seq (merge-chan (generate (emit 1) (emit 2)) [3 4])))
+	`, F(10))
+	as.EvalTo(`
+		(let [res (split-chan even? (range 6) 6)]
+		  [(seq->vector (:seq (res 0))) (seq->vector (:seq (res 1)))])
+	`, S("[[0 2 4] [1 3 5]]"))
+}
+
+func TestBroadcastErrors(t *testing.T) {
+	as := assert.New(t)
+	as.PanicWith(`(tap (mult []) 0 :blah)`,
+		fmt.Errorf(builtin.ErrUnknownBufferPolicy, K("blah")))
+	as.PanicWith(`(tap (mult []) 0 :drop-oldest)`,
+		fmt.Errorf(builtin.ErrDroppingBufferSize, K("drop-oldest")))
+	defer as.ExpectPanic(fmt.Sprintf(builtin.ErrNotPublication, ""))
+	as.Eval(`(sub (mult []) :topic)`)
+}
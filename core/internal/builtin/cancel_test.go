@@ -0,0 +1,75 @@
+package builtin_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kode4food/ale/core/internal/builtin"
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+)
+
+func TestGenerateCancelEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`
+		(let* ([done (chan 1)]
+		       [gen  (generate
+		               (recover
+		                 (lambda ()
+		                   ((lambda-rec count (i) (emit i) (count (inc i))) 0))
+		                 (lambda (e)
+		                   ((:emit done) (:type e))
+		                   (raise e))))])
+		  (let [res (seq->vector (take 3 gen))]
+		    (cancel (rest (rest (rest gen))))
+		    [res (first (:seq done))]))
+	`, S("[[0 1 2] :cancelled]"))
+	as.EvalTo(`
+		(let [gen (generate (emit 1 2))]
+		  (first gen)
+		  (cancel (rest gen))
+		  (cancelled? (rest gen)))
+	`, data.True)
+}
+
+func TestScopeEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`
+		(let* ([parent (scope)]
+		       [child  (scope parent)]
+		       [before (cancelled? child)])
+		  (cancel parent)
+		  [before (cancelled? child)])
+	`, S("[#f #t]"))
+	as.EvalTo(`
+		(let* ([parent (scope)]
+		       [done   (chan)]
+		       [task   (go-in [s parent]
+		                 ((:emit done) :started)
+		                 (select [[_ s] ((:emit done) :stopped)]))]
+		       [res    (:seq done)])
+		  (first res)
+		  (cancel parent)
+		  [(first res) (first (rest res)) (cancelled? task)])
+	`, S("[:started :stopped #t]"))
+	as.EvalTo(`
+		(let* ([parent (scope)]
+		       [task   (go-in [s parent] :finished)])
+		  (select [[_ task] [(cancelled? task) (cancelled? parent)]]))
+	`, S("[#t #f]"))
+	as.EvalTo(`
+		(let* ([s   (scope)]
+		       [gen (generate-in s (emit 1 2 3))])
+		  (first gen)
+		  (cancel s)
+		  (cancelled? gen))
+	`, data.True)
+	as.EvalTo(`
+		(let [s (scope)]
+		  (cancel s)
+		  (recover (lambda () (check-cancelled s))
+		           (lambda (e) (:type e))))
+	`, K("cancelled"))
+	as.PanicWith(`(cancel 99)`, fmt.Errorf(builtin.ErrNotCancellable, I(99)))
+}
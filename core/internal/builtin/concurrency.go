@@ -1,20 +1,101 @@
 package builtin
 
 import (
+	"fmt"
+	"sync"
+
 	"github.com/kode4food/ale/data"
 	"github.com/kode4food/ale/internal/async"
 	"github.com/kode4food/ale/internal/stream"
+	"github.com/kode4food/ale/runtime/vm"
+)
+
+// Error messages
+const (
+	ErrNotCancellable = "value can't be cancelled: %s"
 )
 
-// Go runs the provided function asynchronously
+// Go runs the provided function asynchronously. If the function is
+// unwound by a cancellation, it exits quietly. Any other error that it
+// raises is reported to the handler of the Monitor that runs it, if
+// there is one, and otherwise crashes the process
 var Go = data.Applicative(func(args ...data.Value) data.Value {
 	fn := args[0].(data.Function)
 	restArgs := args[1:]
-	go fn.Call(restArgs...)
+	go func() {
+		defer func() {
+			if rec := recover(); rec != nil && !stream.IsCancelledError(rec) {
+				vm.ReportError(fn, rec)
+			}
+		}()
+		fn.Call(restArgs...)
+	}()
+	return data.Nil
+}, 1)
+
+// Generate runs a producer function asynchronously, returning the
+// sequence of values that it emits. The producer is called with an
+// emit function that raises a cancellation error once the sequence has
+// been cancelled or abandoned, or once the optional scope is cancelled
+var Generate = data.Applicative(func(args ...data.Value) data.Value {
+	var parent *stream.Scope
+	if len(args) == 2 {
+		parent = args[0].(*stream.Scope)
+		args = args[1:]
+	}
+	producer := args[0].(data.Function)
+	return stream.Generate(parent, func(emit stream.EmitFunc) {
+		producer.Call(data.Applicative(func(args ...data.Value) data.Value {
+			for _, f := range args {
+				emit(f)
+			}
+			return data.Nil
+		}))
+	})
+}, 1, 2)
+
+// Scope returns a new cancellation scope. If a parent scope is
+// provided, the new scope is cancelled along with it
+var Scope = data.Applicative(func(args ...data.Value) data.Value {
+	if len(args) == 0 {
+		return stream.NewScope(nil)
+	}
+	return stream.NewScope(args[0].(*stream.Scope))
+}, 0, 1)
+
+// Cancel cancels a scope, or the sequence of a generator or channel
+var Cancel = data.Applicative(func(args ...data.Value) data.Value {
+	canceller(args[0]).Cancel()
 	return data.Nil
 }, 1)
 
-// Chan instantiates a new go channel
+// IsCancelled returns whether a scope, or the sequence of a generator
+// or channel, has been cancelled
+var IsCancelled = data.Applicative(func(args ...data.Value) data.Value {
+	return data.Bool(canceller(args[0]).IsCancelled())
+}, 1)
+
+// CheckCancelled raises a cancellation error if the provided scope has
+// been cancelled
+var CheckCancelled = data.Applicative(func(args ...data.Value) data.Value {
+	args[0].(*stream.Scope).Check()
+	return data.Nil
+}, 1)
+
+func canceller(v data.Value) stream.Canceller {
+	if m, ok := v.(data.Mapped); ok {
+		if s, ok := m.Get(stream.SequenceKey); ok {
+			v = s
+		}
+	}
+	if c, ok := v.(stream.Canceller); ok {
+		return c
+	}
+	panic(fmt.Errorf(ErrNotCancellable, v))
+}
+
+// Chan instantiates a new go channel. If a transducer is provided,
+// emitted values are transformed by it before reaching the sequence
 var Chan = data.Applicative(func(args ...data.Value) data.Value {
 	var size int
 	if len(args) != 0 {
@@ -22,19 +103,160 @@ var Chan = data.Applicative(func(args ...data.Value) data.Value {
 	}
 	e, s := stream.NewChannel(size)
 
+	var emit, closer data.Function
+	if len(args) > 1 {
+		emit, closer = bindTransducer(e, args[1].(data.Function))
+	} else {
+		emit, closer = bindSelectableWriter(e), bindCloser(e)
+	}
+	return makeChannel(emit, closer, s)
+}, 0, 2)
+
+func makeChannel(emit, closer data.Function, s data.Sequence) data.Object {
 	return data.NewObject(
 		data.NewCons(data.TypeKey, stream.ChannelType),
-		data.NewCons(stream.EmitKey, bindWriter(e)),
-		data.NewCons(stream.CloseKey, bindCloser(e)),
+		data.NewCons(stream.EmitKey, emit),
+		data.NewCons(stream.CloseKey, closer),
 		data.NewCons(stream.SequenceKey, s),
 	)
-}, 0, 1)
+}
 
-// Promise instantiates a new eventually-fulfilled promise
+// transducedChannel applies a transducer to the values that are
+// emitted to a channel. The transducer's state is guarded by a mutex,
+// but its outputs are written to the channel after the mutex has been
+// released, by one emitter at a time, so that a write that's blocked
+// by an idle reader never holds up closing the channel
+type transducedChannel struct {
+	sync.Mutex
+	cond      *sync.Cond
+	emitter   stream.Emitter
+	reducer   data.Function
+	pending   data.Values
+	writing   bool
+	completed bool
+	closed    bool
+}
+
+func bindTransducer(
+	e stream.Emitter, xform data.Function,
+) (data.Function, data.Function) {
+	c := &transducedChannel{emitter: e}
+	c.cond = sync.NewCond(c)
+	c.reducer = xform.Call(data.Applicative(func(args ...data.Value) data.Value {
+		if len(args) == 2 {
+			c.pending = append(c.pending, args[1])
+		}
+		return data.Nil
+	}, 0, 2)).(data.Function)
+
+	emit := data.Applicative(func(args ...data.Value) data.Value {
+		c.emit(args)
+		return data.Nil
+	})
+
+	closer := data.Applicative(func(_ ...data.Value) data.Value {
+		c.close()
+		return data.Nil
+	}, 0)
+
+	return emit, closer
+}
+
+func (c *transducedChannel) emit(args data.Values) {
+	c.Lock()
+	for _, f := range args {
+		if c.completed {
+			break
+		}
+		if _, ok := c.reduce(data.Nil, f).(*reduced); ok {
+			c.complete()
+		}
+	}
+	for c.writing {
+		c.cond.Wait()
+	}
+	c.drain()
+}
+
+func (c *transducedChannel) close() {
+	c.Lock()
+	c.complete()
+	if c.writing {
+		// the emitter that's writing will close the channel once its
+		// writes, and those of the completion, are done
+		c.Unlock()
+		return
+	}
+	c.drain()
+}
+
+// reduce calls the reducer, releasing the mutex if it raises an error
+func (c *transducedChannel) reduce(args ...data.Value) data.Value {
+	ok := false
+	defer func() {
+		if !ok {
+			c.Unlock()
+		}
+	}()
+	res := c.reducer.Call(args...)
+	ok = true
+	return res
+}
+
+func (c *transducedChannel) complete() {
+	if !c.completed {
+		c.completed = true
+		c.reduce(data.Nil)
+	}
+}
+
+// drain writes the pending outputs to the channel, closing it once the
+// transducer has completed. It's called with the mutex held, and
+// releases it
+func (c *transducedChannel) drain() {
+	c.writing = true
+	defer func() {
+		c.writing = false
+		c.cond.Broadcast()
+		c.Unlock()
+	}()
+	for len(c.pending) != 0 {
+		pending := c.pending
+		c.pending = nil
+		c.Unlock()
+		c.write(pending)
+		c.Lock()
+	}
+	if c.completed && !c.closed {
+		c.closed = true
+		c.emitter.Close()
+	}
+}
+
+// write is called without the mutex held, and reacquires it if a write
+// raises an error, so that drain can release it
+func (c *transducedChannel) write(values data.Values) {
+	ok := false
+	defer func() {
+		if !ok {
+			c.Lock()
+		}
+	}()
+	for _, v := range values {
+		c.emitter.Write(v)
+	}
+	ok = true
+}
+
+// Promise instantiates a new eventually-fulfilled promise. If no
+// resolver is provided, the promise must be resolved with deliver
 var Promise = data.Applicative(func(args ...data.Value) data.Value {
+	if len(args) == 0 {
+		return async.NewDeliverable()
+	}
 	resolver := args[0].(data.Function)
 	return async.NewPromise(resolver)
-}, 1)
+}, 0, 1)
 
 // IsPromise returns whether the specified value is a promise
 var IsPromise = data.Applicative(func(args ...data.Value) data.Value {
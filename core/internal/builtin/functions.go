@@ -4,6 +4,7 @@ import (
 	"github.com/kode4food/ale/compiler/encoder"
 	"github.com/kode4food/ale/data"
 	"github.com/kode4food/ale/internal/sequence"
+	"github.com/kode4food/ale/runtime/disasm"
 )
 
 // Apply performs a parameterized function call
@@ -30,3 +31,13 @@ var IsSpecial = data.Applicative(func(args ...data.Value) data.Value {
 	_, ok := args[0].(encoder.Call)
 	return data.Bool(ok)
 }, 1)
+
+// Disassemble returns a listing of the code that was compiled for a
+// function, both before and after it was optimized
+var Disassemble = data.Applicative(func(args ...data.Value) data.Value {
+	res, err := disasm.Value(args[0])
+	if err != nil {
+		panic(err)
+	}
+	return data.String(res)
+}, 1)
@@ -12,6 +12,8 @@ import (
 	"github.com/kode4food/ale/internal/assert"
 	. "github.com/kode4food/ale/internal/assert/helpers"
 	"github.com/kode4food/ale/read"
+	"github.com/kode4food/ale/runtime/disasm"
+	"github.com/kode4food/ale/runtime/vm"
 )
 
 func interfaceErr(concrete, expected string) error {
@@ -140,3 +142,25 @@ func TestTailCallEval(t *testing.T) {
 		(to-zero 9999999)
 	`, I(0))
 }
+
+func TestDisassembleEval(t *testing.T) {
+	as := assert.New(t)
+	defer vm.RetainSource(vm.RetainSource(true))
+
+	res := as.Eval(`
+		(define (adder x) (lambda (y) (+ x y)))
+		(disassemble* (adder 1))
+	`)
+	as.Contains("closure cells:\n    0: 1\n", res)
+	as.Contains("    0014  Const(1)             ; ale/+\n", res)
+	as.Contains("pre-optimization:\n", res)
+	as.Contains("    0016  TailCall(2)\n", res)
+
+	err := fmt.Errorf(disasm.ErrNotLambda, "99")
+	as.PanicWith(`(disassemble* 99)`, err)
+
+	vm.RetainSource(false)
+	res = as.Eval(`(disassemble* (lambda (x) (* x 2)))`)
+	as.NotContains("pre-optimization:\n", res)
+	as.Contains("  code:\n", res)
+}
@@ -133,3 +133,74 @@ func TestForEachLoopEval(t *testing.T) {
 			(reduce (lambda (x y) (+ x y)) seq))
 	`, F(250))
 }
+
+func TestGeneratorsEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`(seq->vector (take 5 (iterate inc 0)))`, S("[0 1 2 3 4]"))
+	as.EvalTo(`(seq->vector (take 3 (repeat :x)))`, S("[:x :x :x]"))
+	as.EvalTo(`(seq->vector (repeat 2 :y))`, S("[:y :y]"))
+	as.EvalTo(`(seq->vector (take 5 (cycle [1 2])))`, S("[1 2 1 2 1]"))
+	as.EvalTo(`(seq->vector (cycle []))`, S("[]"))
+	as.EvalTo(`
+		(seq->vector (take 6 (interleave (range) (repeat :x))))
+	`, S("[0 :x 1 :x 2 :x]"))
+	as.EvalTo(`(seq->vector (interleave [1 2 3] [:a :b]))`, S("[1 :a 2 :b]"))
+	as.EvalTo(`(seq->vector (interleave))`, S("[]"))
+	as.EvalTo(`
+		(seq->vector (take 5 (interpose :s (range))))
+	`, S("[0 :s 1 :s 2]"))
+}
+
+func TestFilteringEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`
+		(seq->vector (take 3 (distinct (cycle [1 2 2 3]))))
+	`, S("[1 2 3]"))
+	as.EvalTo(`
+		(seq->vector (take 3 (dedupe (cycle [1 1 2]))))
+	`, S("[1 2 1]"))
+	as.EvalTo(`
+		(seq->vector (take 3 (keep (lambda (x) (when (even? x) (* x 10)))
+		                           (range))))
+	`, S("[0 20 40]"))
+	as.EvalTo(`
+		(seq->vector (take 3 (drop-while (lambda (x) (< x 5)) (range))))
+	`, S("[5 6 7]"))
+	as.EvalTo(`
+		(seq->vector
+		  (take 5 (flatten (map (lambda (x) [x [x "ab" {:a 1}]]) (range)))))
+	`, S(`[0 0 "ab" {:a 1} 1]`))
+	as.EvalTo(`(seq->vector (flatten '(1 (2 [3 (4)]) 5)))`, S("[1 2 3 4 5]"))
+}
+
+func TestPartitioningEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`
+		(seq->vector
+		  (take 3 (partition-by (lambda (x) (< (mod x 6) 3)) (range))))
+	`, S("[(0 1 2) (3 4 5) (6 7 8)]"))
+	as.EvalTo(`
+		(seq->vector (take 2 (partition-all 2 (range))))
+	`, S("[(0 1) (2 3)]"))
+	as.EvalTo(`(seq->vector (partition-all 2 [1 2 3]))`, S("[(1 2) (3)]"))
+	as.EvalTo(`
+		(let [s (split-at 2 (range))]
+		  [(seq->vector (s 0)) (seq->vector (take 2 (s 1)))])
+	`, S("[[0 1] [2 3]]"))
+	as.EvalTo(`
+		(let [s (split-with (lambda (x) (< x 3)) (range))]
+		  [(seq->vector (s 0)) (seq->vector (take 2 (s 1)))])
+	`, S("[[0 1 2] [3 4]]"))
+	as.EvalTo(`(seq->vector (take-last 2 [1 2 3 4]))`, S("[3 4]"))
+	as.EvalTo(`(seq->vector (take-last 5 (range 1 3)))`, S("[1 2]"))
+}
+
+func TestAggregationEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`(group-by even? [1 2 3 4])`, S("{#f [1 3] #t [2 4]}"))
+	as.EvalTo(`(frequencies [:a :b :a])`, S("{:a 2 :b 1}"))
+	as.EvalTo(`(some (lambda (x) (when (> x 10) x)) (range))`, I(11))
+	as.EvalTo(`(some even? [])`, data.Nil)
+	as.EvalTo(`(every? even? (range))`, data.False)
+	as.EvalTo(`(every? even? [2 4])`, data.True)
+}
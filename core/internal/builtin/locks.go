@@ -0,0 +1,174 @@
+package builtin
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/async"
+)
+
+// Error messages
+const (
+	ErrNotLockable  = "value can't be locked: %s"
+	ErrNotRWMutex   = "value is not a read/write mutex: %s"
+	ErrNotSemaphore = "value is not a semaphore: %s"
+	ErrNotCountdown = "value is not a countdown: %s"
+	ErrBadPermits   = "permits must be a positive integer: %s"
+	ErrBadCount     = "count must be a non-negative integer: %s"
+)
+
+// Mutex returns a new unlocked mutex
+var Mutex = data.Applicative(func(...data.Value) data.Value {
+	return async.NewMutex()
+}, 0)
+
+// RWMutex returns a new unlocked read/write mutex
+var RWMutex = data.Applicative(func(...data.Value) data.Value {
+	return async.NewRWMutex()
+}, 0)
+
+// Lock blocks until the calling task holds the provided mutex. Read
+// /write mutexes are held for writing
+var Lock = data.Applicative(func(args ...data.Value) data.Value {
+	if err := locker(args[0]).Lock(); err != nil {
+		panic(err)
+	}
+	return args[0]
+}, 1)
+
+// TryLock attempts to lock the provided mutex, waiting at most the
+// specified number of milliseconds, and returns whether it succeeded
+var TryLock = data.Applicative(func(args ...data.Value) data.Value {
+	l := locker(args[0])
+	var d time.Duration
+	if len(args) == 2 {
+		d = duration(args[1])
+	}
+	ok, err := l.TryLock(d)
+	if err != nil {
+		panic(err)
+	}
+	return data.Bool(ok)
+}, 1, 2)
+
+// Unlock releases the provided mutex, which must be held by the
+// calling task
+var Unlock = data.Applicative(func(args ...data.Value) data.Value {
+	if err := locker(args[0]).Unlock(); err != nil {
+		panic(err)
+	}
+	return args[0]
+}, 1)
+
+// ReadLock blocks until the calling task holds the provided read/write
+// mutex for reading
+var ReadLock = data.Applicative(func(args ...data.Value) data.Value {
+	if err := rwMutex(args[0]).RLock(); err != nil {
+		panic(err)
+	}
+	return args[0]
+}, 1)
+
+// ReadUnlock releases the calling task's read hold on the provided
+// read/write mutex
+var ReadUnlock = data.Applicative(func(args ...data.Value) data.Value {
+	if err := rwMutex(args[0]).RUnlock(); err != nil {
+		panic(err)
+	}
+	return args[0]
+}, 1)
+
+// Semaphore returns a new semaphore with the specified number of
+// permits
+var Semaphore = data.Applicative(func(args ...data.Value) data.Value {
+	if n, ok := args[0].(data.Integer); ok && n > 0 {
+		return async.NewSemaphore(int(n))
+	}
+	panic(fmt.Errorf(ErrBadPermits, args[0]))
+}, 1)
+
+// Acquire blocks until one of the provided semaphore's permits is
+// available and acquires it
+var Acquire = data.Applicative(func(args ...data.Value) data.Value {
+	semaphore(args[0]).Acquire()
+	return args[0]
+}, 1)
+
+// TryAcquire attempts to acquire one of the provided semaphore's
+// permits, waiting at most the specified number of milliseconds, and
+// returns whether it succeeded
+var TryAcquire = data.Applicative(func(args ...data.Value) data.Value {
+	s := semaphore(args[0])
+	var d time.Duration
+	if len(args) == 2 {
+		d = duration(args[1])
+	}
+	return data.Bool(s.TryAcquire(d))
+}, 1, 2)
+
+// Release returns a permit to the provided semaphore
+var Release = data.Applicative(func(args ...data.Value) data.Value {
+	if err := semaphore(args[0]).Release(); err != nil {
+		panic(err)
+	}
+	return args[0]
+}, 1)
+
+// Countdown returns a new countdown latch that starts at the specified
+// count
+var Countdown = data.Applicative(func(args ...data.Value) data.Value {
+	if n, ok := args[0].(data.Integer); ok && n >= 0 {
+		return async.NewLatch(int(n))
+	}
+	panic(fmt.Errorf(ErrBadCount, args[0]))
+}, 1)
+
+// CountDown decrements the provided countdown latch and returns the
+// remaining count
+var CountDown = data.Applicative(func(args ...data.Value) data.Value {
+	n, err := countdown(args[0]).CountDown()
+	if err != nil {
+		panic(err)
+	}
+	return data.Integer(n)
+}, 1)
+
+// Await blocks until the provided countdown latch reaches zero. If a
+// timeout in milliseconds is provided, returns whether it did so in time
+var Await = data.Applicative(func(args ...data.Value) data.Value {
+	l := countdown(args[0])
+	if len(args) == 1 {
+		l.Await()
+		return data.True
+	}
+	return data.Bool(l.AwaitTimeout(duration(args[1])))
+}, 1, 2)
+
+func locker(v data.Value) async.Locker {
+	if l, ok := v.(async.Locker); ok {
+		return l
+	}
+	panic(fmt.Errorf(ErrNotLockable, v))
+}
+
+func rwMutex(v data.Value) *async.RWMutex {
+	if m, ok := v.(*async.RWMutex); ok {
+		return m
+	}
+	panic(fmt.Errorf(ErrNotRWMutex, v))
+}
+
+func semaphore(v data.Value) *async.Semaphore {
+	if s, ok := v.(*async.Semaphore); ok {
+		return s
+	}
+	panic(fmt.Errorf(ErrNotSemaphore, v))
+}
+
+func countdown(v data.Value) *async.Latch {
+	if l, ok := v.(*async.Latch); ok {
+		return l
+	}
+	panic(fmt.Errorf(ErrNotCountdown, v))
+}
@@ -0,0 +1,119 @@
+package builtin_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kode4food/ale/core/internal/builtin"
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+)
+
+func TestMutexEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`
+		(let* ([m     (mutex)]
+		       [res   (with-lock m :locked)]
+		       [err   (recover (lambda () (with-lock m (raise "boom")))
+		                       (lambda (e) e))]
+		       [ok    (try-lock m)]
+		       [ch    (chan)]
+		       [_     (go (: ch :emit (try-lock m 10)))]
+		       [other (first (:seq ch))])
+		  (unlock m)
+		  [res err ok other])
+	`, S(`[:locked "boom" #t #f]`))
+
+	as.EvalTo(`
+		(let [m (mutex)]
+		  (recover (lambda () (unlock m))
+		           (lambda (e) (:type e))))
+	`, K("not-locked"))
+
+	as.EvalTo(`
+		(let* ([m   (mutex)]
+		       [_   (lock m)]
+		       [err (recover (lambda () (lock m))
+		                     (lambda (e) (:type e)))])
+		  (unlock m)
+		  err)
+	`, K("already-locked"))
+
+	as.EvalTo(`
+		(let* ([m   (mutex)]
+		       [ch  (chan)]
+		       [_   (lock m)]
+		       [_   (go (recover (lambda () (unlock m))
+		                         (lambda (e) (: ch :emit (:type e)))))]
+		       [res (first (:seq ch))])
+		  (unlock m)
+		  res)
+	`, K("not-owner"))
+
+	as.PanicWith(`(lock 99)`, fmt.Errorf(builtin.ErrNotLockable, I(99)))
+}
+
+func TestRWMutexEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`
+		(let* ([m   (rw-mutex)]
+		       [ch  (chan)]
+		       [res (with-read-lock m
+		              (go (with-read-lock m (: ch :emit :shared)))
+		              (first (:seq ch)))])
+		  [res (with-lock m :exclusive)])
+	`, S("[:shared :exclusive]"))
+
+	as.EvalTo(`
+		(let [m (rw-mutex)]
+		  (recover (lambda () (read-unlock m))
+		           (lambda (e) (:type e))))
+	`, K("not-locked"))
+
+	as.PanicWith(`(read-lock :m)`, fmt.Errorf(builtin.ErrNotRWMutex, K("m")))
+}
+
+func TestSemaphoreEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`
+		(let* ([s   (semaphore 1)]
+		       [res (with-permit s (try-acquire s))]
+		       [ok  (try-acquire s 10)])
+		  (release s)
+		  [res ok])
+	`, S("[#f #t]"))
+
+	as.EvalTo(`
+		(let [s (semaphore 1)]
+		  (recover (lambda () (release s))
+		           (lambda (e) (:type e))))
+	`, K("over-released"))
+
+	as.PanicWith(`(semaphore 0)`, fmt.Errorf(builtin.ErrBadPermits, I(0)))
+	as.PanicWith(`(acquire :s)`, fmt.Errorf(builtin.ErrNotSemaphore, K("s")))
+}
+
+func TestCountdownEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`
+		(let* ([l    (countdown 3)]
+		       [work (lambda () (go (count-down l)))])
+		  (work) (work) (work)
+		  (await l)
+		  [(await l 0) (await l 10)])
+	`, S("[#t #t]"))
+
+	as.EvalTo(`
+		(let* ([l       (countdown 1)]
+		       [waited  (await l 10)]
+		       [left    (count-down l)]
+		       [err     (recover (lambda () (count-down l))
+		                         (lambda (e) (:type e)))])
+		  [waited left err])
+	`, S("[#f 0 :latch-underflow]"))
+
+	as.PanicWith(`(countdown -1)`, fmt.Errorf(builtin.ErrBadCount, I(-1)))
+	as.PanicWith(`(await 1)`, fmt.Errorf(builtin.ErrNotCountdown, I(1)))
+	as.Equal(data.Name("countdown"), as.Eval(`(countdown 0)`).(data.Typed).Type())
+}
@@ -0,0 +1,153 @@
+package builtin
+
+import (
+	"fmt"
+
+	"github.com/kode4food/ale/data"
+)
+
+// Error messages
+const (
+	ErrInvalidHierarchyValue = "hierarchy values must be keywords: %s"
+)
+
+// Multimethod option keys
+const (
+	DefaultKey   = data.Keyword("default")
+	HierarchyKey = data.Keyword("hierarchy")
+)
+
+// HierarchyFunctions returns the functions that relate keywords to one
+// another, and that instantiate multimethods. Unless they're called with
+// a hierarchy of their own, they all use the provided default hierarchy
+func HierarchyFunctions(h data.Hierarchy) map[data.Name]data.Function {
+	return map[data.Name]data.Function{
+		"multimethod": multimethod(h),
+		"derive":      derive(h),
+		"is-a":        isA(h),
+		"isa?":        isA(h),
+		"parents":     parents(h),
+		"ancestors":   ancestors(h),
+	}
+}
+
+// multimethod instantiates a new multimethod from a name, a dispatch
+// function, and an optional object of options
+func multimethod(dh data.Hierarchy) data.Function {
+	return data.Applicative(func(args ...data.Value) data.Value {
+		return newMultimethod(dh, args)
+	}, 2, 3)
+}
+
+func newMultimethod(h data.Hierarchy, args data.Values) data.Value {
+	name := args[0].(data.Named).Name()
+	dispatch := args[1].(data.Function)
+	def := data.Value(data.DefaultDispatch)
+	if len(args) > 2 {
+		opts := args[2].(data.Mapped)
+		if v, ok := opts.Get(DefaultKey); ok {
+			def = v
+		}
+		if v, ok := opts.Get(HierarchyKey); ok {
+			h = v.(data.Hierarchy)
+		}
+	}
+	return data.NewMultimethod(name, dispatch, def, h)
+}
+
+// AddMethod registers a multimethod's method for a dispatch value
+var AddMethod = data.Applicative(func(args ...data.Value) data.Value {
+	m := args[0].(data.Multimethod)
+	m.AddMethod(args[1], args[2].(data.Function))
+	return m
+}, 3)
+
+// RemoveMethod removes a multimethod's method for a dispatch value
+var RemoveMethod = data.Applicative(func(args ...data.Value) data.Value {
+	m := args[0].(data.Multimethod)
+	m.RemoveMethod(args[1])
+	return m
+}, 2)
+
+// PreferMethod causes a multimethod to prefer one dispatch value to
+// another when both apply
+var PreferMethod = data.Applicative(func(args ...data.Value) data.Value {
+	m := args[0].(data.Multimethod)
+	if err := m.PreferMethod(args[1], args[2]); err != nil {
+		panic(err)
+	}
+	return m
+}, 3)
+
+// MakeHierarchy instantiates a new, empty hierarchy
+var MakeHierarchy = data.Applicative(func(...data.Value) data.Value {
+	return data.NewHierarchy()
+}, 0)
+
+// derive establishes a parent/child relationship between two keywords,
+// either in the provided hierarchy or in the default one
+func derive(dh data.Hierarchy) data.Function {
+	return data.Applicative(func(args ...data.Value) data.Value {
+		h, args := hierarchyArgs(dh, args, 2)
+		child := hierarchyValue(args[0])
+		parent := hierarchyValue(args[1])
+		if err := h.Derive(child, parent); err != nil {
+			panic(err)
+		}
+		return h
+	}, 2, 3)
+}
+
+// isA returns whether a value is equal to, or derived from, another
+func isA(dh data.Hierarchy) data.Function {
+	return data.Applicative(func(args ...data.Value) data.Value {
+		h, args := hierarchyArgs(dh, args, 2)
+		return data.Bool(h.IsA(args[0], args[1]))
+	}, 2, 3)
+}
+
+// parents returns the immediate parents of a keyword
+func parents(dh data.Hierarchy) data.Function {
+	return data.Applicative(func(args ...data.Value) data.Value {
+		h, args := hierarchyArgs(dh, args, 1)
+		return keywordVector(h.Parents(hierarchyValue(args[0])))
+	}, 1, 2)
+}
+
+// ancestors returns all the ancestors of a keyword, nearest first
+func ancestors(dh data.Hierarchy) data.Function {
+	return data.Applicative(func(args ...data.Value) data.Value {
+		h, args := hierarchyArgs(dh, args, 1)
+		return keywordVector(h.Ancestors(hierarchyValue(args[0])))
+	}, 1, 2)
+}
+
+// IsMultimethod returns whether the provided value is a multimethod
+var IsMultimethod = data.Applicative(func(args ...data.Value) data.Value {
+	_, ok := args[0].(data.Multimethod)
+	return data.Bool(ok)
+}, 1)
+
+func hierarchyArgs(
+	dh data.Hierarchy, args data.Values, count int,
+) (data.Hierarchy, data.Values) {
+	if len(args) > count {
+		return args[0].(data.Hierarchy), args[1:]
+	}
+	return dh, args
+}
+
+func hierarchyValue(v data.Value) data.Keyword {
+	if k, ok := v.(data.Keyword); ok {
+		return k
+	}
+	panic(fmt.Errorf(ErrInvalidHierarchyValue, v))
+}
+
+func keywordVector(k []data.Keyword) data.Value {
+	res := make(data.Values, len(k))
+	for i, e := range k {
+		res[i] = e
+	}
+	return data.NewVector(res...)
+}
@@ -0,0 +1,76 @@
+package builtin_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kode4food/ale/core/internal/builtin"
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+)
+
+func TestHierarchyBuiltins(t *testing.T) {
+	as := assert.New(t)
+
+	fn := builtin.HierarchyFunctions(data.NewHierarchy())
+	h := builtin.MakeHierarchy.Call()
+	fn["derive"].Call(h, K("dog"), K("mammal"))
+	fn["derive"].Call(h, K("mammal"), K("animal"))
+	as.True(fn["is-a"].Call(h, K("dog"), K("animal")))
+	as.False(fn["is-a"].Call(K("dog"), K("animal")))
+	as.Equal(V(K("mammal")), fn["parents"].Call(h, K("dog")))
+	as.Equal(V(K("mammal"), K("animal")), fn["ancestors"].Call(h, K("dog")))
+
+	fn["derive"].Call(K("cat"), K("animal"))
+	as.True(fn["isa?"].Call(K("cat"), K("animal")))
+	other := builtin.HierarchyFunctions(data.NewHierarchy())
+	as.False(other["isa?"].Call(K("cat"), K("animal")))
+
+	defer as.ExpectPanic(fmt.Sprintf(builtin.ErrInvalidHierarchyValue, "dog"))
+	fn["derive"].Call(h, S("dog"), K("animal"))
+}
+
+func TestMultimethodEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`
+		(define-multi multi-handle :type)
+		(define-method multi-handle :click (e) (str "click " (:x e)))
+		(define-method multi-handle :default (e) "unknown")
+		(derive :multi-double-click :click)
+		[(multi-handle {:type :click :x 1})
+		 (multi-handle {:type :multi-double-click :x 2})
+		 (multi-handle {:type :key})
+		 (multimethod? multi-handle)
+		 (isa? :multi-double-click :click)]
+	`, V(S("click 1"), S("click 2"), S("unknown"), data.True, data.True))
+
+	as.EvalTo(`
+		(define multi-shapes (make-hierarchy))
+		(derive multi-shapes :square :rect)
+		(derive multi-shapes :square :rhombus)
+		(define-multi multi-area first
+		  :hierarchy multi-shapes
+		  :default   :other)
+		(define-method multi-area :rect (k) "rect")
+		(define-method multi-area :rhombus (k) "rhombus")
+		(define-method multi-area :other (k) "other")
+		(prefer-method multi-area :rhombus :rect)
+		[(multi-area [:square]) (multi-area [:rect]) (multi-area [:circle])
+		 (isa? :square :rect) (isa? multi-shapes :square :rect)]
+	`, V(S("rhombus"), S("rect"), S("other"), data.False, data.True))
+
+	as.EvalTo(`
+		(define-multi multi-pair (lambda (l r) [(:type l) (:type r)]))
+		(derive :multi-cat :multi-animal)
+		(define-method multi-pair [:multi-animal :multi-animal] (l r) "animals")
+		(define-method multi-pair [:multi-cat :multi-animal] (l r) "cat first")
+		[(multi-pair {:type :multi-cat} {:type :multi-cat})
+		 (multi-pair {:type :multi-animal} {:type :multi-cat})]
+	`, V(S("cat first"), S("animals")))
+
+	as.PanicWith(`
+		(define-multi multi-none :type)
+		(multi-none {:type :missing})
+	`, fmt.Errorf(data.ErrNoMultimethodMethod, "multi-none", ":missing"))
+}
@@ -0,0 +1,106 @@
+package builtin
+
+import (
+	"fmt"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/node"
+)
+
+// Error messages
+const (
+	ErrNotNode = "value is not a node: %s"
+)
+
+// DefaultNodeAddr is the address that a node listens at if none is
+// provided. Its port is chosen by the system
+const DefaultNodeAddr = "127.0.0.1:0"
+
+// StartNode starts a node with the provided name and cookie, listening
+// for connections from other nodes at the provided TCP address
+var StartNode = data.Applicative(func(args ...data.Value) data.Value {
+	addr := DefaultNodeAddr
+	if len(args) == 3 {
+		addr = args[2].(data.String).String()
+	}
+	cookie := args[1].(data.String).String()
+	n, err := node.Start(processName(args[0]), addr, cookie)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}, 2, 3)
+
+// StopNode stops a node, dropping all of its connections
+var StopNode = data.Applicative(func(args ...data.Value) data.Value {
+	nodeValue(args[0]).Close()
+	return data.Nil
+}, 1)
+
+// ConnectNode connects a node to the node listening at the provided
+// address, returning the name of the node it connected to
+var ConnectNode = data.Applicative(func(args ...data.Value) data.Value {
+	n := nodeValue(args[0])
+	peer, err := n.Connect(args[1].(data.String).String())
+	if err != nil {
+		panic(err)
+	}
+	return peer
+}, 2)
+
+// NodeName returns the name of a node
+var NodeName = data.Applicative(func(args ...data.Value) data.Value {
+	return data.Keyword(nodeValue(args[0]).Name())
+}, 1)
+
+// NodeAddr returns the address that a node is listening at
+var NodeAddr = data.Applicative(func(args ...data.Value) data.Value {
+	return data.String(nodeValue(args[0]).Addr())
+}, 1)
+
+// NodePeers returns the names of the nodes that a node is connected to
+var NodePeers = data.Applicative(func(args ...data.Value) data.Value {
+	peers := nodeValue(args[0]).Peers()
+	res := make(data.Values, len(peers))
+	for i, p := range peers {
+		res[i] = p
+	}
+	return data.NewVector(res...)
+}, 1)
+
+// NodeRegister makes a process reachable by the nodes that are connected
+// to a node, under the provided name
+var NodeRegister = data.Applicative(func(args ...data.Value) data.Value {
+	n := nodeValue(args[0])
+	if err := n.Register(processName(args[1]), process(args[2])); err != nil {
+		panic(err)
+	}
+	return args[2]
+}, 3)
+
+// NodeUnregister stops the process that is registered with a node under
+// the provided name from being reachable, returning whether there was one
+var NodeUnregister = data.Applicative(func(args ...data.Value) data.Value {
+	return data.Bool(nodeValue(args[0]).Unregister(processName(args[1])))
+}, 2)
+
+// NodeWhereis asks a connected node for the process that is registered
+// there with the provided name, returning a reference to it or nil
+var NodeWhereis = data.Applicative(func(args ...data.Value) data.Value {
+	n := nodeValue(args[0])
+	p, ok, err := n.Whereis(processName(args[1]), processName(args[2]))
+	if err != nil {
+		panic(err)
+	}
+	if ok {
+		return p
+	}
+	return data.Nil
+}, 3)
+
+func nodeValue(v data.Value) *node.Node {
+	if n, ok := v.(*node.Node); ok {
+		return n
+	}
+	panic(fmt.Errorf(ErrNotNode, v))
+}
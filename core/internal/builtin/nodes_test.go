@@ -0,0 +1,61 @@
+package builtin_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kode4food/ale/core/internal/builtin"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+)
+
+func TestNodesEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`
+		(let* ([a     (start-node :eval-a "test")]
+		       [b     (start-node :eval-b "test" "127.0.0.1:0")]
+		       [peer  (connect-node a (node-addr b))]
+		       [peers (node-peers b)])
+		  (stop-node a)
+		  (stop-node b)
+		  [peer peers (node-name a)])
+	`, S("[:eval-b [:eval-a] :eval-a]"))
+}
+
+func TestRemoteActorsEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`
+		(let* ([a       (start-node :actors-a "test")]
+		       [b       (start-node :actors-b "test")]
+		       [_       (connect-node a (node-addr b))]
+		       [greeter (spawn (lambda (mbox)
+		                         (for-each [msg mbox]
+		                           ((msg 0) (str "hello, " (msg 1))))))]
+		       [_       (node-register b :eval-greeter greeter)]
+		       [remote  (node-whereis a :actors-b :eval-greeter)]
+		       [missing (node-whereis a :actors-b :eval-missing)]
+		       [out     (chan)]
+		       [me      (spawn (lambda (mbox)
+		                         (for-each [m mbox] ((:emit out) m))))]
+		       [_       (remote [me "world"])]
+		       [reply   (first (:seq out))]
+		       [_       (monitor me remote)]
+		       [_       (stop-node b)]
+		       [down    (first (rest (:seq out)))])
+		  (stop-node a)
+		  [reply missing (eq remote greeter) (:reason down)])
+	`, S(`["hello, world" () #f :noconnection]`))
+}
+
+func TestNodeErrors(t *testing.T) {
+	as := assert.New(t)
+	as.PanicWith(`(node-addr 99)`, fmt.Errorf(builtin.ErrNotNode, I(99)))
+	as.PanicWith(
+		`(start-node "a" "test")`, fmt.Errorf(builtin.ErrInvalidName, "a"),
+	)
+	as.PanicWith(`
+		(let [a (start-node :errors-a "test")]
+		  (defer (lambda () (node-whereis a :errors-b :x))
+		         (lambda () (stop-node a))))
+	`, fmt.Errorf("node is not connected: :errors-b"))
+}
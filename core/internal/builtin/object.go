@@ -2,8 +2,15 @@ package builtin
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/sequence"
+)
+
+// Error messages
+const (
+	ErrInvalidPathKey = "invalid key for path element %s: %s"
 )
 
 // Object creates a new object instance
@@ -44,6 +51,161 @@ var Dissoc = data.Applicative(func(args ...data.Value) data.Value {
 	return s
 }, 2)
 
+// Keys returns the keys of the provided MappedSequence
+var Keys = data.Applicative(func(args ...data.Value) data.Value {
+	pairs := mappedPairs(args[0].(data.MappedSequence))
+	res := make(data.Values, len(pairs))
+	for i, p := range pairs {
+		res[i] = p.Car()
+	}
+	return data.NewVector(res...)
+}, 1)
+
+// Vals returns the values of the provided MappedSequence
+var Vals = data.Applicative(func(args ...data.Value) data.Value {
+	pairs := mappedPairs(args[0].(data.MappedSequence))
+	res := make(data.Values, len(pairs))
+	for i, p := range pairs {
+		res[i] = p.Cdr()
+	}
+	return data.NewVector(res...)
+}, 1)
+
+func mappedPairs(s data.MappedSequence) data.Pairs {
+	var res data.Pairs
+	for f, r, ok := s.Split(); ok; f, r, ok = r.Split() {
+		res = append(res, f.(data.Pair))
+	}
+	return res
+}
+
+// Merge returns the first MappedSequence with the pairs of the others
+// associated into it. Later keys replace earlier ones
+var Merge = data.Applicative(func(args ...data.Value) data.Value {
+	return mergeWith(nil, args)
+})
+
+// MergeWith merges MappedSequences, calling a function to combine the
+// values of any keys that they have in common
+var MergeWith = data.Applicative(func(args ...data.Value) data.Value {
+	return mergeWith(args[0].(data.Function), args[1:])
+}, 1, data.OrMore)
+
+func mergeWith(fn data.Function, args data.Values) data.Value {
+	if len(args) == 0 {
+		return data.EmptyObject
+	}
+	res := args[0].(data.MappedSequence)
+	for _, m := range args[1:] {
+		for _, p := range mappedPairs(m.(data.MappedSequence)) {
+			if fn != nil {
+				if v, ok := res.Get(p.Car()); ok {
+					p = data.NewCons(p.Car(), fn.Call(v, p.Cdr()))
+				}
+			}
+			res = res.Put(p).(data.MappedSequence)
+		}
+	}
+	return res
+}
+
+// SelectKeys returns an object containing only the provided keys
+var SelectKeys = data.Applicative(func(args ...data.Value) data.Value {
+	s := args[0].(data.MappedSequence)
+	keys := sequence.ToValues(args[1].(data.Sequence))
+	res := data.NewTransientObject(data.EmptyObject)
+	for _, k := range keys {
+		if v, ok := s.Get(k); ok {
+			res.Put(data.NewCons(k, v))
+		}
+	}
+	return res.Persistent()
+}, 2)
+
+// RenameKeys returns a new MappedSequence with its keys renamed
+// according to the provided MappedSequence of old to new keys
+var RenameKeys = data.Applicative(func(args ...data.Value) data.Value {
+	s := args[0].(data.MappedSequence)
+	renames := mappedPairs(args[1].(data.MappedSequence))
+	var res data.Sequence = s
+	for _, p := range renames {
+		if _, r, ok := res.(data.MappedSequence).Remove(p.Car()); ok {
+			res = r
+		}
+	}
+	for _, p := range renames {
+		if v, ok := s.Get(p.Car()); ok {
+			res = res.(data.MappedSequence).Put(data.NewCons(p.Cdr(), v))
+		}
+	}
+	return res
+}, 2)
+
+// GetIn returns a value from a nested structure of objects, records
+// and vectors, or a default if any element of the path is missing
+var GetIn = data.Applicative(func(args ...data.Value) data.Value {
+	var res data.Value = args[0]
+	for _, k := range sequence.ToValues(args[1].(data.Sequence)) {
+		v, ok := getElement(res, k)
+		if !ok {
+			if len(args) > 2 {
+				return args[2]
+			}
+			return data.Nil
+		}
+		res = v
+	}
+	return res
+}, 2, 3)
+
+// AssocIn associates a value within a nested structure of objects,
+// records and vectors, creating objects for any missing elements
+var AssocIn = data.Applicative(func(args ...data.Value) data.Value {
+	path := sequence.ToValues(args[1].(data.Sequence))
+	if len(path) == 0 {
+		return args[2]
+	}
+	return assocIn(args[0], path, args[2])
+}, 3)
+
+func assocIn(s data.Value, path data.Values, v data.Value) data.Value {
+	k := path[0]
+	if len(path) > 1 {
+		child, ok := getElement(s, k)
+		if !ok || child == data.Nil {
+			child = data.EmptyObject
+		}
+		v = assocIn(child, path[1:], v)
+	}
+	return putElement(s, k, v)
+}
+
+func getElement(s data.Value, k data.Value) (data.Value, bool) {
+	switch s := s.(type) {
+	case data.Mapped:
+		return s.Get(k)
+	case data.Indexed:
+		if i, ok := k.(data.Integer); ok {
+			return s.ElementAt(int(i))
+		}
+	}
+	return data.Nil, false
+}
+
+func putElement(s data.Value, k data.Value, v data.Value) data.Value {
+	switch s := s.(type) {
+	case data.MappedSequence:
+		return s.Put(data.NewCons(k, v))
+	case data.Vector:
+		if i, ok := k.(data.Integer); ok {
+			res := data.NewTransientVector(s)
+			res.Set(int(i), v)
+			return res.Persistent()
+		}
+	}
+	panic(fmt.Errorf(ErrInvalidPathKey, s, k))
+}
+
 // IsObject returns whether a value is an object
 var IsObject = data.Applicative(func(args ...data.Value) data.Value {
 	_, ok := args[0].(data.Object)
@@ -2,6 +2,7 @@ package builtin_test
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/kode4food/ale/core/internal/builtin"
@@ -64,3 +65,62 @@ func TestMappedEval(t *testing.T) {
 	as.EvalTo(`(!mapped? '(:name "Ale" :age 45))`, data.True)
 	as.EvalTo(`(!mapped? [:name "Ale" :age 45])`, data.True)
 }
+
+func TestObjectLibrary(t *testing.T) {
+	as := assert.New(t)
+
+	o := O(C(K("a"), I(1)), C(K("b"), I(2)))
+	as.Equal(V(K("a")), builtin.Keys.Call(O(C(K("a"), I(1)))))
+	as.Equal(V(I(1)), builtin.Vals.Call(O(C(K("a"), I(1)))))
+	as.Equal(2, builtin.Keys.Call(o).(data.Vector).Count())
+	as.Equal(data.EmptyObject, builtin.Merge.Call())
+	as.String("{:a 1 :b 3}", builtin.Merge.Call(o, O(C(K("b"), I(3)))))
+	as.String("{:a 1}", builtin.SelectKeys.Call(o, V(K("a"), K("z"))))
+	as.String("{:a 2 :c 1}",
+		builtin.RenameKeys.Call(o, O(C(K("a"), K("c")), C(K("b"), K("a")))),
+	)
+}
+
+func TestObjectLibraryEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`
+		(merge-with + {:a 1 :b 2} {:a 10} {:b 5 :c 1})
+	`, O(C(K("a"), I(11)), C(K("b"), I(7)), C(K("c"), I(1))))
+
+	as.EvalTo(`
+		(define nested {:a {:b [1 2 {:c 3}]}})
+		[(get-in nested [:a :b 2 :c])
+		 (get-in nested [:a :x] :missing)
+		 (get-in nested [:a :b 5])
+		 (get-in nested [])]
+	`, V(
+		I(3), K("missing"), data.Nil,
+		O(C(K("a"), O(C(K("b"), V(I(1), I(2), O(C(K("c"), I(3))))))))),
+	)
+
+	as.EvalTo(`
+		[(assoc-in {:a {:b [1 2 {:c 3}]}} [:a :b 2 :c] 4)
+		 (assoc-in {} [:a :b] 1)
+		 (assoc-in [1 [2 3]] [1 2] 4)]
+	`, S(`[{:a {:b [1 2 {:c 4}]}} {:a {:b 1}} [1 [2 3 4]]]`))
+
+	as.EvalTo(`
+		(define-record obj-point [x y])
+		(let [p (->obj-point {:z 1} 2)]
+		  [(get-in p [:x :z])
+		   (update-in p [:x :z] inc)
+		   (update p :y * 10)
+		   (merge p {:x 5})])
+	`, S(`[1 #obj-point{:x {:z 2} :y 2} #obj-point{:x {:z 1} :y 20} `+
+		`#obj-point{:x 5 :y 2}]`))
+
+	as.EvalTo(`
+		[(update [1 2] 0 + 10 100)
+		 (map-vals inc {:a 1 :b 2})
+		 (filter-keys keyword? {:a 1 "b" 2})]
+	`, S(`[[111 2] {:a 2 :b 3} {:a 1}]`))
+
+	as.PanicWith(`(assoc-in [1 2] [:x] 3)`,
+		fmt.Errorf(builtin.ErrInvalidPathKey, "[1 2]", ":x"),
+	)
+}
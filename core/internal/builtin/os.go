@@ -3,7 +3,6 @@ package builtin
 import (
 	"os"
 	"regexp"
-	"time"
 
 	"github.com/kode4food/ale/data"
 )
@@ -29,8 +28,3 @@ func Args() data.Value {
 	}
 	return data.NewVector(r...)
 }
-
-// CurrentTime returns the current system time in nanoseconds
-var CurrentTime = data.Applicative(func(_ ...data.Value) data.Value {
-	return data.Integer(time.Now().UnixNano())
-}, 0)
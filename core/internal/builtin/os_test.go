@@ -7,13 +7,15 @@ import (
 	"github.com/kode4food/ale/core/internal/builtin"
 	"github.com/kode4food/ale/data"
 	"github.com/kode4food/ale/internal/assert"
+	"github.com/kode4food/ale/internal/clock"
 )
 
 func TestCurrentTime(t *testing.T) {
 	as := assert.New(t)
 
 	t1 := time.Now().UnixNano()
-	t2 := int64(builtin.CurrentTime.Call().(data.Integer))
+	now := builtin.ClockFunctions(clock.NewRef(clock.Real))["current-time"]
+	t2 := int64(now.Call().(data.Integer))
 
 	as.Equal(t1-(t1%1000000), t2-(t2%1000000))
 }
@@ -0,0 +1,251 @@
+package builtin
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/sequence"
+)
+
+type (
+	parallelTask struct {
+		done  chan struct{}
+		index int
+		input data.Value
+		value data.Value
+		err   data.Error
+	}
+
+	parallelResult func(t *parallelTask) (data.Value, bool)
+
+	folder struct {
+		size    int
+		combine data.Function
+		reduce  data.Function
+		elems   data.RandomAccess
+		workers chan struct{}
+	}
+)
+
+// Error messages
+const (
+	ErrWorkerPanicked     = "%s worker panicked on element %d: %s"
+	ErrInvalidParallelism = "parallelism must be a positive integer: %s"
+	ErrInvalidChunkSize   = "fold chunk size must be a positive integer: %s"
+)
+
+const (
+	// WorkerPanic is the type of error raised when a worker panics
+	WorkerPanic = data.Keyword("worker-panic")
+
+	// IndexKey identifies the element that a worker was processing
+	// when it panicked
+	IndexKey = data.Keyword("index")
+)
+
+const defaultFoldChunkSize = 512
+
+// ParallelMap returns a lazy sequence of the results of applying a
+// function to the elements of a sequence. The function is applied by
+// a bounded number of concurrent workers, and the order of the results
+// is preserved
+var ParallelMap = data.Applicative(func(args ...data.Value) data.Value {
+	n, fn, s := parallelArgs(args)
+	return parallelSequence("pmap", n, fn, s,
+		func(t *parallelTask) (data.Value, bool) {
+			return t.value, true
+		},
+	)
+}, 2, 3)
+
+// ParallelFilter returns a lazy sequence of the elements of a sequence
+// for which a function returns a truthy value. The function is applied
+// by a bounded number of concurrent workers, and the order of the
+// results is preserved
+var ParallelFilter = data.Applicative(func(args ...data.Value) data.Value {
+	n, fn, s := parallelArgs(args)
+	return parallelSequence("pfilter", n, fn, s,
+		func(t *parallelTask) (data.Value, bool) {
+			return t.input, data.Truthy(t.value)
+		},
+	)
+}, 2, 3)
+
+// Fold reduces a sequence by splitting it into chunks that are reduced
+// concurrently, and then combining their results. The combine function
+// is called without arguments to produce the initial value of each
+// chunk. No more than GOMAXPROCS chunks are reduced at once
+var Fold = data.Applicative(func(args ...data.Value) data.Value {
+	size := defaultFoldChunkSize
+	if len(args) == 4 {
+		size = positiveInt(args[0], ErrInvalidChunkSize)
+		args = args[1:]
+	}
+	f := &folder{
+		size:    size,
+		combine: args[0].(data.Function),
+		reduce:  args[1].(data.Function),
+		elems:   foldElements(args[2].(data.Sequence)),
+		workers: make(chan struct{}, runtime.GOMAXPROCS(0)-1),
+	}
+	res, err := f.foldRange(0, f.elems.Count())
+	if err != nil {
+		panic(err)
+	}
+	return res
+}, 3, 4)
+
+func parallelArgs(
+	args data.Values,
+) (int, data.Function, data.Sequence) {
+	n := runtime.GOMAXPROCS(0)
+	if len(args) == 3 {
+		n = positiveInt(args[0], ErrInvalidParallelism)
+		args = args[1:]
+	}
+	return n, args[0].(data.Function), args[1].(data.Sequence)
+}
+
+func positiveInt(v data.Value, errStr string) int {
+	if i, ok := v.(data.Integer); ok && i > 0 {
+		return int(i)
+	}
+	panic(fmt.Errorf(errStr, v))
+}
+
+func parallelSequence(
+	name string, n int, fn data.Function, s data.Sequence,
+	result parallelResult,
+) data.Sequence {
+	it := data.MakeIterator(s)
+	var pending []*parallelTask
+	index := 0
+
+	fill := func() {
+		for len(pending) < n {
+			v, ok := it.Next()
+			if !ok {
+				return
+			}
+			t := &parallelTask{
+				done:  make(chan struct{}),
+				index: index,
+				input: v,
+			}
+			index++
+			pending = append(pending, t)
+			go t.run(name, fn)
+		}
+	}
+
+	var resolver sequence.LazyResolver
+	resolver = func() (data.Value, data.Sequence, bool) {
+		for fill(); len(pending) > 0; fill() {
+			t := pending[0]
+			pending = pending[1:]
+			<-t.done
+			if t.err != nil {
+				panic(t.err)
+			}
+			if v, ok := result(t); ok {
+				return v, sequence.NewLazy(resolver), true
+			}
+		}
+		return data.Nil, data.EmptyList, false
+	}
+	return sequence.NewLazy(resolver)
+}
+
+func (t *parallelTask) run(name string, fn data.Function) {
+	defer close(t.done)
+	defer func() {
+		if rec := recover(); rec != nil {
+			t.err = workerError(name, t.index, rec)
+		}
+	}()
+	t.value = fn.Call(t.input)
+}
+
+func workerError(name string, index int, rec interface{}) data.Error {
+	cause := data.ErrorCause(rec)
+	msg := cause.String()
+	if err, ok := rec.(error); ok {
+		msg = err.Error()
+	}
+	return data.NewError(WorkerPanic,
+		fmt.Sprintf(ErrWorkerPanicked, name, index, msg),
+		data.NewCons(IndexKey, data.Integer(index)),
+		data.NewCons(data.CauseKey, cause),
+	)
+}
+
+// foldElements returns the elements of a sequence in a form that can be
+// split in constant time. Lists are indexed by walking them, so they're
+// converted to a vector along with the sequences that aren't indexed
+func foldElements(s data.Sequence) data.RandomAccess {
+	if _, ok := s.(data.List); !ok {
+		if r, ok := s.(data.RandomAccessSequence); ok {
+			return r
+		}
+	}
+	return sequence.ToVector(s)
+}
+
+func (f *folder) foldRange(start, end int) (res data.Value, err data.Error) {
+	index := start
+	defer func() {
+		if rec := recover(); rec != nil {
+			res, err = data.Nil, workerError("fold", index, rec)
+		}
+	}()
+
+	if end-start <= f.size {
+		res = f.combine.Call()
+		for ; index < end; index++ {
+			e, _ := f.elems.ElementAt(index)
+			res = f.reduce.Call(res, e)
+			if _, ok := res.(*reduced); ok {
+				break
+			}
+		}
+		return unreduced(res), nil
+	}
+
+	mid := start + (end-start)/2
+	left := f.foldLeft(start, mid)
+	right, rightErr := f.foldRange(mid, end)
+	l, leftErr := left()
+	if leftErr != nil {
+		return data.Nil, leftErr
+	}
+	if rightErr != nil {
+		return data.Nil, rightErr
+	}
+	return f.combine.Call(l, right), nil
+}
+
+// foldLeft reduces the left half of a split range. It's reduced by a
+// new goroutine if a worker is available, otherwise by the caller
+func (f *folder) foldLeft(start, end int) func() (data.Value, data.Error) {
+	select {
+	case f.workers <- struct{}{}:
+		var res data.Value
+		var err data.Error
+		done := make(chan struct{})
+		go func() {
+			defer func() { <-f.workers }()
+			defer close(done)
+			res, err = f.foldRange(start, end)
+		}()
+		return func() (data.Value, data.Error) {
+			<-done
+			return res, err
+		}
+	default:
+		res, err := f.foldRange(start, end)
+		return func() (data.Value, data.Error) {
+			return res, err
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package builtin_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kode4food/ale/core/internal/builtin"
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+)
+
+func TestParallelMapEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`(seq->vector (pmap inc [1 2 3 4 5]))`, S("[2 3 4 5 6]"))
+	as.EvalTo(`(seq->vector (pmap 2 inc '(1 2 3)))`, S("[2 3 4]"))
+	as.EvalTo(`(seq->vector (take 3 (pmap inc (range))))`, S("[1 2 3]"))
+	as.EvalTo(`(seq->vector (pmap inc []))`, S("[]"))
+	as.EvalTo(`
+		(seq->vector (pfilter 3 even? (range 10)))
+	`, S("[0 2 4 6 8]"))
+	as.EvalTo(`(seq->vector (take 2 (pfilter odd? (range))))`, S("[1 3]"))
+
+	err := fmt.Errorf(builtin.ErrInvalidParallelism, I(0))
+	as.PanicWith(`(pmap 0 inc [1])`, err)
+}
+
+func TestParallelPanicEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`
+		(recover
+		  (lambda ()
+		    (seq->vector (pmap (lambda (x) (if (= x 3) (raise "boom") x))
+		                       (range 10))))
+		  (lambda (e)
+		    [(:type e) (:index e) (:cause e) (:message e)]))
+	`, S(`[:worker-panic 3 "boom" "pmap worker panicked on element 3: boom"]`))
+
+	as.EvalTo(`
+		(recover
+		  (lambda ()
+		    (fold 2 + (lambda (acc x) (raise {:type :bad :message "no"}))
+		          [1 2 3]))
+		  (lambda (e)
+		    [(:type e) (:type (:cause e))]))
+	`, S("[:worker-panic :bad]"))
+}
+
+func TestFoldEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`(fold + + (range 10000))`, F(49995000))
+	as.EvalTo(`(fold 10 + + (seq->vector (range 1000)))`, F(499500))
+	as.EvalTo(`(fold 1 + + (seq->vector (range 1000)))`, F(499500))
+	as.EvalTo(`(fold 2 + + '(1 2 3 4 5))`, F(15))
+	as.EvalTo(`(fold + + [])`, F(0))
+	as.EvalTo(`
+		(fold 2
+		      (lambda [() []] [(l r) (apply conj l r)])
+		      conj
+		      '(1 2 3 4 5))
+	`, S("[1 2 3 4 5]"))
+
+	// each chunk stops being reduced once its reducer returns reduced
+	as.EvalTo(`
+		(fold 4
+		      +
+		      (lambda (acc x)
+		        (let [r (+ acc x)]
+		          (if (> r 10) (reduced r) r)))
+		      (seq->vector (range 16)))
+	`, F(50))
+}
+
+func TestParallelMapOrder(t *testing.T) {
+	as := assert.New(t)
+	in := make(data.Values, 1000)
+	for i := range in {
+		in[i] = I(int64(i))
+	}
+	inc := data.Applicative(func(args ...data.Value) data.Value {
+		return args[0].(data.Integer) + 1
+	}, 1)
+	res := builtin.ParallelMap.Call(I(8), inc, V(in...)).(data.Sequence)
+	i := int64(1)
+	for f, r, ok := res.Split(); ok; f, r, ok = r.Split() {
+		as.Equal(I(i), f)
+		i++
+	}
+	as.Equal(int64(1001), i)
+}
@@ -0,0 +1,87 @@
+package builtin
+
+import (
+	"time"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/async"
+)
+
+// Future instantiates a new promise whose resolver is immediately
+// called asynchronously
+var Future = data.Applicative(func(args ...data.Value) data.Value {
+	return async.NewFuture(args[0].(data.Function))
+}, 1)
+
+// Deliver resolves a promise with the provided value, returning whether
+// the promise had not already been resolved
+var Deliver = data.Applicative(func(args ...data.Value) data.Value {
+	p := args[0].(async.Promise)
+	return data.Bool(p.Deliver(args[1]))
+}, 2)
+
+// Deref waits for a promise to be resolved and returns its value. If a
+// timeout in milliseconds is provided and the promise isn't resolved
+// in time, the default value is returned instead, or nil if there
+// isn't one
+var Deref = data.Applicative(func(args ...data.Value) data.Value {
+	p := args[0].(async.Promise)
+	if len(args) == 1 {
+		return p.Call()
+	}
+	ms := time.Duration(args[1].(data.Integer)) * time.Millisecond
+	res, err, ok := p.AwaitTimeout(ms)
+	if !ok {
+		if len(args) == 2 {
+			return data.Nil
+		}
+		return args[2]
+	}
+	if err != nil {
+		panic(err)
+	}
+	return res
+}, 1, 3)
+
+// Then returns a new promise that is resolved with the result of
+// calling a function with the value of the provided promise
+var Then = data.Applicative(func(args ...data.Value) data.Value {
+	p := args[0].(async.Promise)
+	return async.Then(p, args[1].(data.Function))
+}, 2)
+
+// Catch returns a new promise that is resolved with the value of the
+// provided promise, or with the result of calling a function with its
+// error if it fails
+var Catch = data.Applicative(func(args ...data.Value) data.Value {
+	p := args[0].(async.Promise)
+	return async.Catch(p, args[1].(data.Function))
+}, 2)
+
+// PromiseAll returns a new promise that is resolved with a vector of
+// the values of all the provided promises, or that fails with the
+// first of their errors
+var PromiseAll = data.Applicative(func(args ...data.Value) data.Value {
+	return async.All(promises(args[0])...)
+}, 1)
+
+// PromiseAny returns a new promise that is resolved with the value of
+// the first of the provided promises to succeed
+var PromiseAny = data.Applicative(func(args ...data.Value) data.Value {
+	return async.Any(promises(args[0])...)
+}, 1)
+
+// PromiseRace returns a new promise that is resolved or fails along
+// with the first of the provided promises to do so
+var PromiseRace = data.Applicative(func(args ...data.Value) data.Value {
+	return async.Race(promises(args[0])...)
+}, 1)
+
+func promises(v data.Value) []async.Promise {
+	var res []async.Promise
+	it := data.MakeIterator(v.(data.Sequence))
+	for f, ok := it.Next(); ok; f, ok = it.Next() {
+		res = append(res, f.(async.Promise))
+	}
+	return res
+}
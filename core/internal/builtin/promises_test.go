@@ -0,0 +1,74 @@
+package builtin_test
+
+import (
+	"testing"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+)
+
+func TestDeliverEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`
+		(let [p (promise)]
+		  (go (deliver p :done))
+		  (deref p))
+	`, K("done"))
+	as.EvalTo(`
+		(let* ([p     (promise)]
+		       [first (deliver p 1)]
+		       [again (deliver p 2)])
+		  [first again (p) (resolved? p)])
+	`, S("[#t #f 1 #t]"))
+	as.EvalTo(`(deref (promise) 10 :timed-out)`, K("timed-out"))
+	as.EvalTo(`(deref (promise) 10)`, data.Nil)
+	as.EvalTo(`(deref (future 42) 1000 :timed-out)`, F(42))
+}
+
+func TestPromiseChainEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`
+		(let* ([p (promise)]
+		       [q (then (then p inc) (lambda (x) (* x 10)))])
+		  (deliver p 1)
+		  (q))
+	`, F(20))
+	as.EvalTo(`
+		(deref (catch (future (raise {:type :bad :message "oops"}))
+		              (lambda (e) [(:type e) (:message e)])))
+	`, S(`[:bad "oops"]`))
+	as.EvalTo(`
+		(deref (catch (future (raise "oops"))
+		              (lambda (e) [(:type e) (:message e)])))
+	`, S(`[:promise-failed "oops"]`))
+	as.EvalTo(`(deref (catch (future 1) (lambda (e) 2)))`, F(1))
+	as.EvalTo(`
+		(recover (lambda () (deref (then (future (raise "first")) inc)))
+		         (lambda (e) (:message e)))
+	`, S("first"))
+}
+
+func TestPromiseCombinatorsEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`(deref (promise-all [(future 1) (future 2) (future 3)]))`,
+		S("[1 2 3]"))
+	as.EvalTo(`(deref (promise-all []))`, data.EmptyVector)
+	as.EvalTo(`
+		(deref (promise-any [(future (raise "no")) (future :yes)]))
+	`, K("yes"))
+	as.EvalTo(`
+		(recover
+		  (lambda ()
+		    (deref (promise-any [(future (raise "a")) (future (raise "b"))])))
+		  (lambda (e)
+		    [(:type e) (length (:errors e)) (:message ((:errors e) 0))]))
+	`, S(`[:all-failed 2 "a"]`))
+	as.EvalTo(`(deref (promise-race [(promise) (future :fast)]))`,
+		K("fast"))
+	as.EvalTo(`
+		(recover
+		  (lambda () (deref (promise-all [(promise) (future (raise "bad"))])))
+		  (lambda (e) (:message e)))
+	`, S("bad"))
+}
@@ -0,0 +1,119 @@
+package builtin
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/sequence"
+)
+
+// Error messages
+const (
+	ErrUnknownProtocolType   = "unknown protocol type: %s"
+	ErrInvalidProtocolMethod = "invalid protocol method: %s"
+	ErrUnpairedProtocolImpls = "protocol implementations must be paired"
+)
+
+var protocolTypes = map[data.Keyword]data.GoType{}
+
+func init() {
+	for k, v := range map[data.Keyword]interface{}{
+		"any":      (*data.Value)(nil),
+		"null":     (*data.Null)(nil),
+		"boolean":  (*data.Bool)(nil),
+		"integer":  (*data.Integer)(nil),
+		"float":    (*data.Float)(nil),
+		"number":   (*data.Number)(nil),
+		"string":   (*data.String)(nil),
+		"keyword":  (*data.Keyword)(nil),
+		"symbol":   (*data.Symbol)(nil),
+		"list":     (*data.List)(nil),
+		"vector":   (*data.Vector)(nil),
+		"object":   (*data.Object)(nil),
+		"cons":     (*data.Cons)(nil),
+		"pair":     (*data.Pair)(nil),
+		"record":   (*data.Record)(nil),
+		"seq":      (*data.Sequence)(nil),
+		"mapped":   (*data.Mapped)(nil),
+		"function": (*data.Function)(nil),
+	} {
+		t := reflect.TypeOf(v).Elem()
+		protocolTypes[k] = data.NewGoType(data.Name(k), t)
+	}
+}
+
+// Protocol instantiates a new protocol from a name and a sequence of
+// method names
+var Protocol = data.Applicative(func(args ...data.Value) data.Value {
+	name := args[0].(data.Named).Name()
+	in := sequence.ToValues(args[1].(data.Sequence))
+	methods := make(data.Names, len(in))
+	for i, m := range in {
+		methods[i] = protocolMethodName(m)
+	}
+	return data.NewProtocol(name, methods...)
+}, 2)
+
+func protocolMethodName(v data.Value) data.Name {
+	if s, ok := v.(data.LocalSymbol); ok {
+		return s.Name()
+	}
+	panic(fmt.Errorf(ErrInvalidProtocolMethod, v))
+}
+
+// ProtocolMethod returns the dispatching function for a protocol method
+var ProtocolMethod = data.Applicative(func(args ...data.Value) data.Value {
+	p := args[0].(data.Protocol)
+	n := protocolMethodName(args[1])
+	if fn, ok := p.Method(n); ok {
+		return fn
+	}
+	panic(fmt.Errorf(data.ErrProtocolMethodNotFound, p.Name(), n))
+}, 2)
+
+// ExtendProtocol registers method implementations for a type. The type
+// can be a record type, a Go type, or one of the builtin type keywords
+var ExtendProtocol = data.Applicative(func(args ...data.Value) data.Value {
+	p := args[0].(data.Protocol)
+	target := protocolTarget(args[1])
+	impls := args[2:]
+	if len(impls)%2 != 0 {
+		panic(errors.New(ErrUnpairedProtocolImpls))
+	}
+	methods := make(data.ProtocolMethods, len(impls)/2)
+	for i := 0; i < len(impls); i += 2 {
+		n := protocolMethodName(impls[i])
+		methods[n] = impls[i+1].(data.Function)
+	}
+	if err := p.Extend(target, methods); err != nil {
+		panic(err)
+	}
+	return p
+}, 2, data.OrMore)
+
+func protocolTarget(v data.Value) data.Value {
+	switch v := v.(type) {
+	case data.Keyword:
+		if t, ok := protocolTypes[v]; ok {
+			return t
+		}
+	case data.RecordType, data.GoType:
+		return v
+	}
+	panic(fmt.Errorf(ErrUnknownProtocolType, v))
+}
+
+// Satisfies returns whether a value implements all of a protocol's
+// methods
+var Satisfies = data.Applicative(func(args ...data.Value) data.Value {
+	p := args[0].(data.Protocol)
+	return data.Bool(p.Satisfies(args[1]))
+}, 2)
+
+// IsProtocol returns whether the provided value is a protocol
+var IsProtocol = data.Applicative(func(args ...data.Value) data.Value {
+	_, ok := args[0].(data.Protocol)
+	return data.Bool(ok)
+}, 1)
@@ -0,0 +1,86 @@
+package builtin_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kode4food/ale/core/internal/builtin"
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+)
+
+func TestProtocol(t *testing.T) {
+	as := assert.New(t)
+
+	p := builtin.Protocol.Call(LS("sized"), L(LS("size"))).(data.Protocol)
+	as.True(builtin.IsProtocol.Call(p))
+	as.False(builtin.IsProtocol.Call(I(1)))
+
+	size := builtin.ProtocolMethod.Call(p, LS("size")).(data.Function)
+	builtin.ExtendProtocol.Call(p, K("vector"), LS("size"),
+		data.Applicative(func(args ...data.Value) data.Value {
+			return I(int64(args[0].(data.Vector).Count()))
+		}, 1),
+	)
+	as.Equal(I(3), size.Call(V(I(1), I(2), I(3))))
+	as.True(builtin.Satisfies.Call(p, V()))
+	as.False(builtin.Satisfies.Call(p, L()))
+
+	defer as.ExpectPanic(fmt.Sprintf(builtin.ErrUnknownProtocolType, ":blah"))
+	builtin.ExtendProtocol.Call(p, K("blah"))
+}
+
+func TestProtocolEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`
+		(define-protocol proto-shape
+		  (proto-area this)
+		  (proto-describe this prefix))
+		(define-record proto-rect [w h])
+		(define-record proto-circle [r])
+		(extend-protocol proto-shape
+		  proto-rect
+		  (proto-area (this) (* (proto-rect-w this) (proto-rect-h this)))
+		  (proto-describe (this prefix) (str prefix "rect"))
+
+		  proto-circle
+		  (proto-area (this) (* 3 (proto-circle-r this) (proto-circle-r this))))
+		(extend-type :seq
+		  proto-shape
+		  (proto-area (this) 0)
+		  (proto-describe (this prefix) (str prefix "seq")))
+		(extend-type :vector
+		  proto-shape
+		  (proto-area (this) (length this)))
+		[(proto-area (->proto-rect 2 3))
+		 (proto-area (->proto-circle 2))
+		 (proto-area [1 2])
+		 (proto-area '(1 2))
+		 (proto-describe (->proto-rect 1 1) "a ")
+		 (proto-describe (->proto-circle 1) "a ")
+		 (proto-describe [1] "a ")]
+	`, V(I(6), I(12), I(2), I(0), S("a rect"), S("a seq"), S("a seq")))
+
+	as.EvalTo(`
+		(define-protocol proto-named (proto-name this))
+		(extend-protocol proto-named
+		  :string  (proto-name (this) "string")
+		  :keyword (proto-name (this) "keyword"))
+		[(proto-name "x") (proto-name :x)
+		 (satisfies proto-named "x") (satisfies proto-named 1)
+		 (protocol? proto-named)]
+	`, V(S("string"), S("keyword"), data.True, data.False, data.True))
+
+	as.PanicWith(`
+		(define-protocol proto-missing (proto-missing-m this))
+		(proto-missing-m 1)
+	`, fmt.Errorf(data.ErrProtocolNotImplemented,
+		"proto-missing", "proto-missing-m", "1"),
+	)
+
+	as.PanicWith(`
+		(define-protocol proto-bad (proto-bad-m this))
+		(extend-type :integer proto-bad (proto-other (this) 1))
+	`, fmt.Errorf(data.ErrProtocolMethodNotFound, "proto-bad", "proto-other"))
+}
@@ -0,0 +1,93 @@
+package builtin
+
+import (
+	"fmt"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/sequence"
+)
+
+// Error messages
+const (
+	ErrUnknownRecordField   = "record %s has no field: %s"
+	ErrRecordTypeMismatch   = "value is not a %s record: %s"
+	ErrInvalidRecordField   = "invalid record field: %s"
+	ErrRecordMappedRequired = "record constructor requires a mapped value: %s"
+)
+
+// RecordType instantiates a new record type from a name and a sequence
+// of field names
+var RecordType = data.Applicative(func(args ...data.Value) data.Value {
+	name := args[0].(data.Named).Name()
+	in := sequence.ToValues(args[1].(data.Sequence))
+	fields := make([]data.Keyword, len(in))
+	for i, f := range in {
+		fields[i] = recordField(f)
+	}
+	return data.NewRecordType(name, fields...)
+}, 2)
+
+func recordField(v data.Value) data.Keyword {
+	switch v := v.(type) {
+	case data.Keyword:
+		return v
+	case data.LocalSymbol:
+		return data.Keyword(v.Name())
+	default:
+		panic(fmt.Errorf(ErrInvalidRecordField, v))
+	}
+}
+
+// RecordConstructor returns a positional constructor for a record type
+var RecordConstructor = data.Applicative(func(args ...data.Value) data.Value {
+	t := args[0].(data.RecordType)
+	return data.Applicative(func(args ...data.Value) data.Value {
+		return t.New(args...)
+	}, len(t.Fields()))
+}, 1)
+
+// RecordObjectConstructor returns a constructor for a record type that
+// retrieves the record's fields from a mapped value
+var RecordObjectConstructor = data.Applicative(func(args ...data.Value) data.Value {
+	t := args[0].(data.RecordType)
+	return data.Applicative(func(args ...data.Value) data.Value {
+		if m, ok := args[0].(data.Mapped); ok {
+			return t.FromMapped(m)
+		}
+		panic(fmt.Errorf(ErrRecordMappedRequired, args[0]))
+	}, 1)
+}, 1)
+
+// RecordAccessor returns a function that retrieves a single field from
+// records of the provided type
+var RecordAccessor = data.Applicative(func(args ...data.Value) data.Value {
+	t := args[0].(data.RecordType)
+	f := recordField(args[1])
+	idx, ok := t.IndexOf(f)
+	if !ok {
+		panic(fmt.Errorf(ErrUnknownRecordField, t.Name(), f))
+	}
+	return data.Applicative(func(args ...data.Value) data.Value {
+		if r, ok := args[0].(data.Record); ok && r.RecordType() == t {
+			return r.FieldAt(idx)
+		}
+		panic(fmt.Errorf(ErrRecordTypeMismatch, t.Name(), args[0]))
+	}, 1)
+}, 2)
+
+// IsRecord returns whether the provided value is a record, optionally
+// of a specific record type
+var IsRecord = data.Applicative(func(args ...data.Value) data.Value {
+	r, ok := args[0].(data.Record)
+	if !ok || len(args) == 1 {
+		return data.Bool(ok)
+	}
+	t := args[1].(data.RecordType)
+	return data.Bool(r.RecordType() == t)
+}, 1, 2)
+
+// IsRecordType returns whether the provided value is a record type
+var IsRecordType = data.Applicative(func(args ...data.Value) data.Value {
+	_, ok := args[0].(data.RecordType)
+	return data.Bool(ok)
+}, 1)
@@ -0,0 +1,78 @@
+package builtin_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kode4food/ale/core/internal/builtin"
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+)
+
+func TestRecord(t *testing.T) {
+	as := assert.New(t)
+
+	rt := builtin.RecordType.Call(LS("point"), V(LS("x"), K("y")))
+	as.True(builtin.IsRecordType.Call(rt))
+	as.False(builtin.IsRecordType.Call(I(1)))
+
+	ctor := builtin.RecordConstructor.Call(rt).(data.Function)
+	as.Nil(ctor.CheckArity(2))
+	as.NotNil(ctor.CheckArity(1))
+
+	r := ctor.Call(I(1), I(2))
+	as.True(builtin.IsRecord.Call(r))
+	as.True(builtin.IsRecord.Call(r, rt))
+	as.False(builtin.IsRecord.Call(O(), rt))
+
+	getY := builtin.RecordAccessor.Call(rt, LS("y")).(data.Function)
+	as.Equal(I(2), getY.Call(r))
+
+	other := builtin.RecordType.Call(LS("point"), V(LS("x"), LS("y")))
+	as.False(builtin.IsRecord.Call(r, other))
+
+	defer as.ExpectPanic(fmt.Sprintf(builtin.ErrUnknownRecordField, "point", ":z"))
+	builtin.RecordAccessor.Call(rt, K("z"))
+}
+
+func TestRecordEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`
+		(define-record rec-point [x y])
+		(define p (->rec-point 1 2))
+		[(rec-point-x p) (rec-point-y p) (:x p) (get p :y) (p :x)]
+	`, V(I(1), I(2), I(1), I(2), I(1)))
+
+	as.EvalTo(`
+		(define-record rec-person [name age])
+		(object->rec-person {:name "Ale" :age 45 :extra true})
+	`, S(`#rec-person{:name "Ale" :age 45}`))
+
+	as.EvalTo(`
+		(define-record rec-pred [value])
+		[(rec-pred? (->rec-pred 1) (->rec-pred 2))
+		 (rec-pred? (->rec-pred 1) {:value 1})
+		 (!rec-pred? {:value 1})
+		 (is-rec-pred (->rec-pred 1))
+		 (record? (->rec-pred 1))
+		 (record-type? rec-pred)]
+	`, V(data.True, data.False, data.True, data.True, data.True, data.True))
+
+	as.EvalTo(`
+		(define-record rec-assoc [x y])
+		(let [p (->rec-assoc 1 2)]
+		  [(assoc p :x 10) (assoc p :z 3) (dissoc p :x)])
+	`, S(`[#rec-assoc{:x 10 :y 2} {:x 1 :y 2 :z 3} {:y 2}]`))
+
+	as.PanicWith(`
+		(define-record rec-wrong [x])
+		(define-record rec-other [x])
+		(rec-wrong-x (->rec-other 1))
+	`, fmt.Errorf(builtin.ErrRecordTypeMismatch, "rec-wrong", "#rec-other{:x 1}"))
+
+	as.PanicWith(`
+		(define-record rec-arity [x y])
+		(->rec-arity 1)
+	`, fmt.Errorf(data.ErrFixedArity, 2, 1))
+}
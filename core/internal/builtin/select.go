@@ -0,0 +1,150 @@
+package builtin
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/clock"
+	"github.com/kode4food/ale/internal/stream"
+)
+
+// selectableWriter is a channel's emit function that can also take part
+// in a select as the target of a write
+type selectableWriter struct {
+	data.Function
+	stream.Selectable
+}
+
+// Error messages
+const (
+	ErrNotSelectable  = "value can't be selected: %s"
+	ErrEmptySelect    = "select requires at least one operation"
+	ErrInvalidTimeout = "timeout must be a non-negative integer: %s"
+)
+
+// Alts completes at most one of several channel operations. An
+// operation is either a channel to read from, or a vector of a channel
+// and a value to write to it. The result is a vector of the value read
+// (or whether the write succeeded) and the channel that was chosen. If
+// a default is provided and no operation can complete immediately, the
+// result is the default and :default
+var Alts = data.Applicative(func(args ...data.Value) data.Value {
+	ops := args[0].(data.Sequence)
+	var ports data.Values
+	var cases []stream.SelectCase
+	for f, r, ok := ops.Split(); ok; f, r, ok = r.Split() {
+		if v, ok := f.(data.Vector); ok {
+			ch, _ := v.ElementAt(0)
+			val, _ := v.ElementAt(1)
+			ports = append(ports, ch)
+			cases = append(cases, writeCase(ch, val))
+			continue
+		}
+		ports = append(ports, f)
+		cases = append(cases, readCase(f))
+	}
+
+	idx, res := selectCases(cases, len(args) == 1)
+	if idx < 0 {
+		return data.NewVector(args[1], DefaultKey)
+	}
+	return data.NewVector(res, ports[idx])
+}, 1, 2)
+
+// Select is the primitive behind the select macro. It accepts a vector
+// of operations in the same form as alts, and whether a default clause
+// was provided. The result is a vector of the index of the operation
+// that was chosen (or -1 for the default) and its value
+var Select = data.Applicative(func(args ...data.Value) data.Value {
+	ops := args[0].(data.Vector)
+	cases := make([]stream.SelectCase, ops.Count())
+	for i := range cases {
+		op, _ := ops.ElementAt(i)
+		if v, ok := op.(data.Vector); ok {
+			ch, _ := v.ElementAt(0)
+			val, _ := v.ElementAt(1)
+			cases[i] = writeCase(ch, val)
+			continue
+		}
+		cases[i] = readCase(op)
+	}
+
+	block := len(args) == 1 || !data.Truthy(args[1])
+	idx, res := selectCases(cases, block)
+	return data.NewVector(data.Integer(idx), res)
+}, 1, 2)
+
+// timeout returns a channel that is closed after the specified number
+// of milliseconds. Reading from it will block until that happens
+func timeout(r *clock.Ref) data.Function {
+	return data.Applicative(func(args ...data.Value) data.Value {
+		ms, ok := args[0].(data.Integer)
+		if !ok || ms < 0 {
+			panic(fmt.Errorf(ErrInvalidTimeout, args[0]))
+		}
+		e, s := stream.NewChannel(0)
+		t := r.Current().NewTimer(time.Duration(ms) * time.Millisecond)
+		stop := make(chan struct{})
+		go func() {
+			select {
+			case <-t.C():
+			case <-stop:
+				t.Stop()
+			}
+			e.Close()
+		}()
+		var once sync.Once
+		closer := data.Applicative(func(_ ...data.Value) data.Value {
+			once.Do(func() { close(stop) })
+			return data.Nil
+		}, 0)
+		return makeChannel(bindSelectableWriter(e), closer, s)
+	}, 1)
+}
+
+func bindSelectableWriter(e stream.Emitter) data.Function {
+	w := bindWriter(e)
+	if s, ok := e.(stream.Selectable); ok {
+		return &selectableWriter{
+			Function:   w,
+			Selectable: s,
+		}
+	}
+	return w
+}
+
+func selectCases(cases []stream.SelectCase, block bool) (int, data.Value) {
+	if len(cases) == 0 && block {
+		panic(errors.New(ErrEmptySelect))
+	}
+	return stream.Select(cases, block)
+}
+
+func readCase(ch data.Value) stream.SelectCase {
+	return stream.SelectCase{
+		Channel: selectable(ch, stream.SequenceKey),
+	}
+}
+
+func writeCase(ch data.Value, v data.Value) stream.SelectCase {
+	return stream.SelectCase{
+		Channel: selectable(ch, stream.EmitKey),
+		Value:   v,
+		Write:   true,
+	}
+}
+
+func selectable(v data.Value, key data.Keyword) stream.Selectable {
+	if m, ok := v.(data.Mapped); ok {
+		if f, ok := m.Get(key); ok {
+			v = f
+		}
+	}
+	if s, ok := v.(stream.Selectable); ok {
+		return s
+	}
+	panic(fmt.Errorf(ErrNotSelectable, v))
+}
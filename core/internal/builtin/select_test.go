@@ -0,0 +1,75 @@
+package builtin_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kode4food/ale/core/internal/builtin"
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+)
+
+func TestAltsEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`
+		(let ([c1 (chan)] [c2 (chan)])
+		  (go ((:emit c2) :hello))
+		  (let [res (alts [c1 c2])]
+		    [(res 0) (eq (res 1) c2)]))
+	`, S("[:hello #t]"))
+	as.EvalTo(`
+		(let [ch (chan 1)]
+		  (let [res (alts [[ch 42]])]
+		    [(res 0) (first (:seq ch))]))
+	`, S("[#t 42]"))
+	as.EvalTo(`(alts [(chan)] :nothing)`, S("[:nothing :default]"))
+	as.EvalTo(`((alts [(timeout 10)]) 0)`, data.Nil)
+}
+
+func TestSelectEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`
+		(let [ch (chan)]
+		  (go ((:emit ch) 41))
+		  (select [[v ch] (inc v)]
+		          [[_ (timeout 1000)] :timeout]))
+	`, F(42))
+	as.EvalTo(`
+		(select [[v (chan)] v]
+		        [[_ (timeout 10)] :timeout])
+	`, K("timeout"))
+	as.EvalTo(`
+		(select [[v (chan)] v]
+		        [:default :nothing])
+	`, K("nothing"))
+	as.EvalTo(`
+		(let [ch (chan 1)]
+		  (select [:default :full]
+		          [[ok ch 99] (if ok (first (:seq ch)) :failed)]))
+	`, F(99))
+}
+
+func TestSelectMailboxEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`
+		(let* ([out    (chan)]
+		       [sender (spawn (lambda (mbox)
+		                        ((:emit out) (first mbox))))])
+		  (select [[ok sender :ping] ok])
+		  (first (:seq out)))
+	`, K("ping"))
+	as.EvalTo(`
+		(let [gen (generate (emit 1) (emit 2))]
+		  (select [[v gen] v]))
+	`, F(1))
+}
+
+func TestSelectErrors(t *testing.T) {
+	as := assert.New(t)
+	as.PanicWith(`(alts [99])`, fmt.Errorf(builtin.ErrNotSelectable, I(99)))
+	as.PanicWith(`(timeout -1)`, fmt.Errorf(builtin.ErrInvalidTimeout, I(-1)))
+	as.PanicWith(`(alts [])`, fmt.Errorf(builtin.ErrEmptySelect))
+	defer as.ExpectPanic("invalid select clause: [:blah]")
+	as.Eval(`(select [:blah])`)
+}
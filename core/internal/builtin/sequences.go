@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/sequence"
 )
 
 // Error messages
@@ -55,6 +56,47 @@ var Nth = data.Applicative(func(args ...data.Value) data.Value {
 	panic(errors.New(ErrIndexOutOfBounds))
 }, 2, 3)
 
+// Map returns a lazy sequence of the results of applying a function to
+// the elements of a sequence
+var Map = data.Applicative(func(args ...data.Value) data.Value {
+	fn := args[0].(data.Function)
+	return sequence.Map(args[1].(data.Sequence), fn)
+}, 2)
+
+// Filter returns a lazy sequence of the elements of a sequence for
+// which a function returns a truthy value
+var Filter = data.Applicative(func(args ...data.Value) data.Value {
+	fn := args[0].(data.Function)
+	return sequence.Filter(args[1].(data.Sequence), fn)
+}, 2)
+
+// Reduce combines the elements of a sequence using a function. If no
+// initial value is provided, the first element is used. The reduction
+// terminates early if the function returns a reduced value
+var Reduce = data.Applicative(func(args ...data.Value) data.Value {
+	fn := args[0].(data.Function)
+	var res data.Value
+	var it data.Iterator
+	if len(args) == 3 {
+		res = args[1]
+		it = data.MakeIterator(args[2].(data.Sequence))
+	} else {
+		it = data.MakeIterator(args[1].(data.Sequence))
+		f, ok := it.Next()
+		if !ok {
+			return fn.Call()
+		}
+		res = f
+	}
+	for f, ok := it.Next(); ok; f, ok = it.Next() {
+		res = fn.Call(res, f)
+		if r, ok := res.(*reduced); ok {
+			return r.value
+		}
+	}
+	return res
+}, 2, 3)
+
 // IsSeq returns whether the provided value is a sequence
 var IsSeq = data.Applicative(func(args ...data.Value) data.Value {
 	_, ok := args[0].(data.Sequence)
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/kode4food/ale/core/internal/builtin"
 	"github.com/kode4food/ale/data"
 	"github.com/kode4food/ale/internal/assert"
 	. "github.com/kode4food/ale/internal/assert/helpers"
@@ -108,3 +109,33 @@ func TestReverse(t *testing.T) {
 		(reverse (take 4 (range 1 1000)))
 	`, err)
 }
+
+func TestIterableEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`(reduce + [1 2 3 4])`, F(10))
+	as.EvalTo(`(reduce + 10 '(1 2 3 4))`, F(20))
+	as.EvalTo(`(reduce + [])`, F(0))
+	as.EvalTo(`(reduce str "abc")`, S("abc"))
+	as.EvalTo(`(length (reduce conj [] {:a 1 :b 2}))`, F(2))
+	as.EvalTo(`
+		(reduce (lambda (acc x)
+		          (if (> x 3) (reduced acc) (+ acc x)))
+		        (range))
+	`, F(6))
+	as.EvalTo(`(seq->vector (map (lambda (c) (str c c)) "ab"))`,
+		S(`["aa" "bb"]`))
+	as.EvalTo(`(seq->vector (filter odd? '(1 2 3)))`, S("[1 3]"))
+}
+
+func BenchmarkReduce(b *testing.B) {
+	in := make(data.Values, 1_000_000)
+	for i := range in {
+		in[i] = data.Integer(i)
+	}
+	v := data.NewVector(in...)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		builtin.Reduce.Call(builtin.Add, v)
+	}
+}
@@ -0,0 +1,244 @@
+package builtin
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/clock"
+	"github.com/kode4food/ale/internal/stream"
+)
+
+type clockValue struct {
+	clock.Clock
+}
+
+// Error messages
+const (
+	ErrInvalidDuration = "duration must be a non-negative integer: %s"
+	ErrInvalidInterval = "interval must be a positive integer: %s"
+	ErrUnknownClock    = "unknown clock: %s"
+	ErrNotFakeClock    = "the clock in use is not a fake clock"
+)
+
+// Clock names
+const (
+	RealClock = data.Keyword("real")
+	FakeClock = data.Keyword("fake")
+)
+
+// ClockFunctions returns the functions that measure time. They're all
+// measured by the Clock that the provided Ref holds, which use-clock
+// replaces. Environments that don't share a Ref can replace their Clocks
+// without affecting one another
+func ClockFunctions(r *clock.Ref) map[data.Name]data.Function {
+	return map[data.Name]data.Function{
+		"current-time":  currentTime(r),
+		"sleep":         sleep(r),
+		"after":         after(r),
+		"ticker":        ticker(r),
+		"every":         every(r),
+		"timeout":       timeout(r),
+		"scheduler":     scheduler(r),
+		"use-clock":     useClock(r),
+		"advance-clock": advanceClock(r),
+	}
+}
+
+// currentTime returns the current time in nanoseconds, as reported by
+// the clock in use
+func currentTime(r *clock.Ref) data.Function {
+	return data.Applicative(func(_ ...data.Value) data.Value {
+		return timeValue(r.Current().Now())
+	}, 0)
+}
+
+// sleep blocks for the specified number of milliseconds
+func sleep(r *clock.Ref) data.Function {
+	return data.Applicative(func(args ...data.Value) data.Value {
+		clock.Sleep(r.Current(), duration(args[0]))
+		return data.Nil
+	}, 1)
+}
+
+// after returns a channel that yields the current time once the
+// specified number of milliseconds have elapsed, and is then closed
+func after(r *clock.Ref) data.Function {
+	return data.Applicative(func(args ...data.Value) data.Value {
+		t := r.Current().NewTimer(duration(args[0]))
+		return makeGeneratedChannel(
+			stream.Generate(nil, func(emit stream.EmitFunc) {
+				emit(timeValue(<-t.C()))
+			}),
+		)
+	}, 1)
+}
+
+// ticker returns a channel that yields the current time every time
+// that the specified number of milliseconds elapses. Closing the
+// channel, or cancelling its sequence, stops the ticker
+func ticker(r *clock.Ref) data.Function {
+	return data.Applicative(func(args ...data.Value) data.Value {
+		return makeTicker(r.Current(), interval(args[0]))
+	}, 1)
+}
+
+// every returns a lazy sequence of the times at which each interval
+// elapsed. The underlying ticker is stopped when the sequence is
+// cancelled or abandoned
+func every(r *clock.Ref) data.Function {
+	return data.Applicative(func(args ...data.Value) data.Value {
+		t := makeTicker(r.Current(), interval(args[0]))
+		res, _ := t.Get(stream.SequenceKey)
+		return res
+	}, 1)
+}
+
+func makeTicker(c clock.Clock, d time.Duration) data.Object {
+	t := c.NewTimer(d)
+	return makeGeneratedChannel(
+		stream.Generate(nil, func(emit stream.EmitFunc) {
+			for {
+				at := <-t.C()
+				t = c.NewTimer(at.Add(d).Sub(c.Now()))
+				emit(timeValue(at))
+			}
+		}),
+	)
+}
+
+// scheduler returns a new scheduler for running delayed and recurring
+// jobs. If an error handler is provided, it is called with the error
+// and the job when a job fails
+func scheduler(r *clock.Ref) data.Function {
+	return data.Applicative(func(args ...data.Value) data.Value {
+		var onError clock.ErrorHandler
+		if len(args) == 1 {
+			handler := args[0].(data.Function)
+			onError = func(job *stream.Scope, err data.Error) {
+				handler.Call(err, job)
+			}
+		}
+		return clock.NewScheduler(r.Current(), onError)
+	}, 0, 1)
+}
+
+// Schedule runs a function once, after the specified number of
+// milliseconds. Returns a scope that can be cancelled to abandon it
+var Schedule = data.Applicative(func(args ...data.Value) data.Value {
+	s := args[0].(*clock.Scheduler)
+	return s.After(duration(args[1]), jobFunc(args[2]))
+}, 3)
+
+// ScheduleEvery runs a function every time that the specified number
+// of milliseconds elapses. Returns a scope that can be cancelled to
+// stop it
+var ScheduleEvery = data.Applicative(func(args ...data.Value) data.Value {
+	s := args[0].(*clock.Scheduler)
+	return s.Every(duration(args[1]), jobFunc(args[2]))
+}, 3)
+
+// ScheduleCron runs a function at the times matched by a cron
+// expression. Returns a scope that can be cancelled to stop it
+var ScheduleCron = data.Applicative(func(args ...data.Value) data.Value {
+	s := args[0].(*clock.Scheduler)
+	c, err := clock.ParseCron(args[1].(data.String).String())
+	if err != nil {
+		panic(err)
+	}
+	return s.Cron(c, jobFunc(args[2]))
+}, 3)
+
+// useClock replaces the clock that timers are measured by, returning
+// the clock that was previously in use. The clock may be :real, :fake,
+// or a clock that was returned by a previous call. A fake clock starts
+// at the current time and only moves when advanced by advance-clock
+func useClock(r *clock.Ref) data.Function {
+	return data.Applicative(func(args ...data.Value) data.Value {
+		prev := r.Current()
+		switch c := args[0].(type) {
+		case *clockValue:
+			r.Use(c.Clock)
+		case data.Keyword:
+			switch c {
+			case RealClock:
+				r.Use(clock.Real)
+			case FakeClock:
+				r.Use(clock.NewFake(prev.Now()))
+			default:
+				panic(fmt.Errorf(ErrUnknownClock, c))
+			}
+		default:
+			panic(fmt.Errorf(ErrUnknownClock, c))
+		}
+		return &clockValue{prev}
+	}, 1)
+}
+
+// advanceClock moves a fake clock forward by the specified number of
+// milliseconds, firing any timers that become due
+func advanceClock(r *clock.Ref) data.Function {
+	return data.Applicative(func(args ...data.Value) data.Value {
+		f, ok := r.Current().(*clock.Fake)
+		if !ok {
+			panic(errors.New(ErrNotFakeClock))
+		}
+		f.Advance(duration(args[0]))
+		return data.Nil
+	}, 1)
+}
+
+func duration(v data.Value) time.Duration {
+	if ms, ok := v.(data.Integer); ok && ms >= 0 {
+		return time.Duration(ms) * time.Millisecond
+	}
+	panic(fmt.Errorf(ErrInvalidDuration, v))
+}
+
+func interval(v data.Value) time.Duration {
+	if ms, ok := v.(data.Integer); ok && ms > 0 {
+		return time.Duration(ms) * time.Millisecond
+	}
+	panic(fmt.Errorf(ErrInvalidInterval, v))
+}
+
+func timeValue(t time.Time) data.Value {
+	return data.Integer(t.UnixNano())
+}
+
+func jobFunc(v data.Value) func() {
+	fn := v.(data.Function)
+	return func() {
+		fn.Call()
+	}
+}
+
+func makeGeneratedChannel(s data.Sequence) data.Object {
+	c := s.(stream.Canceller)
+	return data.NewObject(
+		data.NewCons(data.TypeKey, stream.ChannelType),
+		data.NewCons(stream.CloseKey,
+			data.Applicative(func(_ ...data.Value) data.Value {
+				c.Cancel()
+				return data.Nil
+			}, 0),
+		),
+		data.NewCons(stream.SequenceKey, s),
+	)
+}
+
+func (c *clockValue) Type() data.Name {
+	return "clock"
+}
+
+func (c *clockValue) Equal(v data.Value) bool {
+	if v, ok := v.(*clockValue); ok {
+		return c == v || c.Clock == v.Clock
+	}
+	return false
+}
+
+func (c *clockValue) String() string {
+	return data.DumpString(c)
+}
@@ -0,0 +1,109 @@
+package builtin_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kode4food/ale/core/internal/builtin"
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+)
+
+func TestSleepEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`
+		(let* ([start (current-time)]
+		       [_     (sleep 10)])
+		  (>= (- (current-time) start) 10000000))
+	`, data.True)
+	as.EvalTo(`
+		(with-fake-clock
+		  (let* ([start (current-time)]
+		         [_     (advance-clock 5000)])
+		    (- (current-time) start)))
+	`, I(5000000000))
+}
+
+func TestAfterEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`
+		(with-fake-clock
+		  (let* ([start (current-time)]
+		         [ch    (after 1000)])
+		    (advance-clock 1000)
+		    (let [s (:seq ch)]
+		      [(- (first s) start) (is-empty (rest s))])))
+	`, S("[1000000000 #t]"))
+	as.EvalTo(`
+		(with-fake-clock
+		  (let [ch (after 1000)]
+		    (select
+		      [[v ch] :fired]
+		      [:default :waiting])))
+	`, K("waiting"))
+}
+
+func TestTickerEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`
+		(with-fake-clock
+		  (let* ([start (current-time)]
+		         [t     (ticker 100)]
+		         [s     (:seq t)]
+		         [_     (advance-clock 100)]
+		         [t1    (first s)]
+		         [_     (advance-clock 100)]
+		         [t2    (first (rest s))])
+		    ((:close t))
+		    [(- t1 start) (- t2 start)]))
+	`, S("[100000000 200000000]"))
+	as.EvalTo(`
+		(with-fake-clock
+		  (let* ([s (every 50)]
+		         [_ (advance-clock 50)]
+		         [_ (first s)])
+		    (cancel (rest s))
+		    (cancelled? (rest s))))
+	`, data.True)
+}
+
+func TestSchedulerEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`
+		(with-fake-clock
+		  (let* ([s   (scheduler)]
+		         [out (chan)]
+		         [res (:seq out)]
+		         [job (schedule-every s 1000 (lambda () ((:emit out) :tick)))]
+		         [_   (schedule s 500 (lambda () ((:emit out) :once)))]
+		         [_   (advance-clock 500)]
+		         [r1  (first res)]
+		         [_   (advance-clock 500)]
+		         [r2  (first (rest res))])
+		    (cancel job)
+		    [r1 r2 (cancelled? job)]))
+	`, S("[:once :tick #t]"))
+	as.EvalTo(`
+		(with-fake-clock
+		  (let* ([out (chan)]
+		         [s   (scheduler (lambda (e job) ((:emit out) (:message e))))]
+		         [_   (schedule-cron s "@hourly" (lambda () (raise "boom")))]
+		         [_   (advance-clock 3600000)])
+		    (first (:seq out))))
+	`, S("boom"))
+}
+
+func TestClockErrors(t *testing.T) {
+	as := assert.New(t)
+	as.PanicWith(`(sleep -1)`, fmt.Errorf(builtin.ErrInvalidDuration, "-1"))
+	as.PanicWith(`(after "soon")`,
+		fmt.Errorf(builtin.ErrInvalidDuration, "soon"),
+	)
+	as.PanicWith(`(ticker 0)`, fmt.Errorf(builtin.ErrInvalidInterval, "0"))
+	as.PanicWith(`(every 0)`, fmt.Errorf(builtin.ErrInvalidInterval, "0"))
+	as.PanicWith(`(advance-clock 10)`, fmt.Errorf(builtin.ErrNotFakeClock))
+	as.PanicWith(`(use-clock :sundial)`,
+		fmt.Errorf(builtin.ErrUnknownClock, ":sundial"),
+	)
+}
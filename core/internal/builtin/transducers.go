@@ -0,0 +1,411 @@
+package builtin
+
+import (
+	"fmt"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/sequence"
+)
+
+type (
+	reduced struct {
+		value data.Value
+	}
+
+	transducerFunc func(rf data.Function) data.Function
+
+	transducerMaker func(args ...data.Value) transducerFunc
+)
+
+// Error messages
+const (
+	ErrUnknownTransducer   = "unknown transducer: %s"
+	ErrTransducerArguments = "transducer %s requires %d argument(s), got %d"
+)
+
+var transducers = map[data.Keyword]struct {
+	args int
+	make transducerMaker
+}{
+	"map":          {1, mapTransducer},
+	"filter":       {1, filterTransducer},
+	"keep":         {1, keepTransducer},
+	"mapcat":       {1, mapcatTransducer},
+	"take":         {1, takeTransducer},
+	"take-while":   {1, takeWhileTransducer},
+	"drop":         {1, dropTransducer},
+	"drop-while":   {1, dropWhileTransducer},
+	"distinct":     {0, distinctTransducer},
+	"dedupe":       {0, dedupeTransducer},
+	"interpose":    {1, interposeTransducer},
+	"partition":    {1, partitionTransducer},
+	"partition-by": {1, partitionByTransducer},
+}
+
+// Reduced wraps a value in order to signal that a reduction should
+// terminate early
+var Reduced = data.Applicative(func(args ...data.Value) data.Value {
+	return makeReduced(args[0])
+}, 1)
+
+// IsReduced returns whether the provided value is a reduced value
+var IsReduced = data.Applicative(func(args ...data.Value) data.Value {
+	_, ok := args[0].(*reduced)
+	return data.Bool(ok)
+}, 1)
+
+// Transducer returns one of the core's named transformations. The
+// transformation accepts a reducing function and returns a new one
+var Transducer = data.Applicative(func(args ...data.Value) data.Value {
+	k := args[0].(data.Keyword)
+	t, ok := transducers[k]
+	if !ok {
+		panic(fmt.Errorf(ErrUnknownTransducer, k))
+	}
+	if len(args)-1 != t.args {
+		panic(fmt.Errorf(ErrTransducerArguments, k, t.args, len(args)-1))
+	}
+	fn := t.make(args[1:]...)
+	return data.Applicative(func(args ...data.Value) data.Value {
+		return fn(args[0].(data.Function))
+	}, 1)
+}, 1, 2)
+
+// Transduce reduces a sequence using a reducing function that has been
+// transformed by a transducer. If no initial value is provided, the
+// reducing function is called without arguments to produce one
+var Transduce = data.Applicative(func(args ...data.Value) data.Value {
+	xform := args[0].(data.Function)
+	rf := xform.Call(args[1]).(data.Function)
+	var res data.Value
+	var coll data.Sequence
+	if len(args) == 4 {
+		res = args[2]
+		coll = args[3].(data.Sequence)
+	} else {
+		res = args[1].(data.Function).Call()
+		coll = args[2].(data.Sequence)
+	}
+	it := data.MakeIterator(coll)
+	for f, ok := it.Next(); ok; f, ok = it.Next() {
+		res = rf.Call(res, f)
+		if red, ok := res.(*reduced); ok {
+			res = red.value
+			break
+		}
+	}
+	return rf.Call(res)
+}, 3, 4)
+
+// Sequence returns a lazy sequence of the results of applying a
+// transducer to the elements of a sequence
+var Sequence = data.Applicative(func(args ...data.Value) data.Value {
+	if len(args) == 1 {
+		return args[0].(data.Sequence)
+	}
+
+	var buf data.Values
+	collect := data.Applicative(func(args ...data.Value) data.Value {
+		if len(args) == 2 {
+			buf = append(buf, args[1])
+		}
+		return data.Nil
+	}, 0, 2)
+
+	rf := args[0].(data.Function).Call(collect).(data.Function)
+	input := args[1].(data.Sequence)
+	done := false
+
+	var resolver sequence.LazyResolver
+	resolver = func() (data.Value, data.Sequence, bool) {
+		for len(buf) == 0 && !done {
+			f, r, ok := input.Split()
+			if !ok {
+				rf.Call(data.Nil)
+				done = true
+				break
+			}
+			input = r
+			if _, ok := rf.Call(data.Nil, f).(*reduced); ok {
+				rf.Call(data.Nil)
+				done = true
+			}
+		}
+		if len(buf) == 0 {
+			return data.Nil, data.EmptyList, false
+		}
+		f := buf[0]
+		buf = buf[1:]
+		return f, sequence.NewLazy(resolver), true
+	}
+	return sequence.NewLazy(resolver)
+}, 1, 2)
+
+func makeReduced(v data.Value) data.Value {
+	if _, ok := v.(*reduced); ok {
+		return v
+	}
+	return &reduced{value: v}
+}
+
+func unreduced(v data.Value) data.Value {
+	if r, ok := v.(*reduced); ok {
+		return r.value
+	}
+	return v
+}
+
+func (r *reduced) Type() data.Name {
+	return "reduced"
+}
+
+func (r *reduced) Equal(v data.Value) bool {
+	if v, ok := v.(*reduced); ok {
+		return r == v || r.value.Equal(v.value)
+	}
+	return false
+}
+
+func (r *reduced) String() string {
+	return data.DumpString(r)
+}
+
+// makeReducer returns a reducing function that performs the provided
+// step, deferring initialization and completion to rf
+func makeReducer(
+	rf data.Function, step func(res, value data.Value) data.Value,
+) data.Function {
+	return makeCompletingReducer(rf, step, nil)
+}
+
+// makeCompletingReducer returns a reducing function that performs the
+// provided step, and that flushes any pending state before completion
+func makeCompletingReducer(
+	rf data.Function,
+	step func(res, value data.Value) data.Value,
+	complete func(res data.Value) data.Value,
+) data.Function {
+	return data.Applicative(func(args ...data.Value) data.Value {
+		switch len(args) {
+		case 0:
+			return rf.Call()
+		case 1:
+			res := args[0]
+			if complete != nil {
+				res = complete(res)
+			}
+			return rf.Call(res)
+		default:
+			return step(args[0], args[1])
+		}
+	}, 0, 2)
+}
+
+func mapTransducer(args ...data.Value) transducerFunc {
+	fn := args[0].(data.Function)
+	return func(rf data.Function) data.Function {
+		return makeReducer(rf, func(res, value data.Value) data.Value {
+			return rf.Call(res, fn.Call(value))
+		})
+	}
+}
+
+func filterTransducer(args ...data.Value) transducerFunc {
+	pred := args[0].(data.Function)
+	return func(rf data.Function) data.Function {
+		return makeReducer(rf, func(res, value data.Value) data.Value {
+			if data.Truthy(pred.Call(value)) {
+				return rf.Call(res, value)
+			}
+			return res
+		})
+	}
+}
+
+func keepTransducer(args ...data.Value) transducerFunc {
+	fn := args[0].(data.Function)
+	return func(rf data.Function) data.Function {
+		return makeReducer(rf, func(res, value data.Value) data.Value {
+			if v := fn.Call(value); v != data.Nil {
+				return rf.Call(res, v)
+			}
+			return res
+		})
+	}
+}
+
+func mapcatTransducer(args ...data.Value) transducerFunc {
+	fn := args[0].(data.Function)
+	return func(rf data.Function) data.Function {
+		return makeReducer(rf, func(res, value data.Value) data.Value {
+			s := fn.Call(value).(data.Sequence)
+			for f, r, ok := s.Split(); ok; f, r, ok = r.Split() {
+				res = rf.Call(res, f)
+				if _, ok := res.(*reduced); ok {
+					break
+				}
+			}
+			return res
+		})
+	}
+}
+
+func takeTransducer(args ...data.Value) transducerFunc {
+	count := int(args[0].(data.Integer))
+	return func(rf data.Function) data.Function {
+		remaining := count
+		return makeReducer(rf, func(res, value data.Value) data.Value {
+			if remaining > 0 {
+				res = rf.Call(res, value)
+			}
+			remaining--
+			if remaining <= 0 {
+				return makeReduced(res)
+			}
+			return res
+		})
+	}
+}
+
+func takeWhileTransducer(args ...data.Value) transducerFunc {
+	pred := args[0].(data.Function)
+	return func(rf data.Function) data.Function {
+		return makeReducer(rf, func(res, value data.Value) data.Value {
+			if data.Truthy(pred.Call(value)) {
+				return rf.Call(res, value)
+			}
+			return makeReduced(res)
+		})
+	}
+}
+
+func dropTransducer(args ...data.Value) transducerFunc {
+	count := int(args[0].(data.Integer))
+	return func(rf data.Function) data.Function {
+		remaining := count
+		return makeReducer(rf, func(res, value data.Value) data.Value {
+			if remaining > 0 {
+				remaining--
+				return res
+			}
+			return rf.Call(res, value)
+		})
+	}
+}
+
+func dropWhileTransducer(args ...data.Value) transducerFunc {
+	pred := args[0].(data.Function)
+	return func(rf data.Function) data.Function {
+		dropping := true
+		return makeReducer(rf, func(res, value data.Value) data.Value {
+			if dropping && data.Truthy(pred.Call(value)) {
+				return res
+			}
+			dropping = false
+			return rf.Call(res, value)
+		})
+	}
+}
+
+func distinctTransducer(...data.Value) transducerFunc {
+	return func(rf data.Function) data.Function {
+		var seen data.Object = data.EmptyObject
+		return makeReducer(rf, func(res, value data.Value) data.Value {
+			if _, ok := seen.Get(value); ok {
+				return res
+			}
+			seen = seen.Put(data.NewCons(value, data.True)).(data.Object)
+			return rf.Call(res, value)
+		})
+	}
+}
+
+func dedupeTransducer(...data.Value) transducerFunc {
+	return func(rf data.Function) data.Function {
+		var prev data.Value
+		return makeReducer(rf, func(res, value data.Value) data.Value {
+			if prev != nil && prev.Equal(value) {
+				return res
+			}
+			prev = value
+			return rf.Call(res, value)
+		})
+	}
+}
+
+func interposeTransducer(args ...data.Value) transducerFunc {
+	sep := args[0]
+	return func(rf data.Function) data.Function {
+		started := false
+		return makeReducer(rf, func(res, value data.Value) data.Value {
+			if started {
+				res = rf.Call(res, sep)
+				if _, ok := res.(*reduced); ok {
+					return res
+				}
+			}
+			started = true
+			return rf.Call(res, value)
+		})
+	}
+}
+
+func partitionTransducer(args ...data.Value) transducerFunc {
+	count := int(args[0].(data.Integer))
+	return func(rf data.Function) data.Function {
+		var buf data.Values
+		return makeCompletingReducer(rf,
+			func(res, value data.Value) data.Value {
+				buf = append(buf, value)
+				if len(buf) < count {
+					return res
+				}
+				p := data.NewList(buf...)
+				buf = nil
+				return rf.Call(res, p)
+			},
+			func(res data.Value) data.Value {
+				if len(buf) == 0 {
+					return res
+				}
+				p := data.NewList(buf...)
+				buf = nil
+				return unreduced(rf.Call(res, p))
+			},
+		)
+	}
+}
+
+func partitionByTransducer(args ...data.Value) transducerFunc {
+	fn := args[0].(data.Function)
+	return func(rf data.Function) data.Function {
+		var buf data.Values
+		var prev data.Value
+		return makeCompletingReducer(rf,
+			func(res, value data.Value) data.Value {
+				k := fn.Call(value)
+				if len(buf) == 0 || prev.Equal(k) {
+					buf = append(buf, value)
+					prev = k
+					return res
+				}
+				p := data.NewList(buf...)
+				buf = nil
+				res = rf.Call(res, p)
+				if _, ok := res.(*reduced); !ok {
+					buf = data.Values{value}
+					prev = k
+				}
+				return res
+			},
+			func(res data.Value) data.Value {
+				if len(buf) == 0 {
+					return res
+				}
+				p := data.NewList(buf...)
+				buf = nil
+				return unreduced(rf.Call(res, p))
+			},
+		)
+	}
+}
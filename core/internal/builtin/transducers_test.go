@@ -0,0 +1,119 @@
+package builtin_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kode4food/ale/core/internal/builtin"
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+)
+
+func TestTransduce(t *testing.T) {
+	as := assert.New(t)
+
+	inc := data.Applicative(func(args ...data.Value) data.Value {
+		return args[0].(data.Integer) + 1
+	}, 1)
+	xform := builtin.Transducer.Call(K("map"), inc)
+	res := builtin.Transduce.Call(xform, builtin.Add, V(I(1), I(2), I(3)))
+	as.Equal(I(9), res)
+
+	res = builtin.Transduce.Call(xform, builtin.Add, I(10), V(I(1), I(2)))
+	as.Equal(I(15), res)
+
+	err := fmt.Errorf(builtin.ErrUnknownTransducer, K("blah"))
+	defer as.ExpectPanic(err.Error())
+	builtin.Transducer.Call(K("blah"))
+}
+
+func TestTransducersEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`(transduce (map inc) + [1 2 3])`, F(9))
+	as.EvalTo(`(transduce (filter even?) + 100 (range 10))`, F(120))
+	as.EvalTo(`
+		(transduce (xform (filter odd?) (map (lambda (x) (* x x))) (take 3))
+		           conj [] (range))
+	`, S("[1 9 25]"))
+	as.EvalTo(`(into [] (take-while (lambda (x) (< x 4))) (range))`,
+		S("[0 1 2 3]"))
+	as.EvalTo(`(into [] (drop 2) [1 2 3 4])`, S("[3 4]"))
+	as.EvalTo(`(into [] (drop-while odd?) [1 3 4 5])`, S("[4 5]"))
+	as.EvalTo(`(into [] (keep first) [[1] [] [2]])`, S("[1 2]"))
+	as.EvalTo(`(into [] (mapcat reverse) [[1 2] [3 4]])`, S("[2 1 4 3]"))
+	as.EvalTo(`(into [] (distinct) [1 2 1 3 2])`, S("[1 2 3]"))
+	as.EvalTo(`(into [] (dedupe) [1 1 2 2 1])`, S("[1 2 1]"))
+	as.EvalTo(`(into [] (interpose :x) [1 2 3])`, S("[1 :x 2 :x 3]"))
+	as.EvalTo(`(into [] (partition 2) [1 2 3 4 5])`,
+		S("[(1 2) (3 4) (5)]"))
+	as.EvalTo(`(into [] (partition-by odd?) [1 3 2 4 5])`,
+		S("[(1 3) (2 4) (5)]"))
+	as.EvalTo(`(into [] (xform (partition 2) (take 1)) [1 2 3 4 5])`,
+		S("[(1 2)]"))
+}
+
+func TestIntoEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`(into [1] [2 3])`, S("[1 2 3]"))
+	as.EvalTo(`(into '(1) [2 3])`, S("(3 2 1)"))
+	as.EvalTo(`(into {} [(cons :a 1) (cons :b 2)])`, S("{:a 1 :b 2}"))
+	as.EvalTo(`
+		(let [o (into {:a 1} (map (lambda (x) (cons x (* x 2)))) [1 2])]
+		  [(:a o) (get o 2) (length o)])
+	`, S("[1 4 3]"))
+}
+
+func TestSequenceEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`(seq->vector (sequence [1 2 3]))`, S("[1 2 3]"))
+	as.EvalTo(`
+		(seq->vector (take 4 (sequence (map inc) (range))))
+	`, S("[1 2 3 4]"))
+	as.EvalTo(`
+		(seq->vector (sequence (xform (filter even?) (partition 2)) (range 9)))
+	`, S("[(0 2) (4 6) (8)]"))
+	as.EvalTo(`(reduced? (reduced 1))`, data.True)
+	as.EvalTo(`
+		(transduce
+		  (lambda (rf)
+		    (lambda [() (rf)]
+		            [(res) (rf res)]
+		            [(res value)
+		               (if (> value 2)
+		                   (reduced res)
+		                   (rf res value))]))
+		  + (range))
+	`, F(3))
+}
+
+func TestChanTransducerEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`
+		(let [ch (chan 0 (xform (map inc) (filter even?)))]
+		  (go (apply (:emit ch) (range 10))
+		      ((:close ch)))
+		  (seq->vector (:seq ch)))
+	`, S("[2 4 6 8 10]"))
+	as.EvalTo(`
+		(let [ch (chan 0 (partition 2))]
+		  (go ((:emit ch) 1 2 3)
+		      ((:close ch)))
+		  (seq->vector (:seq ch)))
+	`, S("[(1 2) (3)]"))
+	as.EvalTo(`
+		(let [ch (chan 0 (take 2))]
+		  (go ((:emit ch) 1 2 3 4))
+		  (seq->vector (:seq ch)))
+	`, S("[1 2]"))
+
+	// closing doesn't wait for a write that's blocked on the reader
+	as.EvalTo(`
+		(let* ([ch (chan 0 (map inc))]
+		       [s (:seq ch)])
+		  (go ((:emit ch) 1 2 3))
+		  (first s)
+		  ((:close ch))
+		  (seq->vector s))
+	`, S("[2 3 4]"))
+}
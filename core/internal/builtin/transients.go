@@ -0,0 +1,93 @@
+package builtin
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kode4food/ale/data"
+)
+
+// Error messages
+const (
+	ErrTransientPairs  = "transient assoc! requires key/value pairs"
+	ErrTransientIndex  = "transient vector index must be an integer: %s"
+	ErrTransientObject = "value is not a transient object: %s"
+)
+
+// Transient returns a mutable, single-owner version of an object or
+// vector
+var Transient = data.Applicative(func(args ...data.Value) data.Value {
+	s, ok := args[0].(data.Sequence)
+	if !ok {
+		panic(fmt.Errorf(data.ErrTransientRequired, args[0]))
+	}
+	res, err := data.NewTransient(s)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}, 1)
+
+// Persistent freezes a transient and returns its persistent contents
+var Persistent = data.Applicative(func(args ...data.Value) data.Value {
+	return args[0].(data.Transient).Persistent()
+}, 1)
+
+// AssocBang associates keys with values in a transient object, or
+// indexes with values in a transient vector
+var AssocBang = data.Applicative(func(args ...data.Value) data.Value {
+	t := args[0].(data.Transient)
+	kv := args[1:]
+	if len(kv)%2 != 0 {
+		panic(errors.New(ErrTransientPairs))
+	}
+	for i := 0; i < len(kv); i += 2 {
+		switch t := t.(type) {
+		case data.TransientObject:
+			t.Put(data.NewCons(kv[i], kv[i+1]))
+		case data.TransientVector:
+			t.Set(transientIndex(kv[i]), kv[i+1])
+		}
+	}
+	return t
+}, 3, data.OrMore)
+
+func transientIndex(v data.Value) int {
+	if i, ok := v.(data.Integer); ok {
+		return int(i)
+	}
+	panic(fmt.Errorf(ErrTransientIndex, v))
+}
+
+// ConjBang appends values to a transient vector, or adds pairs to a
+// transient object
+var ConjBang = data.Applicative(func(args ...data.Value) data.Value {
+	t := args[0].(data.Transient)
+	for _, v := range args[1:] {
+		switch t := t.(type) {
+		case data.TransientObject:
+			t.Put(v.(data.Pair))
+		case data.TransientVector:
+			t.Append(v)
+		}
+	}
+	return t
+}, 1, data.OrMore)
+
+// DissocBang removes keys from a transient object
+var DissocBang = data.Applicative(func(args ...data.Value) data.Value {
+	t, ok := args[0].(data.TransientObject)
+	if !ok {
+		panic(fmt.Errorf(ErrTransientObject, args[0]))
+	}
+	for _, k := range args[1:] {
+		t.Remove(k)
+	}
+	return t
+}, 1, data.OrMore)
+
+// IsTransient returns whether the provided value is a transient
+var IsTransient = data.Applicative(func(args ...data.Value) data.Value {
+	_, ok := args[0].(data.Transient)
+	return data.Bool(ok)
+}, 1)
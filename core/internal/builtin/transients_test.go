@@ -0,0 +1,57 @@
+package builtin_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kode4food/ale/core/internal/builtin"
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+)
+
+func TestTransients(t *testing.T) {
+	as := assert.New(t)
+
+	tr := builtin.Transient.Call(O(C(K("a"), I(1))))
+	as.True(builtin.IsTransient.Call(tr))
+	as.False(builtin.IsTransient.Call(O()))
+	builtin.AssocBang.Call(tr, K("b"), I(2), K("c"), I(3))
+	builtin.ConjBang.Call(tr, C(K("d"), I(4)))
+	builtin.DissocBang.Call(tr, K("a"))
+	as.String("{:b 2 :c 3 :d 4}", builtin.Persistent.Call(tr))
+
+	defer as.ExpectPanic(fmt.Sprintf(data.ErrTransientRequired, "(1)"))
+	builtin.Transient.Call(L(I(1)))
+}
+
+func TestTransientsEval(t *testing.T) {
+	as := assert.New(t)
+	as.EvalTo(`
+		(let [t (transient [1 2])]
+		  (conj! t 3 4)
+		  (assoc! t 0 10)
+		  [(transient? t) (persistent! t)])
+	`, V(data.True, V(I(10), I(2), I(3), I(4))))
+
+	as.EvalTo(`
+		(let [t (transient {})]
+		  (persistent! (dissoc! (assoc! t :a 1 :b 2) :b)))
+	`, O(C(K("a"), I(1))))
+
+	as.EvalTo(`(seq->object '(:a 1 :b 2 :a 3))`, O(
+		C(K("a"), I(1)),
+		C(K("b"), I(2)),
+	))
+
+	as.PanicWith(`
+		(let [t (transient [])]
+		  (persistent! t)
+		  (conj! t 1))
+	`, errors.New(data.ErrTransientFrozen))
+
+	as.PanicWith(`(dissoc! [1] 0)`,
+		fmt.Errorf(builtin.ErrTransientObject, "[1]"),
+	)
+}
@@ -0,0 +1,76 @@
+package data
+
+import "fmt"
+
+type (
+	// Error is an error that is also an Object, allowing the code that
+	// recovers from it to inspect its properties rather than only its
+	// message. By convention, an Error has a :type keyword and a
+	// :message string
+	Error interface {
+		error
+		Object
+		errorValue() // marker
+	}
+
+	errorValue struct {
+		Object
+	}
+)
+
+// Standard Error Keys
+const (
+	MessageKey = Keyword("message")
+	CauseKey   = Keyword("cause")
+)
+
+// NewError instantiates an Error of the given type and message, with
+// any additional properties that are provided
+func NewError(typ Keyword, msg string, pairs ...Pair) Error {
+	props := append(Pairs{
+		NewCons(TypeKey, typ),
+		NewCons(MessageKey, String(msg)),
+	}, pairs...)
+	return &errorValue{Object: NewObject(props...)}
+}
+
+// MakeError wraps an Object's properties as an Error
+func MakeError(o Object) Error {
+	if e, ok := o.(Error); ok {
+		return e
+	}
+	return &errorValue{Object: o}
+}
+
+// ErrorCause converts a value recovered from a panic into a Value that
+// can be stored as the cause of an Error
+func ErrorCause(rec interface{}) Value {
+	switch rec := rec.(type) {
+	case Value:
+		return rec
+	case error:
+		return String(rec.Error())
+	default:
+		return String(fmt.Sprint(rec))
+	}
+}
+
+func (*errorValue) errorValue() {}
+
+// Error returns the Error's message, or its properties if it has none
+func (e *errorValue) Error() string {
+	if m, ok := e.Get(MessageKey); ok {
+		if s, ok := m.(String); ok {
+			return string(s)
+		}
+		return m.String()
+	}
+	return e.Object.String()
+}
+
+func (e *errorValue) Equal(v Value) bool {
+	if v, ok := v.(*errorValue); ok {
+		return e == v || e.Object.Equal(v.Object)
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package data_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+)
+
+func TestError(t *testing.T) {
+	as := assert.New(t)
+
+	e := data.NewError(K("timeout"), "timed out", C(K("after"), I(10)))
+	as.Equal("timed out", e.Error())
+	as.Equal(K("timeout"), as.MustGet(e, data.TypeKey))
+	as.Equal(I(10), as.MustGet(e, K("after")))
+	as.True(e.Equal(e))
+	as.False(e.Equal(data.NewError(K("timeout"), "other")))
+
+	var o data.Object = e
+	as.Equal(e, data.MakeError(o))
+
+	e = data.MakeError(data.NewObject(C(K("type"), K("bare"))))
+	as.Contains(":type :bare", S(e.Error()))
+}
+
+func TestErrorCause(t *testing.T) {
+	as := assert.New(t)
+	as.Equal(S("boom"), data.ErrorCause(errors.New("boom")))
+	as.Equal(I(3), data.ErrorCause(I(3)))
+	as.Equal(S("99"), data.ErrorCause(99))
+}
@@ -0,0 +1,151 @@
+package data
+
+import (
+	"fmt"
+	"sync"
+)
+
+type (
+	// Hierarchy tracks parent/child relationships between Keywords,
+	// allowing dispatch values to be related to one another
+	Hierarchy interface {
+		hierarchy() // marker
+		Value
+		Derive(child Keyword, parent Keyword) error
+		Parents(Keyword) []Keyword
+		Ancestors(Keyword) []Keyword
+		IsA(child Value, parent Value) bool
+		Version() uint64
+	}
+
+	hierarchy struct {
+		sync.RWMutex
+		parents map[Keyword][]Keyword
+		version uint64
+	}
+)
+
+// Error messages
+const (
+	ErrCyclicDerivation = "cyclic derivation: %s is already an ancestor of %s"
+)
+
+// NewHierarchy instantiates a new, empty Hierarchy
+func NewHierarchy() Hierarchy {
+	return &hierarchy{
+		parents: map[Keyword][]Keyword{},
+	}
+}
+
+func (*hierarchy) hierarchy() {}
+
+// Derive establishes a parent/child relationship between two Keywords
+func (h *hierarchy) Derive(child Keyword, parent Keyword) error {
+	h.Lock()
+	defer h.Unlock()
+
+	if child == parent || h.isAncestor(parent, child) {
+		return fmt.Errorf(ErrCyclicDerivation, child, parent)
+	}
+	for _, p := range h.parents[child] {
+		if p == parent {
+			return nil
+		}
+	}
+	h.parents[child] = append(h.parents[child], parent)
+	h.version++
+	return nil
+}
+
+func (h *hierarchy) Parents(k Keyword) []Keyword {
+	h.RLock()
+	defer h.RUnlock()
+	p := h.parents[k]
+	res := make([]Keyword, len(p))
+	copy(res, p)
+	return res
+}
+
+// Ancestors returns all the ancestors of a Keyword, nearest first
+func (h *hierarchy) Ancestors(k Keyword) []Keyword {
+	h.RLock()
+	defer h.RUnlock()
+	return h.ancestors(k)
+}
+
+func (h *hierarchy) ancestors(k Keyword) []Keyword {
+	var res []Keyword
+	seen := map[Keyword]bool{}
+	queue := h.parents[k]
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		res = append(res, p)
+		queue = append(queue, h.parents[p]...)
+	}
+	return res
+}
+
+func (h *hierarchy) isAncestor(child Keyword, ancestor Keyword) bool {
+	for _, a := range h.ancestors(child) {
+		if a == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// IsA returns whether the child is equal to, or derived from, the
+// parent. Vectors are compared element by element
+func (h *hierarchy) IsA(child Value, parent Value) bool {
+	if child.Equal(parent) {
+		return true
+	}
+	switch c := child.(type) {
+	case Keyword:
+		if p, ok := parent.(Keyword); ok {
+			h.RLock()
+			defer h.RUnlock()
+			return h.isAncestor(c, p)
+		}
+	case Vector:
+		if p, ok := parent.(Vector); ok && c.Count() == p.Count() {
+			for i := 0; i < c.Count(); i++ {
+				cv, _ := c.ElementAt(i)
+				pv, _ := p.ElementAt(i)
+				if !h.IsA(cv, pv) {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// Version returns a number that changes every time the Hierarchy is
+// modified
+func (h *hierarchy) Version() uint64 {
+	h.RLock()
+	defer h.RUnlock()
+	return h.version
+}
+
+func (h *hierarchy) Type() Name {
+	return "hierarchy"
+}
+
+func (h *hierarchy) Equal(v Value) bool {
+	if v, ok := v.(*hierarchy); ok {
+		return h == v
+	}
+	return false
+}
+
+func (h *hierarchy) String() string {
+	return DumpString(h)
+}
@@ -0,0 +1,137 @@
+package data
+
+import "unicode/utf8"
+
+type (
+	// Iterator visits the elements of a Sequence in order, without
+	// allocating a rest Sequence for each of them
+	Iterator interface {
+		Next() (Value, bool)
+	}
+
+	// Iterable is implemented by Sequences that can provide an Iterator
+	// that is cheaper than repeatedly splitting them
+	Iterable interface {
+		Iterate() Iterator
+	}
+
+	splitIterator struct {
+		seq Sequence
+	}
+
+	vectorIterator struct {
+		values vector
+		index  int
+	}
+
+	listIterator struct {
+		next List
+	}
+
+	stringIterator struct {
+		str string
+		pos int
+	}
+
+	objectIterator struct {
+		stack []*object
+	}
+
+	recordIterator struct {
+		record *record
+		index  int
+	}
+)
+
+func (i *splitIterator) Next() (Value, bool) {
+	f, r, ok := i.seq.Split()
+	if ok {
+		i.seq = r
+	}
+	return f, ok
+}
+
+// Iterate returns an Iterator over the elements of the Vector
+func (v vector) Iterate() Iterator {
+	return &vectorIterator{values: v}
+}
+
+func (i *vectorIterator) Next() (Value, bool) {
+	if i.index < len(i.values) {
+		res := i.values[i.index]
+		i.index++
+		return res, true
+	}
+	return Nil, false
+}
+
+// Iterate returns an Iterator over the elements of the List
+func (l *list) Iterate() Iterator {
+	return &listIterator{next: l}
+}
+
+// Iterate returns an Iterator over the elements of the empty List
+func (*nilValue) Iterate() Iterator {
+	return &listIterator{next: EmptyList}
+}
+
+func (i *listIterator) Next() (Value, bool) {
+	if l, ok := i.next.(*list); ok {
+		i.next = l.rest
+		return l.first, true
+	}
+	return Nil, false
+}
+
+// Iterate returns an Iterator over the characters of the String
+func (s String) Iterate() Iterator {
+	return &stringIterator{str: string(s)}
+}
+
+func (i *stringIterator) Next() (Value, bool) {
+	if r, w := utf8.DecodeRuneInString(i.str[i.pos:]); w > 0 {
+		i.pos += w
+		return String(r), true
+	}
+	return Nil, false
+}
+
+// Iterate returns an Iterator over the pairs of the Object, in the
+// same order that splitting the Object would produce them
+func (o *object) Iterate() Iterator {
+	return &objectIterator{stack: []*object{o}}
+}
+
+// Iterate returns an Iterator over the pairs of the empty Object
+func (*emptyObject) Iterate() Iterator {
+	return &objectIterator{}
+}
+
+func (i *objectIterator) Next() (Value, bool) {
+	l := len(i.stack)
+	if l == 0 {
+		return Nil, false
+	}
+	o := i.stack[l-1]
+	i.stack = i.stack[:l-1]
+	for j := bucketSize - 1; j >= 0; j-- {
+		if c := o.children[j]; c != nil {
+			i.stack = append(i.stack, c)
+		}
+	}
+	return o.pair, true
+}
+
+// Iterate returns an Iterator over the fields of the Record, as Pairs
+func (r *record) Iterate() Iterator {
+	return &recordIterator{record: r}
+}
+
+func (i *recordIterator) Next() (Value, bool) {
+	if i.index < len(i.record.values) {
+		res := i.record.pairAt(i.index)
+		i.index++
+		return res, true
+	}
+	return Nil, false
+}
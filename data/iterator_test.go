@@ -0,0 +1,133 @@
+package data_test
+
+import (
+	"testing"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+	"github.com/kode4food/ale/internal/sequence"
+)
+
+func iterated(s data.Sequence) data.Values {
+	var res data.Values
+	it := data.MakeIterator(s)
+	for f, ok := it.Next(); ok; f, ok = it.Next() {
+		res = append(res, f)
+	}
+	return res
+}
+
+func split(s data.Sequence) data.Values {
+	var res data.Values
+	for f, r, ok := s.Split(); ok; f, r, ok = r.Split() {
+		res = append(res, f)
+	}
+	return res
+}
+
+func TestIterators(t *testing.T) {
+	as := assert.New(t)
+
+	as.Equal(data.Values{I(1), I(2), I(3)}, iterated(V(I(1), I(2), I(3))))
+	as.Equal(data.Values{I(1), I(2), I(3)}, iterated(L(I(1), I(2), I(3))))
+	as.Equal(data.Values{S("h"), S("é"), S("y")}, iterated(S("héy")))
+	as.Nil(iterated(data.EmptyVector))
+	as.Nil(iterated(data.EmptyList))
+	as.Nil(iterated(data.EmptyString))
+	as.Nil(iterated(data.EmptyObject))
+
+	o := data.EmptyObject.Put(C(K("a"), I(1))).(data.Object)
+	for i := 0; i < 100; i++ {
+		o = o.Put(C(I(int64(i)), S("value"))).(data.Object)
+	}
+	as.Equal(split(o), iterated(o))
+	as.Equal(101, len(iterated(o)))
+
+	r := data.NewRecordType("point", K("x"), K("y"), K("z")).New(
+		I(1), I(2), I(3),
+	)
+	as.Equal(split(r), iterated(r))
+	as.Equal(3, len(iterated(r)))
+
+	lazy := sequence.NewLazy(func() (data.Value, data.Sequence, bool) {
+		return I(1), L(I(2)), true
+	})
+	as.Equal(data.Values{I(1), I(2)}, iterated(lazy))
+}
+
+func TestIteratorIndependence(t *testing.T) {
+	as := assert.New(t)
+
+	v := V(I(1), I(2))
+	i1 := v.Iterate()
+	i2 := v.Iterate()
+	f, _ := i1.Next()
+	as.Equal(I(1), f)
+	f, _ = i1.Next()
+	as.Equal(I(2), f)
+	f, _ = i2.Next()
+	as.Equal(I(1), f)
+	_, ok := i1.Next()
+	as.False(ok)
+}
+
+func makeBenchVector() data.Vector {
+	res := make(data.Values, 1_000_000)
+	for i := range res {
+		res[i] = data.Integer(i)
+	}
+	return data.NewVector(res...)
+}
+
+func BenchmarkVectorSplit(b *testing.B) {
+	v := makeBenchVector()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		var sum data.Integer
+		for f, r, ok := v.Split(); ok; f, r, ok = r.Split() {
+			sum += f.(data.Integer)
+		}
+	}
+}
+
+func BenchmarkVectorIterate(b *testing.B) {
+	v := makeBenchVector()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		var sum data.Integer
+		it := v.Iterate()
+		for f, ok := it.Next(); ok; f, ok = it.Next() {
+			sum += f.(data.Integer)
+		}
+	}
+}
+
+func BenchmarkObjectSplit(b *testing.B) {
+	o := data.Object(data.EmptyObject)
+	for i := 0; i < 10_000; i++ {
+		o = o.Put(C(I(int64(i)), I(int64(i)))).(data.Object)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for _, r, ok := o.Split(); ok; _, r, ok = r.Split() {
+		}
+	}
+}
+
+func BenchmarkObjectIterate(b *testing.B) {
+	o := data.Object(data.EmptyObject)
+	for i := 0; i < 10_000; i++ {
+		o = o.Put(C(I(int64(i)), I(int64(i)))).(data.Object)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		it := o.Iterate()
+		for _, ok := it.Next(); ok; _, ok = it.Next() {
+		}
+	}
+}
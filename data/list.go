@@ -8,6 +8,7 @@ type (
 		RandomAccess
 		Prepender
 		Reverser
+		Iterable
 		Caller
 	}
 
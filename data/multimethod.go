@@ -0,0 +1,231 @@
+package data
+
+import (
+	"fmt"
+	"sync"
+)
+
+type (
+	// Multimethod is a Function that dispatches to one of its methods
+	// based on the result of calling a dispatch Function with its
+	// arguments. Dispatch values are related using a Hierarchy
+	Multimethod interface {
+		multimethod() // marker
+		Function
+		Named
+		AddMethod(Value, Function)
+		RemoveMethod(Value)
+		PreferMethod(Value, Value) error
+		Methods() Object
+	}
+
+	multimethod struct {
+		sync.RWMutex
+		name     Name
+		dispatch Function
+		def      Value
+		hier     Hierarchy
+		methods  Object
+		prefers  Object
+		cache    Object
+		version  uint64
+	}
+)
+
+// Error messages
+const (
+	ErrNoMultimethodMethod = "multimethod %s has no method for dispatch value: %s"
+	ErrAmbiguousMethod     = "multimethod %s has ambiguous methods for dispatch value %s: %s and %s"
+	ErrPreferConflict      = "multimethod %s already prefers %s to %s"
+)
+
+// DefaultDispatch is the dispatch value of a Multimethod's fallback
+// method, unless another is provided
+const DefaultDispatch = Keyword("default")
+
+// NewMultimethod instantiates a new Multimethod. The default value
+// identifies the method to fall back to when no other method applies
+func NewMultimethod(
+	name Name, dispatch Function, def Value, h Hierarchy,
+) Multimethod {
+	return &multimethod{
+		name:     name,
+		dispatch: dispatch,
+		def:      def,
+		hier:     h,
+		methods:  EmptyObject,
+		prefers:  EmptyObject,
+		cache:    EmptyObject,
+	}
+}
+
+func (*multimethod) multimethod() {}
+
+func (m *multimethod) Name() Name {
+	return m.name
+}
+
+func (m *multimethod) Call(args ...Value) Value {
+	d := m.dispatch.Call(args...)
+	fn := m.resolve(d)
+	if err := fn.CheckArity(len(args)); err != nil {
+		panic(err)
+	}
+	return fn.Call(args...)
+}
+
+func (m *multimethod) CheckArity(argCount int) error {
+	return m.dispatch.CheckArity(argCount)
+}
+
+func (m *multimethod) Convention() Convention {
+	return ApplicativeCall
+}
+
+// AddMethod registers the method to be called for a dispatch value,
+// replacing any that was previously registered
+func (m *multimethod) AddMethod(d Value, fn Function) {
+	m.Lock()
+	defer m.Unlock()
+	m.methods = m.methods.Put(NewCons(d, fn)).(Object)
+	m.cache = EmptyObject
+}
+
+func (m *multimethod) RemoveMethod(d Value) {
+	m.Lock()
+	defer m.Unlock()
+	if _, r, ok := m.methods.Remove(d); ok {
+		m.methods = r.(Object)
+		m.cache = EmptyObject
+	}
+}
+
+// PreferMethod resolves ambiguity between two dispatch values by
+// preferring the first to the second
+func (m *multimethod) PreferMethod(x Value, y Value) error {
+	m.Lock()
+	defer m.Unlock()
+	if m.isPreferred(y, x) {
+		return fmt.Errorf(ErrPreferConflict, m.name, y, x)
+	}
+	var p Vector = EmptyVector
+	if v, ok := m.prefers.Get(x); ok {
+		p = v.(Vector)
+	}
+	m.prefers = m.prefers.Put(NewCons(x, p.Append(y))).(Object)
+	m.cache = EmptyObject
+	return nil
+}
+
+func (m *multimethod) Methods() Object {
+	m.RLock()
+	defer m.RUnlock()
+	return m.methods
+}
+
+func (m *multimethod) resolve(d Value) Function {
+	v := m.hier.Version()
+
+	m.RLock()
+	if m.version == v {
+		if fn, ok := m.cache.Get(d); ok {
+			m.RUnlock()
+			return fn.(Function)
+		}
+	}
+	m.RUnlock()
+
+	m.Lock()
+	defer m.Unlock()
+	if m.version != v {
+		m.cache = EmptyObject
+		m.version = v
+	}
+	if fn, ok := m.cache.Get(d); ok {
+		return fn.(Function)
+	}
+	fn := m.findMethod(d)
+	m.cache = m.cache.Put(NewCons(d, fn)).(Object)
+	return fn
+}
+
+func (m *multimethod) findMethod(d Value) Function {
+	if fn, ok := m.methods.Get(d); ok {
+		return fn.(Function)
+	}
+
+	var best Pair
+	pairs := objectPairs(m.methods)
+	for _, p := range pairs {
+		if !m.hier.IsA(d, p.Car()) {
+			continue
+		}
+		if best == nil || m.dominates(p.Car(), best.Car()) {
+			best = p
+		}
+	}
+	if best != nil {
+		for _, p := range pairs {
+			k := p.Car()
+			if k.Equal(best.Car()) || !m.hier.IsA(d, k) {
+				continue
+			}
+			if !m.dominates(best.Car(), k) {
+				panic(fmt.Errorf(ErrAmbiguousMethod, m.name, d, best.Car(), k))
+			}
+		}
+		return best.Cdr().(Function)
+	}
+
+	if fn, ok := m.methods.Get(m.def); ok {
+		return fn.(Function)
+	}
+	panic(fmt.Errorf(ErrNoMultimethodMethod, m.name, d))
+}
+
+func (m *multimethod) dominates(x Value, y Value) bool {
+	return m.isPreferred(x, y) || m.hier.IsA(x, y)
+}
+
+// isPreferred returns whether x is preferred to y, either directly or
+// by way of their ancestors
+func (m *multimethod) isPreferred(x Value, y Value) bool {
+	if p, ok := m.prefers.Get(x); ok {
+		for _, e := range p.(Vector).Values() {
+			if m.hier.IsA(y, e) {
+				return true
+			}
+		}
+	}
+	if k, ok := x.(Keyword); ok {
+		for _, a := range m.hier.Parents(k) {
+			if m.isPreferred(a, y) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func objectPairs(o Object) Pairs {
+	var res Pairs
+	for f, r, ok := o.Split(); ok; f, r, ok = r.Split() {
+		res = append(res, f.(Pair))
+	}
+	return res
+}
+
+func (m *multimethod) Type() Name {
+	return "multimethod"
+}
+
+func (m *multimethod) Equal(v Value) bool {
+	if v, ok := v.(*multimethod); ok {
+		return m == v
+	}
+	return false
+}
+
+func (m *multimethod) String() string {
+	return DumpString(m)
+}
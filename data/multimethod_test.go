@@ -0,0 +1,103 @@
+package data_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+)
+
+func TestHierarchy(t *testing.T) {
+	as := assert.New(t)
+
+	h := data.NewHierarchy()
+	v := h.Version()
+	as.Nil(h.Derive("square", "rect"))
+	as.Nil(h.Derive("rect", "shape"))
+	as.Nil(h.Derive("square", "rect"))
+	as.NotEqual(v, h.Version())
+
+	as.Equal([]data.Keyword{"rect"}, h.Parents("square"))
+	as.Equal([]data.Keyword{"rect", "shape"}, h.Ancestors("square"))
+	as.True(h.IsA(K("square"), K("shape")))
+	as.True(h.IsA(K("square"), K("square")))
+	as.False(h.IsA(K("shape"), K("square")))
+	as.True(h.IsA(V(K("square"), I(1)), V(K("shape"), I(1))))
+	as.False(h.IsA(V(K("square"), I(1)), V(K("shape"), I(2))))
+	as.False(h.IsA(V(K("square")), V(K("shape"), I(1))))
+
+	as.Equal(
+		fmt.Errorf(data.ErrCyclicDerivation, ":shape", ":square"),
+		h.Derive("shape", "square"),
+	)
+}
+
+func TestMultimethod(t *testing.T) {
+	as := assert.New(t)
+
+	h := data.NewHierarchy()
+	m := data.NewMultimethod("area",
+		data.Applicative(func(args ...data.Value) data.Value {
+			return as.MustGet(args[0].(data.Mapped), K("shape"))
+		}, 1),
+		data.DefaultDispatch, h,
+	)
+	as.Equal(N("area"), m.Name())
+	shape := func(k data.Keyword) data.Value {
+		return O(C(K("shape"), k))
+	}
+
+	as.Nil(h.Derive("square", "rect"))
+	as.Nil(h.Derive("square", "rhombus"))
+	m.AddMethod(K("rect"), constant(S("rect")))
+	m.AddMethod(K("rhombus"), constant(S("rhombus")))
+	m.AddMethod(K("default"), constant(S("default")))
+
+	as.Equal(S("rect"), m.Call(shape("rect")))
+	as.Equal(S("default"), m.Call(shape("circle")))
+	as.Equal(3, m.Methods().Count())
+
+	as.Nil(m.PreferMethod(K("rhombus"), K("rect")))
+	as.Equal(S("rhombus"), m.Call(shape("square")))
+	as.Equal(
+		fmt.Errorf(data.ErrPreferConflict, "area", ":rhombus", ":rect"),
+		m.PreferMethod(K("rect"), K("rhombus")),
+	)
+
+	// derivations invalidate the dispatch cache
+	as.Equal(S("default"), m.Call(shape("cube")))
+	as.Nil(h.Derive("cube", "square"))
+	as.Equal(S("rhombus"), m.Call(shape("cube")))
+
+	m.RemoveMethod(K("default"))
+	defer as.ExpectPanic(
+		fmt.Sprintf(data.ErrNoMultimethodMethod, "area", ":circle"),
+	)
+	m.Call(shape("circle"))
+}
+
+func TestMultimethodAmbiguous(t *testing.T) {
+	as := assert.New(t)
+
+	h := data.NewHierarchy()
+	m := data.NewMultimethod("kind",
+		data.Applicative(func(args ...data.Value) data.Value {
+			return args[0]
+		}, 1),
+		data.DefaultDispatch, h,
+	)
+	as.Nil(h.Derive("square", "rect"))
+	as.Nil(h.Derive("square", "rhombus"))
+	m.AddMethod(K("rect"), constant(S("rect")))
+	m.AddMethod(K("rhombus"), constant(S("rhombus")))
+
+	defer func() {
+		err := recover().(error)
+		as.Contains(
+			"ambiguous methods for dispatch value :square", S(err.Error()),
+		)
+	}()
+	m.Call(K("square"))
+}
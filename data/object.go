@@ -15,12 +15,14 @@ type (
 		Sequence
 		Mapped
 		Counted
+		Iterable
 		Caller
 	}
 
 	object struct {
 		pair     Pair
 		children [bucketSize]*object
+		edit     *owner
 	}
 
 	emptyObject struct{}
@@ -59,11 +61,11 @@ var (
 //    http://lampwww.epfl.ch/papers/idealhashtrees.pdf
 //
 func NewObject(pairs ...Pair) Object {
-	var res Object = EmptyObject
+	res := newTransientObject(EmptyObject)
 	for _, p := range pairs {
-		res = res.Put(p).(Object)
+		res.put(p)
 	}
-	return res
+	return res.persistent()
 }
 
 // ValuesToObject interprets a set of Values as an Object
@@ -71,11 +73,11 @@ func ValuesToObject(v ...Value) (Object, error) {
 	if len(v)%2 != 0 {
 		return nil, errors.New(ErrMapNotPaired)
 	}
-	var res Object = EmptyObject
+	res := newTransientObject(EmptyObject)
 	for i := len(v) - 2; i >= 0; i -= 2 {
-		res = res.Put(NewCons(v[i], v[i+1])).(Object)
+		res.put(NewCons(v[i], v[i+1]))
 	}
-	return res, nil
+	return res.persistent(), nil
 }
 
 func (*object) object() {}
@@ -120,6 +122,36 @@ func (o *object) put(p Pair, hash uint64) *object {
 	// return a copy with the new bucket
 	res := *o
 	res.children[idx] = bucket
+	res.edit = nil
+	return &res
+}
+
+// putInPlace mutates the nodes that are owned by the provided owner,
+// copying any that aren't. It also reports whether the key was added
+func (o *object) putInPlace(e *owner, p Pair, hash uint64) (*object, bool) {
+	res := o.editable(e)
+	if res.pair.Car().Equal(p.Car()) {
+		res.pair = p
+		return res, false
+	}
+
+	idx := hash & bucketMask
+	bucket := res.children[idx]
+	if bucket == nil {
+		res.children[idx] = &object{pair: p, edit: e}
+		return res, true
+	}
+	bucket, added := bucket.putInPlace(e, p, hash>>bucketBits)
+	res.children[idx] = bucket
+	return res, added
+}
+
+func (o *object) editable(e *owner) *object {
+	if o.edit == e {
+		return o
+	}
+	res := *o
+	res.edit = e
 	return &res
 }
 
@@ -143,18 +175,35 @@ func (o *object) remove(k Value, hash uint64) (Value, *object, bool) {
 		if v, r, ok := bucket.remove(k, hash>>bucketBits); ok {
 			res := *o
 			res.children[idx] = r
+			res.edit = nil
 			return v, &res, true
 		}
 	}
 	return nil, nil, false
 }
 
+func (o *object) removeInPlace(e *owner, k Value, hash uint64) (*object, bool) {
+	if o.pair.Car().Equal(k) {
+		return o.promote(), true
+	}
+	idx := hash & bucketMask
+	if bucket := o.children[idx]; bucket != nil {
+		if r, ok := bucket.removeInPlace(e, k, hash>>bucketBits); ok {
+			res := o.editable(e)
+			res.children[idx] = r
+			return res, true
+		}
+	}
+	return o, false
+}
+
 func (o *object) promote() *object {
 	for i, c := range o.children {
 		if c != nil {
 			res := *o
 			res.pair = c.pair
 			res.children[i] = c.promote()
+			res.edit = nil
 			return &res
 		}
 	}
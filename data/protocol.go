@@ -0,0 +1,304 @@
+package data
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+type (
+	// Protocol is a named set of methods that dispatch on the type of
+	// their first argument. Implementations can target Go types, or
+	// individual RecordTypes
+	Protocol interface {
+		protocol() // marker
+		Value
+		Named
+		Methods() Names
+		Method(Name) (Function, bool)
+		Extend(Value, ProtocolMethods) error
+		Satisfies(Value) bool
+	}
+
+	// ProtocolMethods maps Protocol method names to their implementations
+	ProtocolMethods map[Name]Function
+
+	// GoType identifies a Go type, or the set of Go types that implement
+	// an interface, when extending a Protocol
+	GoType interface {
+		goType() // marker
+		Value
+		Named
+		ReflectType() reflect.Type
+	}
+
+	protocol struct {
+		sync.RWMutex
+		name    Name
+		names   Names
+		methods map[Name]Function
+		types   []*typeImpl
+		records map[RecordType]ProtocolMethods
+		cache   map[reflect.Type][]ProtocolMethods
+	}
+
+	typeImpl struct {
+		typ     reflect.Type
+		methods ProtocolMethods
+	}
+
+	goType struct {
+		name Name
+		typ  reflect.Type
+	}
+)
+
+// Error messages
+const (
+	ErrProtocolMethodNotFound = "protocol %s has no method: %s"
+	ErrProtocolNotImplemented = "protocol %s method %s not implemented for: %s"
+	ErrInvalidProtocolTarget  = "invalid protocol target: %s"
+	ErrProtocolNeedsMethods   = "protocol %s must declare at least one method"
+)
+
+// NewProtocol instantiates a new Protocol with the named methods
+func NewProtocol(name Name, methods ...Name) Protocol {
+	if len(methods) == 0 {
+		panic(fmt.Errorf(ErrProtocolNeedsMethods, name))
+	}
+	res := &protocol{
+		name:    name,
+		names:   make(Names, len(methods)),
+		methods: make(map[Name]Function, len(methods)),
+		records: map[RecordType]ProtocolMethods{},
+		cache:   map[reflect.Type][]ProtocolMethods{},
+	}
+	copy(res.names, methods)
+	for _, m := range methods {
+		res.methods[m] = res.makeMethod(m)
+	}
+	return res
+}
+
+func (p *protocol) makeMethod(n Name) Function {
+	return MakeApplicative(func(args ...Value) Value {
+		fn, ok := p.lookup(args[0], n)
+		if !ok {
+			panic(fmt.Errorf(ErrProtocolNotImplemented, p.name, n, args[0]))
+		}
+		if err := fn.CheckArity(len(args)); err != nil {
+			panic(err)
+		}
+		return fn.Call(args...)
+	}, MakeMinimumChecker(1))
+}
+
+func (*protocol) protocol() {}
+
+func (p *protocol) Name() Name {
+	return p.name
+}
+
+func (p *protocol) Methods() Names {
+	res := make(Names, len(p.names))
+	copy(res, p.names)
+	return res
+}
+
+func (p *protocol) Method(n Name) (Function, bool) {
+	res, ok := p.methods[n]
+	return res, ok
+}
+
+// Extend registers method implementations for a RecordType or GoType.
+// Extending the same target more than once merges the methods
+func (p *protocol) Extend(target Value, methods ProtocolMethods) error {
+	for n := range methods {
+		if _, ok := p.methods[n]; !ok {
+			return fmt.Errorf(ErrProtocolMethodNotFound, p.name, n)
+		}
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	switch target := target.(type) {
+	case RecordType:
+		p.records[target] = mergeMethods(p.records[target], methods)
+		return nil
+	case GoType:
+		p.extendType(target.ReflectType(), methods)
+		p.cache = map[reflect.Type][]ProtocolMethods{}
+		return nil
+	default:
+		return fmt.Errorf(ErrInvalidProtocolTarget, target)
+	}
+}
+
+func (p *protocol) extendType(t reflect.Type, methods ProtocolMethods) {
+	for _, i := range p.types {
+		if i.typ == t {
+			i.methods = mergeMethods(i.methods, methods)
+			return
+		}
+	}
+	p.types = append(p.types, &typeImpl{
+		typ:     t,
+		methods: mergeMethods(nil, methods),
+	})
+}
+
+func mergeMethods(l ProtocolMethods, r ProtocolMethods) ProtocolMethods {
+	res := make(ProtocolMethods, len(l)+len(r))
+	for k, v := range l {
+		res[k] = v
+	}
+	for k, v := range r {
+		res[k] = v
+	}
+	return res
+}
+
+// Satisfies returns whether every method of the Protocol has been
+// implemented for the provided Value
+func (p *protocol) Satisfies(v Value) bool {
+	for _, n := range p.names {
+		if _, ok := p.lookup(v, n); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *protocol) lookup(v Value, n Name) (Function, bool) {
+	if r, ok := v.(Record); ok {
+		p.RLock()
+		m := p.records[r.RecordType()]
+		p.RUnlock()
+		if fn, ok := m[n]; ok {
+			return fn, true
+		}
+	}
+	for _, m := range p.implsFor(reflect.TypeOf(v)) {
+		if fn, ok := m[n]; ok {
+			return fn, true
+		}
+	}
+	return nil, false
+}
+
+// implsFor returns the implementations that apply to a concrete Go
+// type, ordered from most to least specific. Results are cached until
+// the next call to Extend
+func (p *protocol) implsFor(t reflect.Type) []ProtocolMethods {
+	p.RLock()
+	res, ok := p.cache[t]
+	p.RUnlock()
+	if ok {
+		return res
+	}
+
+	p.Lock()
+	defer p.Unlock()
+	var matched []*typeImpl
+	for _, i := range p.types {
+		if t != nil && typeMatches(t, i.typ) {
+			matched = insertBySpecificity(matched, i)
+		}
+	}
+	res = make([]ProtocolMethods, len(matched))
+	for idx, i := range matched {
+		res[idx] = i.methods
+	}
+	p.cache[t] = res
+	return res
+}
+
+func typeMatches(t reflect.Type, target reflect.Type) bool {
+	if t == target {
+		return true
+	}
+	return target.Kind() == reflect.Interface && t.Implements(target)
+}
+
+func insertBySpecificity(impls []*typeImpl, i *typeImpl) []*typeImpl {
+	for idx, e := range impls {
+		if isMoreSpecific(i.typ, e.typ) {
+			res := make([]*typeImpl, 0, len(impls)+1)
+			res = append(res, impls[:idx]...)
+			res = append(res, i)
+			return append(res, impls[idx:]...)
+		}
+	}
+	return append(impls, i)
+}
+
+func isMoreSpecific(l reflect.Type, r reflect.Type) bool {
+	if l.Kind() != reflect.Interface {
+		return true
+	}
+	if r.Kind() != reflect.Interface {
+		return false
+	}
+	return l.Implements(r) && !r.Implements(l)
+}
+
+func (p *protocol) Type() Name {
+	return "protocol"
+}
+
+func (p *protocol) Equal(v Value) bool {
+	if v, ok := v.(*protocol); ok {
+		return p == v
+	}
+	return false
+}
+
+func (p *protocol) String() string {
+	return DumpString(p)
+}
+
+// NewGoType returns a named GoType for the provided reflect.Type
+func NewGoType(name Name, t reflect.Type) GoType {
+	if t == nil {
+		panic(fmt.Errorf(ErrInvalidProtocolTarget, name))
+	}
+	return &goType{
+		name: name,
+		typ:  t,
+	}
+}
+
+// GoTypeOf returns a GoType for the type of the provided Go value
+func GoTypeOf(i interface{}) GoType {
+	t := reflect.TypeOf(i)
+	if t == nil {
+		panic(fmt.Errorf(ErrInvalidProtocolTarget, "nil"))
+	}
+	return NewGoType(Name(t.String()), t)
+}
+
+func (*goType) goType() {}
+
+func (t *goType) Name() Name {
+	return t.name
+}
+
+func (t *goType) ReflectType() reflect.Type {
+	return t.typ
+}
+
+func (t *goType) Type() Name {
+	return "go-type"
+}
+
+func (t *goType) Equal(v Value) bool {
+	if v, ok := v.(*goType); ok {
+		return t == v || t.typ == v.typ
+	}
+	return false
+}
+
+func (t *goType) String() string {
+	return DumpString(t)
+}
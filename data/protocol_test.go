@@ -0,0 +1,115 @@
+package data_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+)
+
+type hostValue struct{ name string }
+
+func (h *hostValue) Equal(v data.Value) bool {
+	return h == v
+}
+
+func (h *hostValue) String() string {
+	return h.name
+}
+
+func constant(v data.Value) data.Function {
+	return data.Applicative(func(...data.Value) data.Value {
+		return v
+	}, 1)
+}
+
+func TestProtocol(t *testing.T) {
+	as := assert.New(t)
+
+	p := data.NewProtocol("describer", "describe")
+	as.Equal(N("describer"), p.Name())
+	as.Equal(data.Names{"describe"}, p.Methods())
+	as.Equal(N("protocol"), p.(data.Typed).Type())
+
+	describe, ok := p.Method("describe")
+	as.True(ok)
+	_, ok = p.Method("missing")
+	as.False(ok)
+
+	valueType := data.NewGoType("any", reflect.TypeOf((*data.Value)(nil)).Elem())
+	seqType := data.NewGoType("seq", reflect.TypeOf((*data.Sequence)(nil)).Elem())
+	as.Nil(p.Extend(valueType, data.ProtocolMethods{"describe": constant(S("value"))}))
+	as.Equal(S("value"), describe.Call(I(1)))
+	as.Equal(S("value"), describe.Call(V(I(1))))
+
+	// the cache must be invalidated by Extend
+	as.Nil(p.Extend(seqType, data.ProtocolMethods{"describe": constant(S("seq"))}))
+	as.Equal(S("value"), describe.Call(I(1)))
+	as.Equal(S("seq"), describe.Call(V(I(1))))
+
+	vecType := data.GoTypeOf(V())
+	as.Nil(p.Extend(vecType, data.ProtocolMethods{"describe": constant(S("vector"))}))
+	as.Equal(S("vector"), describe.Call(V(I(1))))
+	as.Equal(S("seq"), describe.Call(L(I(1))))
+
+	host := &hostValue{name: "host"}
+	as.Nil(p.Extend(data.GoTypeOf(host), data.ProtocolMethods{
+		"describe": data.Applicative(func(args ...data.Value) data.Value {
+			return S(args[0].(*hostValue).name)
+		}, 1),
+	}))
+	as.Equal(S("host"), describe.Call(host))
+	as.True(p.Satisfies(host))
+
+	rt := data.NewRecordType("point", K("x"))
+	as.Nil(p.Extend(rt, data.ProtocolMethods{"describe": constant(S("point"))}))
+	as.Equal(S("point"), describe.Call(rt.New(I(1))))
+	other := data.NewRecordType("point", K("x"))
+	as.Equal(S("seq"), describe.Call(other.New(I(1))))
+
+	as.Equal(
+		fmt.Errorf(data.ErrProtocolMethodNotFound, "describer", "missing"),
+		p.Extend(rt, data.ProtocolMethods{"missing": constant(S("no"))}),
+	)
+	as.Equal(
+		fmt.Errorf(data.ErrInvalidProtocolTarget, "1"),
+		p.Extend(I(1), data.ProtocolMethods{}),
+	)
+}
+
+func TestProtocolNotImplemented(t *testing.T) {
+	as := assert.New(t)
+
+	p := data.NewProtocol("sized", "size", "weight")
+	size, _ := p.Method("size")
+	as.False(p.Satisfies(V()))
+
+	as.Nil(p.Extend(data.GoTypeOf(V()), data.ProtocolMethods{
+		"size": data.Applicative(func(args ...data.Value) data.Value {
+			return I(int64(args[0].(data.Vector).Count()))
+		}, 1),
+	}))
+	as.Equal(I(2), size.Call(V(I(1), I(2))))
+	as.False(p.Satisfies(V()))
+
+	defer as.ExpectPanic(
+		fmt.Sprintf(data.ErrProtocolNotImplemented, "sized", "size", "()"),
+	)
+	size.Call(L())
+}
+
+func TestGoType(t *testing.T) {
+	as := assert.New(t)
+
+	t1 := data.GoTypeOf(I(1))
+	as.Equal(N("data.Integer"), t1.Name())
+	as.True(t1.Equal(data.GoTypeOf(I(2))))
+	as.False(t1.Equal(data.GoTypeOf(F(2))))
+	as.Equal(reflect.TypeOf(I(1)), t1.ReflectType())
+
+	defer as.ExpectPanic(fmt.Sprintf(data.ErrInvalidProtocolTarget, "nil"))
+	data.GoTypeOf(nil)
+}
@@ -0,0 +1,296 @@
+package data
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+)
+
+type (
+	// RecordType describes a named Record with an ordered set of fields
+	RecordType interface {
+		recordType() // marker
+		Value
+		Named
+		Fields() []Keyword
+		IndexOf(Keyword) (int, bool)
+		New(...Value) Record
+		FromMapped(Mapped) Record
+	}
+
+	// Record is an instance of a RecordType, mapping each of the type's
+	// fields to a Value
+	Record interface {
+		record() // marker
+		Sequence
+		Mapped
+		Counted
+		Typed
+		Caller
+		RecordType() RecordType
+		FieldAt(int) Value
+	}
+
+	recordType struct {
+		name    Name
+		fields  []Keyword
+		indexes map[Keyword]int
+	}
+
+	record struct {
+		typ    *recordType
+		values Values
+	}
+)
+
+// Error messages
+const (
+	ErrDuplicateRecordField = "duplicate field in record type %s: %s"
+	ErrRecordFieldCount     = "record %s expects %d fields, got %d"
+)
+
+var recordHash = rand.Uint64()
+
+// NewRecordType instantiates a new RecordType. Each field must be unique
+func NewRecordType(name Name, fields ...Keyword) RecordType {
+	res := &recordType{
+		name:    name,
+		fields:  make([]Keyword, len(fields)),
+		indexes: make(map[Keyword]int, len(fields)),
+	}
+	for i, f := range fields {
+		if _, ok := res.indexes[f]; ok {
+			panic(fmt.Errorf(ErrDuplicateRecordField, name, f))
+		}
+		res.fields[i] = f
+		res.indexes[f] = i
+	}
+	return res
+}
+
+func (*recordType) recordType() {}
+
+func (t *recordType) Name() Name {
+	return t.name
+}
+
+func (t *recordType) Fields() []Keyword {
+	res := make([]Keyword, len(t.fields))
+	copy(res, t.fields)
+	return res
+}
+
+func (t *recordType) IndexOf(k Keyword) (int, bool) {
+	idx, ok := t.indexes[k]
+	return idx, ok
+}
+
+// New instantiates a Record from positional field values
+func (t *recordType) New(values ...Value) Record {
+	if len(values) != len(t.fields) {
+		panic(fmt.Errorf(ErrRecordFieldCount, t.name, len(t.fields), len(values)))
+	}
+	res := make(Values, len(values))
+	copy(res, values)
+	return &record{
+		typ:    t,
+		values: res,
+	}
+}
+
+// FromMapped instantiates a Record from the field Keywords of a Mapped
+// Value. Missing fields are set to Nil, and unknown keys are ignored
+func (t *recordType) FromMapped(m Mapped) Record {
+	res := make(Values, len(t.fields))
+	for i, f := range t.fields {
+		if v, ok := m.Get(f); ok {
+			res[i] = v
+			continue
+		}
+		res[i] = Nil
+	}
+	return &record{
+		typ:    t,
+		values: res,
+	}
+}
+
+func (t *recordType) Type() Name {
+	return "record-type"
+}
+
+func (t *recordType) Equal(v Value) bool {
+	if v, ok := v.(*recordType); ok {
+		return t == v
+	}
+	return false
+}
+
+func (t *recordType) String() string {
+	return DumpString(t)
+}
+
+func (*record) record() {}
+
+func (r *record) RecordType() RecordType {
+	return r.typ
+}
+
+func (r *record) FieldAt(index int) Value {
+	return r.values[index]
+}
+
+func (r *record) Type() Name {
+	return r.typ.name
+}
+
+func (r *record) Get(k Value) (Value, bool) {
+	if k, ok := k.(Keyword); ok {
+		if idx, ok := r.typ.indexes[k]; ok {
+			return r.values[idx], true
+		}
+	}
+	return Nil, false
+}
+
+// Put returns a new Record if the key is one of the Record's fields.
+// Otherwise the Record is widened into an Object containing the pair
+func (r *record) Put(p Pair) Sequence {
+	if k, ok := p.Car().(Keyword); ok {
+		if idx, ok := r.typ.indexes[k]; ok {
+			res := make(Values, len(r.values))
+			copy(res, r.values)
+			res[idx] = p.Cdr()
+			return &record{
+				typ:    r.typ,
+				values: res,
+			}
+		}
+	}
+	return r.toObject().Put(p)
+}
+
+// Remove returns an Object, because a Record can't be missing any of
+// its fields
+func (r *record) Remove(k Value) (Value, Sequence, bool) {
+	if k, ok := k.(Keyword); ok {
+		if _, ok := r.typ.indexes[k]; ok {
+			return r.toObject().Remove(k)
+		}
+	}
+	return Nil, r, false
+}
+
+func (r *record) toObject() Object {
+	return NewObject(r.pairs()...)
+}
+
+func (r *record) pairs() Pairs {
+	res := make(Pairs, len(r.values))
+	for i, v := range r.values {
+		res[i] = NewCons(r.typ.fields[i], v)
+	}
+	return res
+}
+
+func (r *record) First() Value {
+	if len(r.values) == 0 {
+		return Nil
+	}
+	return r.pairAt(0)
+}
+
+func (r *record) Rest() Sequence {
+	return r.pairsFrom(1)
+}
+
+// Split returns the Record's first field as a Pair, along with a List
+// of the remaining ones. The List is built once, so walking it doesn't
+// revisit the Record
+func (r *record) Split() (Value, Sequence, bool) {
+	if len(r.values) == 0 {
+		return Nil, EmptyList, false
+	}
+	return r.pairAt(0), r.pairsFrom(1), true
+}
+
+func (r *record) pairAt(index int) Pair {
+	return NewCons(r.typ.fields[index], r.values[index])
+}
+
+func (r *record) pairsFrom(index int) List {
+	var res List = EmptyList
+	for i, u := len(r.values)-1, 1; i >= index; i, u = i-1, u+1 {
+		res = &list{
+			first: r.pairAt(i),
+			rest:  res,
+			count: u,
+		}
+	}
+	return res
+}
+
+func (r *record) IsEmpty() bool {
+	return len(r.values) == 0
+}
+
+func (r *record) Count() int {
+	return len(r.values)
+}
+
+func (r *record) Call(args ...Value) Value {
+	return mappedCall(r, args)
+}
+
+func (r *record) Convention() Convention {
+	return ApplicativeCall
+}
+
+func (r *record) CheckArity(argCount int) error {
+	return checkRangedArity(1, 2, argCount)
+}
+
+func (r *record) Equal(v Value) bool {
+	if v, ok := v.(*record); ok {
+		if r == v {
+			return true
+		}
+		if r.typ != v.typ {
+			return false
+		}
+		for i, e := range r.values {
+			if !e.Equal(v.values[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func (r *record) HashCode() uint64 {
+	h := recordHash * HashString(string(r.typ.name))
+	for _, e := range r.values {
+		h *= HashCode(e)
+	}
+	return h
+}
+
+// String returns a representation of the Record for display, along
+// with the name of its type. The reader can't read it back in
+func (r *record) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("#")
+	buf.WriteString(string(r.typ.name))
+	buf.WriteString("{")
+	for i, v := range r.values {
+		if i > 0 {
+			buf.WriteString(" ")
+		}
+		buf.WriteString(r.typ.fields[i].String())
+		buf.WriteString(" ")
+		buf.WriteString(MaybeQuoteString(v))
+	}
+	buf.WriteString("}")
+	return buf.String()
+}
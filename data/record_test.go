@@ -0,0 +1,75 @@
+package data_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+)
+
+func TestRecord(t *testing.T) {
+	as := assert.New(t)
+
+	rt := data.NewRecordType("point", K("x"), K("y"))
+	as.Equal(N("point"), rt.Name())
+	as.Equal([]data.Keyword{K("x"), K("y")}, rt.Fields())
+
+	r1 := rt.New(I(1), I(2))
+	as.String(`#point{:x 1 :y 2}`, r1)
+	as.Equal(N("point"), r1.Type())
+	as.Equal(2, r1.Count())
+	as.Equal(I(1), as.MustGet(r1, K("x")))
+	as.Equal(I(2), r1.FieldAt(1))
+	as.Equal(I(2), r1.Call(K("y")))
+	as.Equal(S("missing"), r1.Call(K("z"), S("missing")))
+	as.True(r1.Equal(rt.New(I(1), I(2))))
+	as.False(r1.Equal(rt.New(I(2), I(1))))
+	as.Equal(data.HashCode(r1), data.HashCode(rt.New(I(1), I(2))))
+
+	f, r, ok := r1.Split()
+	as.True(ok)
+	as.String("(:x . 1)", f)
+	as.String("((:y . 2))", r)
+
+	r2 := r1.Put(C(K("x"), I(10))).(data.Record)
+	as.String(`#point{:x 10 :y 2}`, r2)
+	as.String(`#point{:x 1 :y 2}`, r1)
+
+	o1 := r1.Put(C(K("z"), I(3))).(data.Object)
+	as.String(`{:x 1 :y 2 :z 3}`, o1)
+
+	v, o2, ok := r1.Remove(K("x"))
+	as.True(ok)
+	as.Equal(I(1), v)
+	as.String(`{:y 2}`, o2)
+
+	_, r3, ok := r1.Remove(K("z"))
+	as.False(ok)
+	as.Identical(r1, r3)
+
+	defer as.ExpectPanic(fmt.Sprintf(data.ErrRecordFieldCount, "point", 2, 1))
+	rt.New(I(1))
+}
+
+func TestRecordFromMapped(t *testing.T) {
+	as := assert.New(t)
+
+	rt := data.NewRecordType("person", K("name"), K("age"))
+	r1 := rt.FromMapped(O(
+		C(K("name"), S("Ale")),
+		C(K("extra"), S("ignored")),
+	))
+	as.String(`#person{:name "Ale" :age ()}`, r1)
+
+	other := data.NewRecordType("person", K("name"), K("age"))
+	as.False(r1.Equal(other.FromMapped(r1)))
+	as.True(r1.Equal(rt.FromMapped(r1)))
+}
+
+func TestRecordTypeErrors(t *testing.T) {
+	as := assert.New(t)
+	defer as.ExpectPanic(fmt.Sprintf(data.ErrDuplicateRecordField, "point", ":x"))
+	data.NewRecordType("point", K("x"), K("x"))
+}
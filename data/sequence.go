@@ -61,6 +61,16 @@ type (
 	}
 )
 
+// MakeIterator returns an Iterator for the provided Sequence. If the
+// Sequence is Iterable, its own Iterator is returned. Otherwise, the
+// Iterator falls back to splitting the Sequence
+func MakeIterator(s Sequence) Iterator {
+	if i, ok := s.(Iterable); ok {
+		return i.Iterate()
+	}
+	return &splitIterator{seq: s}
+}
+
 // MakeSequenceStr converts a Sequence to a String
 func MakeSequenceStr(s Sequence) string {
 	f, r, ok := s.Split()
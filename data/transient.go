@@ -0,0 +1,247 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+type (
+	// Transient is a mutable version of a persistent collection that can
+	// only be used by one goroutine at a time. Once it has been frozen,
+	// by calling Persistent, it can no longer be used
+	Transient interface {
+		transient() // marker
+		Value
+		Counted
+		Persistent() Sequence
+	}
+
+	// TransientObject is a mutable version of an Object
+	TransientObject interface {
+		Transient
+		Get(Value) (Value, bool)
+		Put(Pair)
+		Remove(Value)
+	}
+
+	// TransientVector is a mutable version of a Vector
+	TransientVector interface {
+		Transient
+		ElementAt(int) (Value, bool)
+		Append(Value)
+		Set(int, Value)
+	}
+
+	// owner is created along with a Transient, and marks the nodes that
+	// it can edit in place. Its state is checked by every operation
+	owner struct {
+		state int32
+	}
+
+	transientObject struct {
+		*owner
+		root *object
+	}
+
+	transientVector struct {
+		*owner
+		values Values
+	}
+)
+
+// Error messages
+const (
+	ErrTransientFrozen   = "transient used after being made persistent"
+	ErrTransientOwner    = "transient used by more than one goroutine at a time"
+	ErrTransientBounds   = "transient index out of bounds: %d"
+	ErrTransientRequired = "value can't be made transient: %s"
+)
+
+const (
+	ownerIdle int32 = iota
+	ownerBusy
+	ownerFrozen
+)
+
+// NewTransient returns a Transient for the provided Object or Vector
+func NewTransient(s Sequence) (Transient, error) {
+	switch s := s.(type) {
+	case Object:
+		return NewTransientObject(s), nil
+	case Vector:
+		return NewTransientVector(s), nil
+	default:
+		return nil, fmt.Errorf(ErrTransientRequired, s)
+	}
+}
+
+// NewTransientObject returns a TransientObject that begins with the
+// contents of the provided Object
+func NewTransientObject(o Object) TransientObject {
+	return newTransientObject(o)
+}
+
+func newTransientObject(o Object) *transientObject {
+	res := &transientObject{owner: &owner{}}
+	if o, ok := o.(*object); ok {
+		res.root = o
+	}
+	return res
+}
+
+// NewTransientVector returns a TransientVector that begins with the
+// contents of the provided Vector
+func NewTransientVector(v Vector) TransientVector {
+	values := v.Values()
+	res := &transientVector{
+		owner:  &owner{},
+		values: make(Values, len(values), len(values)*2),
+	}
+	copy(res.values, values)
+	return res
+}
+
+// acquire marks the Transient as being in use until release is called.
+// It fails if the Transient was frozen, or is in use by another caller
+func (o *owner) acquire() {
+	if atomic.CompareAndSwapInt32(&o.state, ownerIdle, ownerBusy) {
+		return
+	}
+	if atomic.LoadInt32(&o.state) == ownerFrozen {
+		panic(errors.New(ErrTransientFrozen))
+	}
+	panic(errors.New(ErrTransientOwner))
+}
+
+func (o *owner) release() {
+	atomic.StoreInt32(&o.state, ownerIdle)
+}
+
+func (o *owner) freeze() {
+	atomic.StoreInt32(&o.state, ownerFrozen)
+}
+
+func (*owner) transient() {}
+
+func (t *transientObject) Get(k Value) (Value, bool) {
+	t.acquire()
+	defer t.release()
+	if t.root == nil {
+		return Nil, false
+	}
+	return t.root.Get(k)
+}
+
+func (t *transientObject) Put(p Pair) {
+	t.acquire()
+	defer t.release()
+	t.put(p)
+}
+
+func (t *transientObject) put(p Pair) {
+	if t.root == nil {
+		t.root = &object{pair: p, edit: t.owner}
+		return
+	}
+	t.root, _ = t.root.putInPlace(t.owner, p, HashCode(p.Car()))
+}
+
+func (t *transientObject) Remove(k Value) {
+	t.acquire()
+	defer t.release()
+	if t.root != nil {
+		t.root, _ = t.root.removeInPlace(t.owner, k, HashCode(k))
+	}
+}
+
+func (t *transientObject) Count() int {
+	t.acquire()
+	defer t.release()
+	if t.root == nil {
+		return 0
+	}
+	return t.root.Count()
+}
+
+// Persistent freezes the TransientObject and returns its contents as
+// an Object
+func (t *transientObject) Persistent() Sequence {
+	t.acquire()
+	return t.persistent()
+}
+
+func (t *transientObject) persistent() Object {
+	t.freeze()
+	if t.root == nil {
+		return EmptyObject
+	}
+	return t.root
+}
+
+func (t *transientObject) Type() Name {
+	return "transient-object"
+}
+
+func (t *transientObject) Equal(v Value) bool {
+	return t == v
+}
+
+func (t *transientObject) String() string {
+	return DumpString(t)
+}
+
+func (t *transientVector) ElementAt(index int) (Value, bool) {
+	t.acquire()
+	defer t.release()
+	if index >= 0 && index < len(t.values) {
+		return t.values[index], true
+	}
+	return Nil, false
+}
+
+func (t *transientVector) Append(v Value) {
+	t.acquire()
+	defer t.release()
+	t.values = append(t.values, v)
+}
+
+func (t *transientVector) Set(index int, v Value) {
+	t.acquire()
+	defer t.release()
+	switch {
+	case index >= 0 && index < len(t.values):
+		t.values[index] = v
+	case index == len(t.values):
+		t.values = append(t.values, v)
+	default:
+		panic(fmt.Errorf(ErrTransientBounds, index))
+	}
+}
+
+func (t *transientVector) Count() int {
+	t.acquire()
+	defer t.release()
+	return len(t.values)
+}
+
+// Persistent freezes the TransientVector and returns its contents as a
+// Vector
+func (t *transientVector) Persistent() Sequence {
+	t.acquire()
+	t.freeze()
+	l := len(t.values)
+	return vector(t.values[:l:l])
+}
+
+func (t *transientVector) Type() Name {
+	return "transient-vector"
+}
+
+func (t *transientVector) Equal(v Value) bool {
+	return t == v
+}
+
+func (t *transientVector) String() string {
+	return DumpString(t)
+}
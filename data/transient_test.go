@@ -0,0 +1,142 @@
+package data_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+)
+
+func TestTransientObject(t *testing.T) {
+	as := assert.New(t)
+
+	o1 := O(C(K("a"), I(1)), C(K("b"), I(2)))
+	t1 := data.NewTransientObject(o1)
+	for i := 0; i < 1000; i++ {
+		t1.Put(C(I(int64(i)), I(int64(i*2))))
+	}
+	t1.Put(C(K("a"), S("replaced")))
+	t1.Remove(K("b"))
+	t1.Remove(K("missing"))
+	as.Equal(1001, t1.Count())
+	v, ok := t1.Get(K("a"))
+	as.True(ok)
+	as.Equal(S("replaced"), v)
+
+	o2 := t1.Persistent().(data.Object)
+	as.Equal(1001, o2.Count())
+	as.Equal(I(998), as.MustGet(o2, I(499)))
+	_, ok = o2.Get(K("b"))
+	as.False(ok)
+
+	// the original must not have been touched
+	as.String(`{:a 1 :b 2}`, o1)
+
+	// nor the persistent result, by a later transient
+	t2 := data.NewTransientObject(o2)
+	t2.Put(C(I(499), S("changed")))
+	t2.Remove(I(0))
+	as.Equal(I(998), as.MustGet(o2, I(499)))
+	as.Equal(I(0), as.MustGet(o2, I(0)))
+	as.Equal(1000, t2.Count())
+
+	t3 := data.NewTransientObject(data.EmptyObject)
+	as.Equal(0, t3.Count())
+	t3.Remove(K("a"))
+	as.Equal(data.EmptyObject, t3.Persistent())
+
+	defer as.ExpectPanic(data.ErrTransientFrozen)
+	t1.Put(C(K("c"), I(3)))
+}
+
+func TestTransientVector(t *testing.T) {
+	as := assert.New(t)
+
+	v1 := V(I(1), I(2))
+	t1 := data.NewTransientVector(v1)
+	t1.Append(I(3))
+	t1.Set(0, I(10))
+	t1.Set(3, I(4))
+	as.Equal(4, t1.Count())
+	v, ok := t1.ElementAt(2)
+	as.True(ok)
+	as.Equal(I(3), v)
+
+	v2 := t1.Persistent().(data.Vector)
+	as.String("[10 2 3 4]", v2)
+	as.String("[1 2]", v1)
+
+	defer as.ExpectPanic(fmt.Sprintf(data.ErrTransientBounds, 10))
+	data.NewTransientVector(v2).Set(10, I(1))
+}
+
+// reentrant is a key that hands its transient to another goroutine
+// while that transient is being used to hash it
+type reentrant struct {
+	data.Value
+	transient data.Transient
+	recovered interface{}
+}
+
+func (r *reentrant) HashCode() uint64 {
+	res := make(chan interface{})
+	go func() {
+		defer func() { res <- recover() }()
+		r.transient.Count()
+	}()
+	r.recovered = <-res
+	return 0
+}
+
+func TestTransientOwner(t *testing.T) {
+	as := assert.New(t)
+
+	t1 := data.NewTransientObject(O(C(K("a"), I(0))))
+	key := &reentrant{Value: K("key"), transient: t1}
+	t1.Put(C(key, I(1)))
+	as.Equal(errors.New(data.ErrTransientOwner), key.recovered)
+
+	// once it's no longer in use, another goroutine may use it
+	done := make(chan int)
+	go func() {
+		done <- t1.Count()
+	}()
+	as.Equal(2, <-done)
+
+	tr, err := data.NewTransient(L(I(1)))
+	as.Nil(tr)
+	as.Equal(fmt.Errorf(data.ErrTransientRequired, "(1)"), err)
+}
+
+func BenchmarkNewObject(b *testing.B) {
+	pairs := make(data.Pairs, 1000)
+	for i := range pairs {
+		pairs[i] = C(I(int64(i)), I(int64(i)))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		data.NewObject(pairs...)
+	}
+}
+
+func BenchmarkPersistentAssoc(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		var o data.Object = data.EmptyObject
+		for j := 0; j < 1000; j++ {
+			o = o.Put(C(I(int64(j)), I(int64(j)))).(data.Object)
+		}
+	}
+}
+
+func BenchmarkTransientAssoc(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		t := data.NewTransientObject(data.EmptyObject)
+		for j := 0; j < 1000; j++ {
+			t.Put(C(I(int64(j)), I(int64(j))))
+		}
+		t.Persistent()
+	}
+}
@@ -15,6 +15,7 @@ type (
 		Appender
 		Reverser
 		Valuer
+		Iterable
 		Caller
 	}
 
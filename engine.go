@@ -0,0 +1,305 @@
+package ale
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/kode4food/ale/core/bootstrap"
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/env"
+	"github.com/kode4food/ale/eval"
+	"github.com/kode4food/ale/ffi"
+	"github.com/kode4food/ale/read"
+	"github.com/kode4food/ale/runtime/vm"
+)
+
+type (
+	// Engine evaluates Ale code on behalf of a Go host. Nothing that
+	// happens in the evaluated code can panic the host: every failure
+	// is returned as an error
+	Engine struct {
+		ns       env.Namespace
+		limits   vm.Limits
+		onError  func(error)
+		programs *eval.Cache
+	}
+
+	// Option configures an Engine when it's constructed
+	Option func(*config)
+
+	config struct {
+		in        io.Reader
+		out       io.Writer
+		err       io.Writer
+		namespace data.Name
+		sandbox   *bootstrap.Sandbox
+		shared    *bootstrap.Shared
+		limits    vm.Limits
+		onError   func(error)
+	}
+)
+
+// Error messages
+const (
+	ErrNameNotBound = "name is not bound: %s"
+	ErrNotFunction  = "value is not a function: %s"
+	ErrAsync        = "error raised asynchronously: %s"
+)
+
+// WithStdin sets the reader that the Engine's *in* stream consumes.
+// Defaults to the process' standard input
+func WithStdin(r io.Reader) Option {
+	return func(c *config) {
+		c.in = r
+	}
+}
+
+// WithStdout sets the writer that the Engine's *out* stream produces.
+// Defaults to the process' standard output
+func WithStdout(w io.Writer) Option {
+	return func(c *config) {
+		c.out = w
+	}
+}
+
+// WithStderr sets the writer that the Engine's *err* stream produces.
+// Defaults to the process' standard error
+func WithStderr(w io.Writer) Option {
+	return func(c *config) {
+		c.err = w
+	}
+}
+
+// WithNamespace sets the namespace that the Engine evaluates code in,
+// and where its globals are set. Defaults to an anonymous namespace
+func WithNamespace(n data.Name) Option {
+	return func(c *config) {
+		c.namespace = n
+	}
+}
+
+// WithSandbox restricts the root names that the Engine's code can
+// refer to, using the provided Sandbox's rules
+func WithSandbox(s *bootstrap.Sandbox) Option {
+	return func(c *config) {
+		c.sandbox = s
+	}
+}
+
+// WithShared derives the Engine's environment from the provided Shared
+// root, rather than bootstrapping a new one. A Sandbox can be derived
+// from a Shared root using its Share method
+func WithShared(s *bootstrap.Shared) Option {
+	return func(c *config) {
+		c.shared = s
+	}
+}
+
+// WithLimits sets the limits that each of the Engine's evaluations and
+// calls are subject to
+func WithLimits(l vm.Limits) Option {
+	return func(c *config) {
+		c.limits = l
+	}
+}
+
+// WithErrorHandler sets the function that's called with the errors
+// that are raised asynchronously by the Engine's code, such as by its
+// go blocks, which nothing else can observe. By default, they're
+// written to the writer that the Engine's *err* stream produces
+func WithErrorHandler(h func(error)) Option {
+	return func(c *config) {
+		c.onError = h
+	}
+}
+
+// New constructs a new bootstrapped Engine with the provided Options
+func New(opts ...Option) (res *Engine, err error) {
+	defer recoverError(&err)
+
+	c := &config{
+		in:  os.Stdin,
+		out: os.Stdout,
+		err: os.Stderr,
+	}
+	for _, o := range opts {
+		o(c)
+	}
+	if c.onError == nil {
+		w := c.err
+		c.onError = func(err error) {
+			fmt.Fprintln(w, fmt.Errorf(ErrAsync, err))
+		}
+	}
+
+	var e *env.Environment
+	if c.sandbox != nil {
+		e = c.sandbox.StreamEnvironment(c.in, c.out, c.err)
+	} else if c.shared != nil {
+		e = c.shared.StreamEnvironment(c.in, c.out, c.err)
+	} else {
+		e = bootstrap.StreamEnvironment(c.in, c.out, c.err)
+		bootstrap.Into(e)
+	}
+
+	ns := e.GetAnonymous()
+	if c.namespace != "" {
+		ns = e.GetQualified(c.namespace)
+	}
+	return &Engine{
+		ns:       ns,
+		limits:   c.limits,
+		onError:  c.onError,
+		programs: eval.NewCache(ns),
+	}, nil
+}
+
+// Eval evaluates the provided source and returns the value of its last
+// form. The evaluation is interrupted if the Context is done
+func (e *Engine) Eval(
+	ctx context.Context, src string,
+) (res data.Value, err error) {
+	defer recoverError(&err)
+	r := read.FromString(data.String(src))
+	return eval.BlockContext(e.context(ctx), e.ns, r, e.limits), nil
+}
+
+// Set binds a global in the Engine's namespace. Go values are wrapped
+// so that Ale code can use them
+func (e *Engine) Set(name string, v interface{}) (err error) {
+	defer recoverError(&err)
+	w, err := ffi.Wrap(v)
+	if err != nil {
+		return err
+	}
+	e.ns.Declare(data.Name(name)).Bind(w)
+	return nil
+}
+
+// Get retrieves the value of a global that's visible from the Engine's
+// namespace, storing it in the provided target. If the target isn't a
+// *data.Value, the value is unwrapped into the Go type it points to
+func (e *Engine) Get(name string, target interface{}) (err error) {
+	defer recoverError(&err)
+	v, err := e.resolve(name)
+	if err != nil {
+		return err
+	}
+	if t, ok := target.(*data.Value); ok {
+		*t = v
+		return nil
+	}
+	return ffi.Unwrap(v, target)
+}
+
+// Call calls the Ale function bound to a global that's visible from
+// the Engine's namespace. Go arguments are wrapped so that the function
+// can use them. The call is interrupted if the Context is done
+func (e *Engine) Call(
+	ctx context.Context, name string, args ...interface{},
+) (res data.Value, err error) {
+	defer recoverError(&err)
+	v, err := e.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	fn, ok := v.(data.Function)
+	if !ok {
+		return nil, fmt.Errorf(ErrNotFunction, v)
+	}
+	if err := fn.CheckArity(len(args)); err != nil {
+		return nil, err
+	}
+	in, err := wrapArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	return vm.NewMonitor(e.context(ctx), e.limits).Call(fn, in...), nil
+}
+
+// Compile compiles the provided source into a Program that can be run
+// many times with arguments bound to the specified parameter names. The
+// Engine keeps the Programs it compiles, and only compiles the source
+// again if the globals it refers to have changed
+func (e *Engine) Compile(
+	src string, params ...string,
+) (res *eval.Program, err error) {
+	defer recoverError(&err)
+	names := make([]data.Name, len(params))
+	for i, p := range params {
+		names[i] = data.Name(p)
+	}
+	return e.programs.Compile(data.String(src), names...), nil
+}
+
+// Run runs a Program with the provided arguments bound to its
+// parameters. Go arguments are wrapped so that the Program can use
+// them. The run is interrupted if the Context is done
+func (e *Engine) Run(
+	ctx context.Context, p *eval.Program, args ...interface{},
+) (res data.Value, err error) {
+	defer recoverError(&err)
+	if err := data.MakeFixedChecker(len(p.Params))(len(args)); err != nil {
+		return nil, err
+	}
+	in, err := wrapArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	return p.RunContext(e.context(ctx), e.limits, in...), nil
+}
+
+// Namespace returns the namespace that the Engine evaluates code in
+func (e *Engine) Namespace() env.Namespace {
+	return e.ns
+}
+
+// context returns a copy of the provided Context that reports the
+// errors raised asynchronously by the Engine's code to its handler
+func (e *Engine) context(ctx context.Context) context.Context {
+	return vm.WithErrorHandler(ctx, func(rec interface{}) {
+		e.onError(toError(rec))
+	})
+}
+
+func (e *Engine) resolve(name string) (data.Value, error) {
+	s := data.ParseSymbol(data.String(name))
+	if v, ok := env.ResolveValue(e.ns, s); ok {
+		return v, nil
+	}
+	return nil, fmt.Errorf(ErrNameNotBound, name)
+}
+
+func wrapArgs(args []interface{}) (data.Values, error) {
+	res := make(data.Values, len(args))
+	for i, a := range args {
+		w, err := ffi.Wrap(a)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = w
+	}
+	return res, nil
+}
+
+// recoverError converts a panic into an error that's returned by the
+// function that deferred it
+func recoverError(err *error) {
+	if rec := recover(); rec != nil {
+		*err = toError(rec)
+	}
+}
+
+func toError(rec interface{}) error {
+	switch rec := rec.(type) {
+	case error:
+		return rec
+	case data.Value:
+		return errors.New(rec.String())
+	default:
+		return fmt.Errorf("%v", rec)
+	}
+}
@@ -0,0 +1,274 @@
+package ale_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kode4food/ale"
+	"github.com/kode4food/ale/core/bootstrap"
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+	"github.com/kode4food/ale/runtime/vm"
+)
+
+func TestEngineEval(t *testing.T) {
+	as := assert.New(t)
+	ctx := context.Background()
+
+	var out bytes.Buffer
+	e, err := ale.New(
+		ale.WithStdin(strings.NewReader("line\n")),
+		ale.WithStdout(&out),
+	)
+	as.Nil(err)
+
+	res, err := e.Eval(ctx, `(println "hello") (first *in*)`)
+	as.Nil(err)
+	as.String("line", res)
+	as.Equal("hello\n", out.String())
+
+	res, err = e.Eval(ctx, "(define x 10) (* x 2)")
+	as.Nil(err)
+	as.Equal(I(20), res)
+
+	_, err = e.Eval(ctx, "(+ 1 unknown)")
+	as.EqualError(err, "symbol not declared in namespace: unknown")
+
+	_, err = e.Eval(ctx, `(raise "boom")`)
+	as.EqualError(err, "boom")
+
+	_, err = e.Eval(ctx, "(")
+	as.NotNil(err)
+}
+
+// chanWriter sends everything that's written to it to a channel
+type chanWriter chan string
+
+func (w chanWriter) Write(p []byte) (int, error) {
+	w <- string(p)
+	return len(p), nil
+}
+
+func TestEngineAsyncErrors(t *testing.T) {
+	as := assert.New(t)
+	ctx := context.Background()
+
+	// errors raised by asynchronous blocks don't crash the host, and
+	// are written to the Engine's *err* stream
+	stderr := make(chanWriter, 1)
+	e, err := ale.New(ale.WithStderr(stderr))
+	as.Nil(err)
+	res, err := e.Eval(ctx, `(go (raise "boom")) 1`)
+	as.Nil(err)
+	as.Equal(I(1), res)
+	as.Equal("error raised asynchronously: boom\n", <-stderr)
+
+	// or to the Engine's error handler, if it has one
+	errs := make(chan error, 1)
+	e, err = ale.New(ale.WithErrorHandler(func(err error) {
+		errs <- err
+	}))
+	as.Nil(err)
+	_, err = e.Eval(ctx, `(define (fail) (go (raise "bust")))`)
+	as.Nil(err)
+	_, err = e.Call(ctx, "fail")
+	as.Nil(err)
+	as.EqualError(<-errs, "bust")
+
+	// cancellations still unwind quietly
+	_, err = e.Eval(ctx, `
+		(let [s (scope)]
+		  (cancel s)
+		  (go (check-cancelled s)))
+	`)
+	as.Nil(err)
+	select {
+	case err := <-errs:
+		as.Fail("unexpected error", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestEngineClosureKeys(t *testing.T) {
+	as := assert.New(t)
+	ctx := context.Background()
+
+	e, err := ale.New()
+	as.Nil(err)
+	_, err = e.Eval(ctx, `
+		(define (key) 1)
+		(define keyed (assoc {:a 1 :b 2 :c 3} key :found))
+	`)
+	as.Nil(err)
+	for i := 0; i < 20; i++ {
+		res, err := e.Eval(ctx, "[(get keyed key) (keyed key)]")
+		as.Nil(err)
+		as.String("[:found :found]", res)
+	}
+}
+
+func TestEngineSetGet(t *testing.T) {
+	as := assert.New(t)
+	ctx := context.Background()
+
+	e, err := ale.New(ale.WithNamespace("host"))
+	as.Nil(err)
+	as.Equal(data.Name("host"), e.Namespace().Domain())
+
+	as.Nil(e.Set("double", func(i int) int { return i * 2 }))
+	as.Nil(e.Set("names", []string{"a", "b"}))
+	as.EqualError(e.Set("names", 1), "name is already bound in namespace: names")
+
+	res, err := e.Eval(ctx, "[(double 21) (names 1)]")
+	as.Nil(err)
+	as.String(`[42 "b"]`, res)
+
+	_, err = e.Eval(ctx, `(define greeting "hi") (define nums [1 2 3])`)
+	as.Nil(err)
+
+	var s string
+	as.Nil(e.Get("greeting", &s))
+	as.Equal("hi", s)
+
+	var nums []int
+	as.Nil(e.Get("host/nums", &nums))
+	as.Equal([]int{1, 2, 3}, nums)
+
+	var v data.Value
+	as.Nil(e.Get("nums", &v))
+	as.String("[1 2 3]", v)
+
+	as.EqualError(e.Get("missing", &v), "name is not bound: missing")
+	as.NotNil(e.Get("greeting", &nums))
+}
+
+func TestEngineCall(t *testing.T) {
+	as := assert.New(t)
+	ctx := context.Background()
+
+	e, err := ale.New()
+	as.Nil(err)
+	_, err = e.Eval(ctx, `
+		(define (add x y) (+ x y))
+		(define not-fn 99)
+		(define (spin) (spin))
+	`)
+	as.Nil(err)
+
+	res, err := e.Call(ctx, "add", 40, 2)
+	as.Nil(err)
+	as.Equal(I(42), res)
+
+	res, err = e.Call(ctx, "str", "a", 1)
+	as.Nil(err)
+	as.String("a1", res)
+
+	_, err = e.Call(ctx, "add", 1)
+	as.NotNil(err)
+
+	_, err = e.Call(ctx, "not-fn")
+	as.EqualError(err, "value is not a function: 99")
+
+	_, err = e.Call(ctx, "missing")
+	as.EqualError(err, "name is not bound: missing")
+
+	tc, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = e.Call(tc, "spin")
+	as.True(vm.IsLimitError(err))
+}
+
+func TestEngineLimits(t *testing.T) {
+	as := assert.New(t)
+	ctx := context.Background()
+
+	e, err := ale.New(ale.WithLimits(vm.Limits{Instructions: 10000}))
+	as.Nil(err)
+
+	_, err = e.Eval(ctx, "(let-rec [spin (lambda () (spin))] (spin))")
+	var de data.Error
+	as.True(errors.As(err, &de))
+	as.Equal(vm.BudgetExceeded, as.MustGet(de, data.TypeKey))
+
+	res, err := e.Eval(ctx, "(+ 1 2)")
+	as.Nil(err)
+	as.Equal(I(3), res)
+}
+
+func TestEngineSandbox(t *testing.T) {
+	as := assert.New(t)
+	ctx := context.Background()
+
+	var out bytes.Buffer
+	e, err := ale.New(
+		ale.WithSandbox(bootstrap.NewSandbox().DenyGroup(bootstrap.Eval)),
+		ale.WithStdout(&out),
+	)
+	as.Nil(err)
+
+	_, err = e.Eval(ctx, `(println "sandboxed")`)
+	as.Nil(err)
+	as.Equal("sandboxed\n", out.String())
+
+	_, err = e.Eval(ctx, "(eval '(+ 1 2))")
+	as.EqualError(err, "symbol is not allowed in this sandbox: eval")
+}
+
+func TestEngineShared(t *testing.T) {
+	as := assert.New(t)
+	ctx := context.Background()
+
+	s := bootstrap.NewShared()
+	var out1, out2 bytes.Buffer
+	e1, err := ale.New(ale.WithShared(s), ale.WithStdout(&out1))
+	as.Nil(err)
+	e2, err := ale.New(ale.WithShared(s), ale.WithStdout(&out2))
+	as.Nil(err)
+
+	_, err = e1.Eval(ctx, `(define x 1) (println "first")`)
+	as.Nil(err)
+	_, err = e2.Eval(ctx, `(println "second")`)
+	as.Nil(err)
+	as.Equal("first\n", out1.String())
+	as.Equal("second\n", out2.String())
+
+	_, err = e2.Eval(ctx, "x")
+	as.EqualError(err, "symbol not declared in namespace: x")
+}
+
+func TestEnginePrograms(t *testing.T) {
+	as := assert.New(t)
+	ctx := context.Background()
+
+	e, err := ale.New()
+	as.Nil(err)
+
+	p, err := e.Compile("(if (> amount limit) :review :approve)", "amount")
+	as.NotNil(err)
+	as.Nil(p)
+
+	as.Nil(e.Set("limit", 100))
+	p, err = e.Compile("(if (> amount limit) :review :approve)", "amount")
+	as.Nil(err)
+
+	res, err := e.Run(ctx, p, 50)
+	as.Nil(err)
+	as.Equal(K("approve"), res)
+	res, err = e.Run(ctx, p, 500)
+	as.Nil(err)
+	as.Equal(K("review"), res)
+
+	again, err := e.Compile("(if (> amount limit) :review :approve)", "amount")
+	as.Nil(err)
+	as.True(p == again)
+
+	_, err = e.Run(ctx, p)
+	as.NotNil(err)
+	_, err = e.Run(ctx, p, "not a number")
+	as.NotNil(err)
+}
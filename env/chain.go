@@ -37,3 +37,7 @@ func (ns *chainedNamespace) Resolve(n data.Name) (Entry, bool) {
 	}
 	return ns.parent.Resolve(n)
 }
+
+func (ns *chainedNamespace) Declared() data.Names {
+	return ns.child.Declared()
+}
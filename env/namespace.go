@@ -2,6 +2,7 @@ package env
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/kode4food/ale/data"
@@ -14,6 +15,7 @@ type (
 		Domain() data.Name
 		Declare(data.Name) Entry
 		Resolve(data.Name) (Entry, bool)
+		Declared() data.Names
 	}
 
 	// Entry represents a namespace entry
@@ -86,6 +88,19 @@ func (ns *namespace) Resolve(n data.Name) (Entry, bool) {
 	return nil, false
 }
 
+func (ns *namespace) Declared() data.Names {
+	ns.mutex.RLock()
+	defer ns.mutex.RUnlock()
+	res := make(data.Names, 0, len(ns.entries))
+	for n := range ns.entries {
+		res = append(res, n)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i] < res[j]
+	})
+	return res
+}
+
 func (e *entry) Owner() Namespace {
 	return e.owner
 }
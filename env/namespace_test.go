@@ -54,3 +54,58 @@ func TestChaining(t *testing.T) {
 	as.True(ok)
 	as.True(v8)
 }
+
+func TestDeclared(t *testing.T) {
+	as := assert.New(t)
+
+	e := env.NewEnvironment()
+	root := e.GetRoot()
+	root.Declare("b").Bind(data.True)
+	root.Declare("a")
+
+	ns := e.GetAnonymous()
+	ns.Declare("c")
+
+	as.Equal(data.Names{"a", "b"}, root.Declared())
+	as.Equal(data.Names{"c"}, ns.Declared())
+}
+
+func TestDerive(t *testing.T) {
+	as := assert.New(t)
+
+	shared := env.NewEnvironment()
+	root := shared.GetRoot()
+	root.Declare("bound").Bind(data.True)
+	root.Declare("unbound")
+
+	e := shared.Derive()
+	ns := e.GetRoot()
+	as.Equal(e, ns.Environment())
+	as.Equal(env.RootDomain, ns.Domain())
+
+	e1, ok := ns.Resolve("bound")
+	as.True(ok && e1.IsBound())
+	as.True(e1.Value())
+
+	_, ok = ns.Resolve("unbound")
+	as.False(ok)
+	ns.Declare("unbound").Bind(data.False)
+	e2, ok := ns.Resolve("unbound")
+	as.True(ok)
+	as.False(e2.Value())
+
+	ns.Declare("bound").Bind(data.False)
+	e3, ok := ns.Resolve("bound")
+	as.True(ok)
+	as.False(e3.Value())
+
+	v4, ok := env.ResolveValue(e.GetAnonymous(), env.RootSymbol("bound"))
+	as.True(ok)
+	as.False(v4)
+
+	as.Equal(data.Names{"bound", "unbound"}, ns.Declared())
+	as.Equal(data.Names{"bound", "unbound"}, root.Declared())
+	u, _ := root.Resolve("unbound")
+	as.False(u.IsBound())
+	as.True(root.Declare("bound").Value())
+}
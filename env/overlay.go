@@ -0,0 +1,38 @@
+package env
+
+import "github.com/kode4food/ale/data"
+
+// overlay is a root namespace that's layered over a shared one. Its own
+// entries shadow the shared ones, which are only visible once they've
+// been bound, so that they can't be bound through the overlay
+type overlay struct {
+	Namespace
+	shared Namespace
+}
+
+// Derive creates a new Environment whose root namespace is layered over
+// the root namespace of this one. Names are declared in the new root
+// without affecting the shared root, but the bound entries of the shared
+// root are resolved as if they were its own. Other namespaces aren't
+// shared
+func (e *Environment) Derive() *Environment {
+	res := NewEnvironment()
+	shared := e.GetRoot()
+	res.Get(RootDomain, func() Namespace {
+		return &overlay{
+			Namespace: res.New(RootDomain),
+			shared:    shared,
+		}
+	})
+	return res
+}
+
+func (ns *overlay) Resolve(n data.Name) (Entry, bool) {
+	if e, ok := ns.Namespace.Resolve(n); ok {
+		return e, true
+	}
+	if e, ok := ns.shared.Resolve(n); ok && e.IsBound() {
+		return e, true
+	}
+	return nil, false
+}
@@ -1,6 +1,8 @@
 package eval
 
 import (
+	"context"
+
 	"github.com/kode4food/ale/compiler/encoder"
 	"github.com/kode4food/ale/compiler/generate"
 	"github.com/kode4food/ale/data"
@@ -18,14 +20,25 @@ func String(ns env.Namespace, src data.String) data.Value {
 
 // Block evaluates a Sequence that a call to FromScanner might produce
 func Block(ns env.Namespace, s data.Sequence) data.Value {
-	var res data.Value
-	for f, r, ok := s.Split(); ok; f, r, ok = r.Split() {
-		e := encoder.NewEncoder(ns)
-		generate.Value(e, f)
-		e.Emit(isa.Return)
-		res = encodeAndRun(e)
-	}
-	return res
+	return block(ns, s, nil)
+}
+
+// StringContext evaluates the specified raw source, interrupting it
+// with an error if the Context is done or any of the Limits is exceeded
+func StringContext(
+	ctx context.Context, ns env.Namespace, src data.String, l vm.Limits,
+) data.Value {
+	r := read.FromString(src)
+	return BlockContext(ctx, ns, r, l)
+}
+
+// BlockContext evaluates a Sequence that a call to FromScanner might
+// produce, interrupting it with an error if the Context is done or any
+// of the Limits is exceeded. The Limits apply to the Sequence as a whole
+func BlockContext(
+	ctx context.Context, ns env.Namespace, s data.Sequence, l vm.Limits,
+) data.Value {
+	return block(ns, s, vm.NewMonitor(ctx, l))
 }
 
 // Value evaluates the provided Value
@@ -33,11 +46,25 @@ func Value(ns env.Namespace, v data.Value) data.Value {
 	e := encoder.NewEncoder(ns)
 	generate.Value(e, v)
 	e.Emit(isa.Return)
-	return encodeAndRun(e)
+	return encodeAndRun(e, nil)
 }
 
-func encodeAndRun(e encoder.Encoder) data.Value {
+func block(ns env.Namespace, s data.Sequence, m *vm.Monitor) data.Value {
+	var res data.Value
+	for f, r, ok := s.Split(); ok; f, r, ok = r.Split() {
+		e := encoder.NewEncoder(ns)
+		generate.Value(e, f)
+		e.Emit(isa.Return)
+		res = encodeAndRun(e, m)
+	}
+	return res
+}
+
+func encodeAndRun(e encoder.Encoder, m *vm.Monitor) data.Value {
 	fn := vm.LambdaFromEncoder(e)
 	closure := fn.Call().(data.Function)
+	if m != nil {
+		return m.Call(closure)
+	}
 	return closure.Call()
 }
@@ -1,7 +1,9 @@
 package eval_test
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/kode4food/ale/core/bootstrap"
 	"github.com/kode4food/ale/data"
@@ -10,6 +12,7 @@ import (
 	"github.com/kode4food/ale/internal/assert"
 	. "github.com/kode4food/ale/internal/assert/helpers"
 	"github.com/kode4food/ale/read"
+	"github.com/kode4food/ale/runtime/vm"
 )
 
 func TestBasicEval(t *testing.T) {
@@ -56,3 +59,96 @@ func TestBuiltIns(t *testing.T) {
 
 	as.String("there", eval.Block(b, tr))
 }
+
+func limitError(as *assert.Wrapper, fn func()) data.Value {
+	var res data.Value
+	func() {
+		defer func() {
+			err := recover().(data.Error)
+			as.True(vm.IsLimitError(err))
+			res = as.MustGet(err, data.TypeKey)
+		}()
+		fn()
+	}()
+	return res
+}
+
+func TestContextEval(t *testing.T) {
+	as := assert.New(t)
+
+	e := env.NewEnvironment()
+	bootstrap.Into(e)
+	ns := e.GetAnonymous()
+	ctx := context.Background()
+
+	as.Number(6, eval.StringContext(ctx, ns, "(+ 1 2 3)", vm.Limits{
+		Instructions: 1000,
+		Depth:        10,
+	}))
+
+	eval.String(ns, "(define (spin) (spin))")
+	eval.String(ns, "(define (deep n) (if (= n 0) 0 (+ 1 (deep (- n 1)))))")
+
+	as.Equal(vm.BudgetExceeded, limitError(as, func() {
+		eval.StringContext(ctx, ns, "(spin)", vm.Limits{Instructions: 10000})
+	}))
+
+	// the tasks that monitored code starts are monitored along with it
+	as.Equal(vm.BudgetExceeded, limitError(as, func() {
+		eval.StringContext(ctx, ns, "(deref (future (spin)))", vm.Limits{
+			Instructions: 10000,
+		})
+	}))
+
+	as.Number(100, eval.StringContext(ctx, ns, "(deep 100)", vm.Limits{
+		Depth: 200,
+	}))
+	as.Equal(vm.DepthExceeded, limitError(as, func() {
+		eval.StringContext(ctx, ns, "(deep 100)", vm.Limits{Depth: 50})
+	}))
+
+	tc, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	as.Equal(vm.TimedOut, limitError(as, func() {
+		eval.StringContext(tc, ns, "(spin)", vm.Limits{})
+	}))
+
+	cc, cancel := context.WithCancel(ctx)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	as.Equal(vm.Interrupted, limitError(as, func() {
+		eval.StringContext(cc, ns, "(spin)", vm.Limits{})
+	}))
+}
+
+func TestContextEvalRecover(t *testing.T) {
+	as := assert.New(t)
+
+	e := env.NewEnvironment()
+	bootstrap.Into(e)
+	ns := e.GetAnonymous()
+	ctx := context.Background()
+
+	eval.String(ns, "(define (deep n) (if (= n 0) 0 (+ 1 (deep (- n 1)))))")
+	as.Equal(K("depth-exceeded"), eval.StringContext(ctx, ns, `
+		(recover (lambda () (deep 100))
+		         (lambda (e) (:type e)))
+	`, vm.Limits{Depth: 50}))
+
+	// a spent budget isn't replenished, so catching its error doesn't
+	// allow the code to keep running
+	as.Equal(vm.BudgetExceeded, limitError(as, func() {
+		eval.StringContext(ctx, ns, `
+			(recover (lambda () (let-rec [spin (lambda () (spin))] (spin)))
+			         (lambda (e) (:type e)))
+		`, vm.Limits{Instructions: 5000})
+	}))
+
+	// the limits don't outlive the evaluation
+	as.Number(100, eval.String(ns, `
+		(let-rec [count (lambda (n) (if (= n 100) n (count (+ n 1))))]
+		  (count 0))
+	`))
+}
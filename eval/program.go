@@ -0,0 +1,173 @@
+package eval
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/kode4food/ale/compiler/encoder"
+	"github.com/kode4food/ale/compiler/generate"
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/env"
+	"github.com/kode4food/ale/internal/sequence"
+	"github.com/kode4food/ale/read"
+	"github.com/kode4food/ale/runtime/isa"
+	"github.com/kode4food/ale/runtime/vm"
+)
+
+type (
+	// Program is source that has been compiled once so that it can be
+	// run many times, concurrently if need be, with different arguments
+	// bound to its parameters
+	Program struct {
+		Lambda *vm.Lambda
+		Params []data.Name
+		fn     data.Function
+		refs   map[data.Name]globalRef
+		ns     env.Namespace
+	}
+
+	// Cache compiles Programs for a namespace, reusing the ones that it
+	// has already compiled unless the globals they refer to have changed
+	Cache struct {
+		sync.Mutex
+		ns       env.Namespace
+		programs map[string]*Program
+	}
+
+	globalRef struct {
+		entry env.Entry
+		bound bool
+	}
+
+	// recorder tracks the globals that a Program refers to while it's
+	// being compiled
+	recorder struct {
+		env.Namespace
+		refs map[data.Name]globalRef
+	}
+)
+
+var lambdaSym = env.RootSymbol("lambda")
+
+// Compile compiles the specified raw source into a Program whose forms
+// are evaluated with the provided parameter names bound to arguments
+func Compile(ns env.Namespace, src data.String, params ...data.Name) *Program {
+	forms := read.FromString(src)
+	args := make(data.Values, len(params))
+	for i, p := range params {
+		args[i] = data.NewLocalSymbol(p)
+	}
+	l := data.NewList(
+		append(data.Values{lambdaSym, data.NewList(args...)},
+			sequence.ToValues(forms)...,
+		)...,
+	)
+
+	r := &recorder{
+		Namespace: ns,
+		refs:      map[data.Name]globalRef{},
+	}
+	e := encoder.NewEncoder(r)
+	generate.Value(e, l)
+	e.Emit(isa.Return)
+	lambda := vm.LambdaFromEncoder(e)
+	fn := lambda.Call().(data.Function).Call().(data.Function)
+
+	return &Program{
+		Lambda: lambda,
+		Params: params,
+		fn:     fn,
+		refs:   r.stop(),
+		ns:     ns,
+	}
+}
+
+// Run runs the Program with the provided arguments bound to its
+// parameters and returns the value of its last form
+func (p *Program) Run(args ...data.Value) data.Value {
+	return p.fn.Call(args...)
+}
+
+// RunContext runs the Program like Run does, interrupting it with an
+// error if the Context is done or any of the Limits is exceeded
+func (p *Program) RunContext(
+	ctx context.Context, l vm.Limits, args ...data.Value,
+) data.Value {
+	return vm.NewMonitor(ctx, l).Call(p.fn, args...)
+}
+
+// IsStale returns whether any of the globals that the Program referred
+// to when it was compiled have since been declared, bound, or shadowed
+func (p *Program) IsStale() bool {
+	for n, ref := range p.refs {
+		e, ok := p.ns.Resolve(n)
+		if !ok {
+			if ref.entry != nil {
+				return true
+			}
+			continue
+		}
+		if e != ref.entry || e.IsBound() != ref.bound {
+			return true
+		}
+	}
+	return false
+}
+
+// NewCache returns a new Cache that compiles Programs for the provided
+// namespace
+func NewCache(ns env.Namespace) *Cache {
+	return &Cache{
+		ns:       ns,
+		programs: map[string]*Program{},
+	}
+}
+
+// Compile returns a Program for the specified raw source and parameter
+// names. It's only compiled if the Cache doesn't already have one, or
+// if that Program is stale
+func (c *Cache) Compile(src data.String, params ...data.Name) *Program {
+	key := cacheKey(src, params)
+	c.Lock()
+	defer c.Unlock()
+	if p, ok := c.programs[key]; ok && !p.IsStale() {
+		return p
+	}
+	p := Compile(c.ns, src, params...)
+	c.programs[key] = p
+	return p
+}
+
+func cacheKey(src data.String, params []data.Name) string {
+	var b strings.Builder
+	for _, p := range params {
+		b.WriteString(string(p))
+		b.WriteByte(' ')
+	}
+	b.WriteByte('\n')
+	b.WriteString(string(src))
+	return b.String()
+}
+
+func (r *recorder) Resolve(n data.Name) (env.Entry, bool) {
+	e, ok := r.Namespace.Resolve(n)
+	if refs := r.refs; refs != nil {
+		if _, seen := refs[n]; !seen {
+			ref := globalRef{}
+			if ok {
+				ref = globalRef{entry: e, bound: e.IsBound()}
+			}
+			refs[n] = ref
+		}
+	}
+	return e, ok
+}
+
+// stop ends the recording and returns the globals that were recorded.
+// The recorder remains the namespace of the compiled code
+func (r *recorder) stop() map[data.Name]globalRef {
+	res := r.refs
+	r.refs = nil
+	return res
+}
@@ -0,0 +1,114 @@
+package eval_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/kode4food/ale/core/bootstrap"
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/env"
+	"github.com/kode4food/ale/eval"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+)
+
+func TestProgram(t *testing.T) {
+	as := assert.New(t)
+
+	e := env.NewEnvironment()
+	bootstrap.Into(e)
+	ns := e.GetAnonymous()
+
+	p := eval.Compile(ns, "(let [sum (+ x y)] (* sum 2))", "x", "y")
+	as.Equal([]data.Name{"x", "y"}, p.Params)
+	as.NotNil(p.Lambda)
+	as.Equal(I(10), p.Run(I(2), I(3)))
+
+	// references are resolved once, when the program is compiled
+	defer as.ExpectPanic("symbol not declared in namespace: factor")
+	eval.Compile(ns, "(* x factor)", "x")
+}
+
+func TestProgramConcurrentRuns(t *testing.T) {
+	as := assert.New(t)
+
+	e := env.NewEnvironment()
+	bootstrap.Into(e)
+	ns := e.GetAnonymous()
+
+	p := eval.Compile(ns, "(if (> x 10) (* x 2) (+ x y))", "x", "y")
+	as.Equal(I(5), p.Run(I(2), I(3)))
+	as.Equal(I(40), p.Run(I(20), I(3)))
+
+	var wg sync.WaitGroup
+	res := make([]data.Value, 100)
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			res[i] = p.Run(I(int64(i)), I(1))
+		}(i)
+	}
+	wg.Wait()
+	for i, r := range res {
+		if i > 10 {
+			as.Equal(I(int64(i*2)), r)
+		} else {
+			as.Equal(I(int64(i+1)), r)
+		}
+	}
+}
+
+func TestProgramCache(t *testing.T) {
+	as := assert.New(t)
+
+	e := env.NewEnvironment()
+	bootstrap.Into(e)
+	ns := e.GetAnonymous()
+	c := eval.NewCache(ns)
+
+	eval.String(ns, "(declare rate)")
+	p1 := c.Compile("(* x rate)", "x")
+	as.False(p1.IsStale())
+	as.True(p1 == c.Compile("(* x rate)", "x"))
+	as.True(p1 != c.Compile("(* x rate)", "x", "y"))
+	as.True(p1 != c.Compile("(* x rate 1)", "x"))
+
+	// binding a referenced global makes the program stale
+	eval.String(ns, "(define rate 3)")
+	as.True(p1.IsStale())
+	p2 := c.Compile("(* x rate)", "x")
+	as.True(p1 != p2)
+	as.Equal(I(6), p2.Run(I(2)))
+	as.True(p2 == c.Compile("(* x rate)", "x"))
+
+	// so does shadowing one
+	p3 := c.Compile("(inc x)", "x")
+	as.Equal(I(3), p3.Run(I(2)))
+	eval.String(ns, "(define (inc x) (+ x 10))")
+	as.True(p3.IsStale())
+	as.Equal(I(12), c.Compile("(inc x)", "x").Run(I(2)))
+}
+
+func BenchmarkProgramRun(b *testing.B) {
+	e := env.NewEnvironment()
+	bootstrap.Into(e)
+	ns := e.GetAnonymous()
+	p := eval.Compile(ns, "(if (> x 10) (* x 2) (+ x 1))", "x")
+	arg := I(20)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		p.Run(arg)
+	}
+}
+
+func BenchmarkStringEval(b *testing.B) {
+	e := env.NewEnvironment()
+	bootstrap.Into(e)
+	ns := e.GetAnonymous()
+	eval.String(ns, "(define x 20)")
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		eval.String(ns, "(if (> x 10) (* x 2) (+ x 1))")
+	}
+}
@@ -0,0 +1,58 @@
+package ffi
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/kode4food/ale/data"
+)
+
+// Error messages
+const (
+	ErrStructRequired = "value must be a struct or a pointer to a struct"
+)
+
+// RecordTypeOf derives a RecordType from a Go struct. The struct's type
+// name becomes the name of the record, and its exported fields become
+// the record's fields, in declaration order and keyed as they would be
+// when wrapped as an Object
+func RecordTypeOf(i interface{}) (data.RecordType, error) {
+	t := reflect.TypeOf(i)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, errors.New(ErrStructRequired)
+	}
+	fLen := t.NumField()
+	fields := make([]data.Keyword, 0, fLen)
+	for i := 0; i < fLen; i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" { // Not exported
+			continue
+		}
+		fields = append(fields, getFieldKeyword(f))
+	}
+	return data.NewRecordType(data.Name(t.Name()), fields...), nil
+}
+
+// WrapRecord wraps a Go struct as a Record of the provided RecordType.
+// Records are unwrapped back into structs like any other Mapped value
+func WrapRecord(t data.RecordType, i interface{}) (data.Record, error) {
+	v := reflect.ValueOf(i)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, errors.New(ErrStructRequired)
+	}
+	w, err := wrapType(v.Type())
+	if err != nil {
+		return nil, err
+	}
+	res, err := w.Wrap(new(Context), v)
+	if err != nil {
+		return nil, err
+	}
+	return t.FromMapped(res.(data.Mapped)), nil
+}
@@ -0,0 +1,42 @@
+package ffi_test
+
+import (
+	"testing"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/ffi"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+)
+
+func TestRecordTypeOf(t *testing.T) {
+	as := assert.New(t)
+
+	rt, err := ffi.RecordTypeOf(testStructStateInfo())
+	as.Nil(err)
+	as.Equal(N("stateInfo"), rt.Name())
+	as.Equal([]data.Keyword{K("Name"), K("pop"), K("Loop")}, rt.Fields())
+
+	_, err = ffi.RecordTypeOf(99)
+	as.EqualError(err, ffi.ErrStructRequired)
+}
+
+func TestRecordWrap(t *testing.T) {
+	as := assert.New(t)
+
+	si := testStructStateInfo()
+	rt, _ := ffi.RecordTypeOf(si)
+	r, err := ffi.WrapRecord(rt, si)
+	as.Nil(err)
+	as.String(`#stateInfo{:Name "California" :pop 40 :Loop ()}`, r)
+
+	f := ffi.MustWrap(func(i *stateInfo) (string, int) {
+		return i.Name, i.Population
+	}).(data.Function)
+	res := f.Call(r.Put(C(K("pop"), I(41)))).(data.Vector).Values()
+	as.Equal(S("California"), res[0])
+	as.Equal(I(41), res[1])
+
+	_, err = ffi.WrapRecord(rt, "hello")
+	as.EqualError(err, ffi.ErrStructRequired)
+}
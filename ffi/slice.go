@@ -43,17 +43,27 @@ func (w *sliceWrapper) Wrap(c *Context, v reflect.Value) (data.Value, error) {
 
 func (w *sliceWrapper) Unwrap(v data.Value) (reflect.Value, error) {
 	if s, ok := v.(data.Sequence); ok {
-		in := sequence.ToValues(s)
-		inLen := len(in)
-		out := reflect.MakeSlice(w.typ, inLen, inLen)
-		for i := 0; i < inLen; i++ {
-			v, err := w.elem.Unwrap(in[i])
-			if err != nil {
-				return _emptyValue, err
-			}
-			out.Index(i).Set(v)
+		if c, ok := s.(data.CountedSequence); ok {
+			return w.unwrapCounted(c)
 		}
-		return out, nil
+		return w.unwrapCounted(sequence.ToVector(s))
 	}
 	return _emptyValue, errors.New(ErrValueMustBeSequence)
 }
+
+func (w *sliceWrapper) unwrapCounted(
+	s data.CountedSequence,
+) (reflect.Value, error) {
+	inLen := s.Count()
+	out := reflect.MakeSlice(w.typ, inLen, inLen)
+	it := data.MakeIterator(s)
+	for i := 0; i < inLen; i++ {
+		e, _ := it.Next()
+		v, err := w.elem.Unwrap(e)
+		if err != nil {
+			return _emptyValue, err
+		}
+		out.Index(i).Set(v)
+	}
+	return out, nil
+}
@@ -72,22 +72,30 @@ func (w *structWrapper) Wrap(c *Context, v reflect.Value) (data.Value, error) {
 }
 
 func (w *structWrapper) Unwrap(v data.Value) (reflect.Value, error) {
-	if s, ok := v.(data.Sequence); ok {
-		in, err := sequence.ToObject(s)
-		if err != nil {
-			return _emptyValue, err
-		}
-		out := reflect.New(w.typ).Elem()
-		for k, w := range w.fields {
-			if v, ok := in.Get(w.Keyword); ok {
-				v, err := w.Unwrap(v)
-				if err != nil {
-					return _emptyValue, err
-				}
-				out.FieldByName(k).Set(v)
+	in, err := toMapped(v)
+	if err != nil {
+		return _emptyValue, err
+	}
+	out := reflect.New(w.typ).Elem()
+	for k, w := range w.fields {
+		if v, ok := in.Get(w.Keyword); ok {
+			v, err := w.Unwrap(v)
+			if err != nil {
+				return _emptyValue, err
 			}
+			out.FieldByName(k).Set(v)
 		}
-		return out, nil
 	}
-	return _emptyValue, errors.New(ErrValueMustBeSequence)
+	return out, nil
+}
+
+func toMapped(v data.Value) (data.Mapped, error) {
+	switch v := v.(type) {
+	case data.Mapped:
+		return v, nil
+	case data.Sequence:
+		return sequence.ToObject(v)
+	default:
+		return nil, errors.New(ErrValueMustBeSequence)
+	}
 }
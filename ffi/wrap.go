@@ -23,7 +23,8 @@ type (
 
 // Error messages
 const (
-	ErrUnsupportedType = "unsupported type"
+	ErrUnsupportedType  = "unsupported type"
+	ErrTargetNotPointer = "unwrap target must be a non-nil pointer"
 )
 
 var (
@@ -58,6 +59,25 @@ func Wrap(i interface{}) (data.Value, error) {
 	return w.Wrap(new(Context), v)
 }
 
+// Unwrap marshals a data.Value into the native Go value that the
+// provided target points to
+func Unwrap(v data.Value, target interface{}) error {
+	p := reflect.ValueOf(target)
+	if p.Kind() != reflect.Ptr || p.IsNil() {
+		return errors.New(ErrTargetNotPointer)
+	}
+	w, err := wrapType(p.Type().Elem())
+	if err != nil {
+		return err
+	}
+	res, err := w.Unwrap(v)
+	if err != nil {
+		return err
+	}
+	p.Elem().Set(res)
+	return nil
+}
+
 func wrapType(t reflect.Type) (Wrapper, error) {
 	if w, ok := cache.get(t); ok {
 		return w, nil
@@ -79,9 +99,9 @@ func wrapType(t reflect.Type) (Wrapper, error) {
 }
 
 /*
-	Unsupported Kinds:
-	  * Uintptr
-	  * UnsafePointer
+Unsupported Kinds:
+  - Uintptr
+  - UnsafePointer
 */
 func makeWrappedType(t reflect.Type) (Wrapper, error) {
 	switch t.Kind() {
@@ -0,0 +1,27 @@
+package ffi_test
+
+import (
+	"testing"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/ffi"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+)
+
+func TestUnwrap(t *testing.T) {
+	as := assert.New(t)
+
+	var i int
+	as.Nil(ffi.Unwrap(I(42), &i))
+	as.Equal(42, i)
+
+	var s []string
+	as.Nil(ffi.Unwrap(data.NewVector(S("a"), S("b")), &s))
+	as.Equal([]string{"a", "b"}, s)
+
+	var b bool
+	as.EqualError(ffi.Unwrap(S("nope"), &b), ffi.ErrValueMustBeBool)
+	as.EqualError(ffi.Unwrap(I(1), i), ffi.ErrTargetNotPointer)
+	as.EqualError(ffi.Unwrap(I(1), (*int)(nil)), ffi.ErrTargetNotPointer)
+}
@@ -0,0 +1,77 @@
+package actor
+
+import (
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/do"
+)
+
+// Mailbox is the lazy Sequence of the messages received by a Process
+type Mailbox struct {
+	once  do.Action
+	proc  *Process
+	first data.Value
+	rest  data.Sequence
+	ok    bool
+}
+
+func newMailbox(p *Process) *Mailbox {
+	return &Mailbox{
+		once: do.Once(),
+		proc: p,
+	}
+}
+
+func (m *Mailbox) resolve() *Mailbox {
+	m.once(func() {
+		m.first = m.proc.Receive()
+		m.rest = newMailbox(m.proc)
+		m.ok = true
+	})
+	if !m.ok {
+		m.proc.checkKilled()
+	}
+	return m
+}
+
+// Process returns the Process that owns the Mailbox
+func (m *Mailbox) Process() *Process {
+	return m.proc
+}
+
+// IsEmpty blocks until a message arrives. A Mailbox is never empty
+func (m *Mailbox) IsEmpty() bool {
+	return !m.resolve().ok
+}
+
+// First returns the next message, blocking until it arrives
+func (m *Mailbox) First() data.Value {
+	return m.resolve().first
+}
+
+// Rest returns the Mailbox of the messages that follow the next one
+func (m *Mailbox) Rest() data.Sequence {
+	return m.resolve().rest
+}
+
+// Split returns the next message and the Mailbox of those that follow
+func (m *Mailbox) Split() (data.Value, data.Sequence, bool) {
+	r := m.resolve()
+	return r.first, r.rest, r.ok
+}
+
+// Type returns the type name of the Mailbox
+func (m *Mailbox) Type() data.Name {
+	return "mailbox"
+}
+
+// Equal compares this Mailbox to another for identity
+func (m *Mailbox) Equal(v data.Value) bool {
+	if v, ok := v.(*Mailbox); ok {
+		return m == v
+	}
+	return false
+}
+
+func (m *Mailbox) String() string {
+	return data.DumpString(m)
+}
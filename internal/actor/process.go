@@ -0,0 +1,389 @@
+package actor
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/stream"
+)
+
+// Process is an actor. It consumes the messages sent to its mailbox,
+// and can be linked to or monitored by other Processes so that they
+// are notified when it exits. Writes to its mailbox can take part in
+// a Select
+type Process struct {
+	*stream.Inbox
+	id     uint64
+	notify chan struct{}
+	killed chan struct{}
+	done   chan struct{}
+	kill   sync.Once
+	sup    *supervisor
+
+	mu       sync.Mutex
+	signals  []data.Value
+	links    map[*Process]bool
+	monitors map[*Process]bool
+	trapExit bool
+	exited   bool
+	killWith data.Value
+	reason   data.Value
+}
+
+const (
+	// ProcessType is the type name for a Process
+	ProcessType = data.String("process")
+
+	// ExitSignal is the type of message that a Process trapping exits
+	// receives when a linked Process exits, and the type of the error
+	// that a killed Process raises when reading its mailbox
+	ExitSignal = data.Keyword("exit")
+
+	// Down is the type of message that a monitoring Process receives
+	// when the monitored Process exits
+	Down = data.Keyword("down")
+
+	// ProcessKey is the key of an exit message's Process
+	ProcessKey = data.Keyword("process")
+
+	// ReasonKey is the key of an exit message's reason
+	ReasonKey = data.Keyword("reason")
+)
+
+// Exit reasons
+const (
+	Normal       = data.Keyword("normal")
+	Kill         = data.Keyword("kill")
+	Killed       = data.Keyword("killed")
+	Shutdown     = data.Keyword("shutdown")
+	RestartLimit = data.Keyword("restart-limit")
+)
+
+// Error messages
+const (
+	ErrProcessExited = "process has exited: %s"
+)
+
+var nextID uint64
+
+// Spawn starts a new Process that runs the provided function. The
+// Process exits with a reason of :normal when the function returns,
+// or with whatever it raises
+func Spawn(fn func(*Process), size int) *Process {
+	p := newProcess(size)
+	p.start(fn)
+	return p
+}
+
+func newProcess(size int) *Process {
+	return &Process{
+		id:       atomic.AddUint64(&nextID, 1),
+		Inbox:    stream.NewInbox(size),
+		notify:   make(chan struct{}, 1),
+		killed:   make(chan struct{}),
+		done:     make(chan struct{}),
+		links:    map[*Process]bool{},
+		monitors: map[*Process]bool{},
+	}
+}
+
+func (p *Process) start(fn func(*Process)) {
+	go func() {
+		defer func() {
+			p.exit(p.reasonFor(recover()))
+		}()
+		fn(p)
+	}()
+}
+
+func (p *Process) reasonFor(rec interface{}) data.Value {
+	select {
+	case <-p.killed:
+		return p.killWith
+	default:
+	}
+	if rec == nil {
+		return Normal
+	}
+	return reasonOf(rec)
+}
+
+// reasonOf converts a value recovered from a panic into an exit reason
+func reasonOf(rec interface{}) data.Value {
+	switch rec := rec.(type) {
+	case data.Value:
+		return rec
+	case error:
+		return data.String(rec.Error())
+	default:
+		return data.String(fmt.Sprint(rec))
+	}
+}
+
+func (p *Process) exit(reason data.Value) {
+	p.mu.Lock()
+	p.exited = true
+	p.reason = reason
+	links := p.links
+	monitors := p.monitors
+	p.links = nil
+	p.monitors = nil
+	p.signals = nil
+	p.mu.Unlock()
+
+	unregisterProcess(p)
+	close(p.done)
+	for l := range links {
+		l.removeLink(p)
+		l.Signal(p, reason)
+	}
+	for w := range monitors {
+		w.deliver(message(Down, p, reason))
+	}
+}
+
+// ID returns the Process' unique identifier
+func (p *Process) ID() uint64 {
+	return p.id
+}
+
+// Send places a message in the Process' mailbox, blocking if the
+// mailbox is full. Messages sent to an exited Process are discarded
+func (p *Process) Send(v data.Value) {
+	p.Inbox.Send(v, p.killed, p.done)
+}
+
+// TrySend places a message in the Process' mailbox if there's room for
+// it, without blocking. Returns whether the message was placed
+func (p *Process) TrySend(v data.Value) bool {
+	select {
+	case <-p.done:
+		return false
+	default:
+		return p.Inbox.TrySend(v)
+	}
+}
+
+// Receive returns the next message from the Process' mailbox, blocking
+// until one arrives. Exit and down messages take priority over those
+// that were sent. If the Process has been killed, an exit error is
+// raised instead
+func (p *Process) Receive() data.Value {
+	for {
+		p.checkKilled()
+		if v, ok := p.nextSignal(); ok {
+			return v
+		}
+		if v, ok := p.Inbox.Receive(p.notify, p.killed); ok {
+			return v
+		}
+	}
+}
+
+func (p *Process) checkKilled() {
+	select {
+	case <-p.killed:
+		panic(data.NewError(ExitSignal,
+			fmt.Sprintf(ErrProcessExited, p.killWith),
+			data.NewCons(ReasonKey, p.killWith),
+		))
+	default:
+	}
+}
+
+func (p *Process) nextSignal() (data.Value, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.signals) == 0 {
+		return nil, false
+	}
+	v := p.signals[0]
+	p.signals = p.signals[1:]
+	return v, true
+}
+
+func (p *Process) deliver(v data.Value) {
+	p.mu.Lock()
+	if p.exited {
+		p.mu.Unlock()
+		return
+	}
+	p.signals = append(p.signals, v)
+	p.mu.Unlock()
+	select {
+	case p.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Mailbox returns the lazy Sequence of the Process' messages
+func (p *Process) Mailbox() data.Sequence {
+	return newMailbox(p)
+}
+
+// Signal sends an exit signal to the Process. A reason of :kill always
+// kills it. Otherwise, a Process that traps exits receives an exit
+// message, and one that doesn't is killed unless the reason is :normal.
+// The sender may be nil if the signal didn't come from a Process
+func (p *Process) Signal(from *Process, reason data.Value) {
+	if Kill.Equal(reason) {
+		p.Kill(Killed)
+		return
+	}
+	p.mu.Lock()
+	trap := p.trapExit
+	p.mu.Unlock()
+	switch {
+	case trap:
+		p.deliver(message(ExitSignal, from, reason))
+	case !Normal.Equal(reason):
+		p.Kill(reason)
+	}
+}
+
+// Kill stops the Process with the provided reason. The Process exits
+// the next time it reads from its mailbox
+func (p *Process) Kill(reason data.Value) {
+	p.kill.Do(func() {
+		p.killWith = reason
+		close(p.killed)
+	})
+}
+
+// TrapExits sets whether exit signals from linked Processes are
+// converted into messages rather than killing the Process
+func (p *Process) TrapExits(trap bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.trapExit = trap
+}
+
+// IsAlive returns whether the Process has yet to exit
+func (p *Process) IsAlive() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return !p.exited
+}
+
+// Reason returns the reason that the Process exited with. Returns
+// false if it's still alive
+func (p *Process) Reason() (data.Value, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.reason, p.exited
+}
+
+// Done returns a channel that is closed when the Process exits
+func (p *Process) Done() <-chan struct{} {
+	return p.done
+}
+
+// Link links two Processes, so that when either exits, the other
+// receives an exit signal. If either has already exited, the other
+// receives the signal right away
+func Link(a, b *Process) {
+	if a == b {
+		return
+	}
+	if !a.addLink(b) {
+		reason, _ := a.Reason()
+		b.Signal(a, reason)
+		return
+	}
+	if !b.addLink(a) {
+		a.removeLink(b)
+		reason, _ := b.Reason()
+		a.Signal(b, reason)
+	}
+}
+
+// Unlink removes the link between two Processes
+func Unlink(a, b *Process) {
+	a.removeLink(b)
+	b.removeLink(a)
+}
+
+// Monitor arranges for the watcher to receive a down message when the
+// target exits. If it already has, the message is delivered right away
+func Monitor(watcher, target *Process) {
+	target.mu.Lock()
+	if target.exited {
+		target.mu.Unlock()
+		watcher.deliver(message(Down, target, target.reason))
+		return
+	}
+	target.monitors[watcher] = true
+	target.mu.Unlock()
+}
+
+// Demonitor stops the watcher from monitoring the target
+func Demonitor(watcher, target *Process) {
+	target.mu.Lock()
+	defer target.mu.Unlock()
+	delete(target.monitors, watcher)
+}
+
+func (p *Process) addLink(o *Process) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.exited {
+		return false
+	}
+	p.links[o] = true
+	return true
+}
+
+func (p *Process) removeLink(o *Process) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.links, o)
+}
+
+func message(typ data.Keyword, from *Process, reason data.Value) data.Value {
+	var proc data.Value = data.Nil
+	if from != nil {
+		proc = from
+	}
+	return data.NewObject(
+		data.NewCons(data.TypeKey, typ),
+		data.NewCons(ProcessKey, proc),
+		data.NewCons(ReasonKey, reason),
+	)
+}
+
+// Call sends each of its arguments to the Process' mailbox
+func (p *Process) Call(args ...data.Value) data.Value {
+	for _, a := range args {
+		p.Send(a)
+	}
+	return data.Nil
+}
+
+// Convention returns the Process' calling convention
+func (p *Process) Convention() data.Convention {
+	return data.ApplicativeCall
+}
+
+// CheckArity accepts any number of arguments
+func (p *Process) CheckArity(int) error {
+	return nil
+}
+
+// Type returns the type name of the Process
+func (p *Process) Type() data.Name {
+	return data.Name(ProcessType)
+}
+
+// Equal compares this Process to another for identity
+func (p *Process) Equal(v data.Value) bool {
+	if v, ok := v.(*Process); ok {
+		return p == v
+	}
+	return false
+}
+
+func (p *Process) String() string {
+	return data.DumpString(p)
+}
@@ -0,0 +1,146 @@
+package actor_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/actor"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+)
+
+func echo(out chan data.Value) func(*actor.Process) {
+	return func(p *actor.Process) {
+		for m := p.Mailbox(); ; m = m.Rest() {
+			out <- m.First()
+		}
+	}
+}
+
+func reason(p *actor.Process) data.Value {
+	<-p.Done()
+	r, _ := p.Reason()
+	return r
+}
+
+func TestProcessMailbox(t *testing.T) {
+	as := assert.New(t)
+
+	out := make(chan data.Value)
+	p := actor.Spawn(echo(out), 4)
+	p.Call(I(1), I(2))
+	as.Equal(I(1), <-out)
+	as.Equal(I(2), <-out)
+	as.True(p.IsAlive())
+	as.Contains(":type process", p)
+
+	p.Kill(S("stop"))
+	as.String("stop", reason(p))
+	as.False(p.IsAlive())
+	p.Send(I(3)) // discarded
+}
+
+func TestProcessReasons(t *testing.T) {
+	as := assert.New(t)
+
+	p := actor.Spawn(func(*actor.Process) {}, 0)
+	as.Equal(actor.Normal, reason(p))
+
+	p = actor.Spawn(func(*actor.Process) {
+		panic(errors.New("boom"))
+	}, 0)
+	as.String("boom", reason(p))
+
+	p = actor.Spawn(func(*actor.Process) {}, 1)
+	as.Equal(actor.Normal, reason(p))
+	p.Send(I(1))
+	p.Send(I(2)) // discarded, rather than blocking on the full mailbox
+}
+
+func TestLink(t *testing.T) {
+	as := assert.New(t)
+
+	a := actor.Spawn(echo(make(chan data.Value)), 0)
+	b := actor.Spawn(echo(make(chan data.Value)), 0)
+	actor.Link(a, b)
+	a.Kill(K("crash"))
+	as.Equal(K("crash"), reason(b))
+
+	out := make(chan data.Value)
+	c := actor.Spawn(echo(out), 0)
+	d := actor.Spawn(func(*actor.Process) {}, 0)
+	c.TrapExits(true)
+	actor.Link(c, d)
+	msg := (<-out).(data.Object)
+	as.Equal(actor.ExitSignal, as.MustGet(msg, data.TypeKey))
+	as.Equal(d, as.MustGet(msg, actor.ProcessKey))
+	as.Equal(actor.Normal, as.MustGet(msg, actor.ReasonKey))
+
+	c.Signal(nil, actor.Kill)
+	as.Equal(actor.Killed, reason(c))
+}
+
+func TestNormalExitIgnored(t *testing.T) {
+	as := assert.New(t)
+
+	out := make(chan data.Value)
+	a := actor.Spawn(echo(out), 0)
+	b := actor.Spawn(func(p *actor.Process) { p.Receive() }, 0)
+	actor.Link(a, b)
+	b.Send(K("go"))
+	<-b.Done()
+	a.Send(K("alive"))
+	as.Equal(K("alive"), <-out)
+	actor.Unlink(a, b)
+	a.Kill(actor.Shutdown)
+}
+
+func TestMonitor(t *testing.T) {
+	as := assert.New(t)
+
+	out := make(chan data.Value)
+	w := actor.Spawn(echo(out), 0)
+	target := actor.Spawn(func(p *actor.Process) { p.Receive() }, 0)
+	actor.Monitor(w, target)
+	target.Kill(K("gone"))
+	msg := (<-out).(data.Object)
+	as.Equal(actor.Down, as.MustGet(msg, data.TypeKey))
+	as.Equal(K("gone"), as.MustGet(msg, actor.ReasonKey))
+
+	actor.Monitor(w, target)
+	msg = (<-out).(data.Object)
+	as.Equal(K("gone"), as.MustGet(msg, actor.ReasonKey))
+	as.True(w.IsAlive())
+	w.Kill(actor.Shutdown)
+}
+
+func TestRegistry(t *testing.T) {
+	as := assert.New(t)
+
+	p := actor.Spawn(func(p *actor.Process) { p.Receive() }, 0)
+	as.Nil(actor.Register(K("reg-test"), p))
+	as.EqualError(
+		actor.Register(K("reg-test"), p),
+		"name is already registered: :reg-test",
+	)
+	res, ok := actor.Whereis(K("reg-test"))
+	as.True(ok)
+	as.Equal(p, res)
+	as.Contains(":reg-test", data.NewVector(toValues(actor.Registered())...))
+
+	p.Kill(actor.Shutdown)
+	<-p.Done()
+	_, ok = actor.Whereis(K("reg-test"))
+	as.False(ok)
+	as.NotNil(actor.Register(K("reg-test"), p))
+	as.False(actor.Unregister(K("reg-test")))
+}
+
+func toValues(k []data.Keyword) []data.Value {
+	res := make([]data.Value, len(k))
+	for i, e := range k {
+		res[i] = e
+	}
+	return res
+}
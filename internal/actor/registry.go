@@ -0,0 +1,78 @@
+package actor
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/kode4food/ale/data"
+)
+
+// Error messages
+const (
+	ErrAlreadyRegistered = "name is already registered: %s"
+)
+
+var registry = struct {
+	sync.Mutex
+	names map[data.Keyword]*Process
+}{
+	names: map[data.Keyword]*Process{},
+}
+
+// Register associates a name with a Process until it exits or the name
+// is unregistered
+func Register(name data.Keyword, p *Process) error {
+	registry.Lock()
+	defer registry.Unlock()
+	if _, ok := registry.names[name]; ok {
+		return fmt.Errorf(ErrAlreadyRegistered, name)
+	}
+	if reason, exited := p.Reason(); exited {
+		return fmt.Errorf(ErrProcessExited, reason)
+	}
+	registry.names[name] = p
+	return nil
+}
+
+// Unregister removes a name's association. Returns false if the name
+// wasn't registered
+func Unregister(name data.Keyword) bool {
+	registry.Lock()
+	defer registry.Unlock()
+	_, ok := registry.names[name]
+	delete(registry.names, name)
+	return ok
+}
+
+// Whereis returns the Process that is registered with the provided name
+func Whereis(name data.Keyword) (*Process, bool) {
+	registry.Lock()
+	defer registry.Unlock()
+	p, ok := registry.names[name]
+	return p, ok
+}
+
+// Registered returns the names that are currently registered, in order
+func Registered() []data.Keyword {
+	registry.Lock()
+	defer registry.Unlock()
+	res := make([]data.Keyword, 0, len(registry.names))
+	for n := range registry.names {
+		res = append(res, n)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i] < res[j]
+	})
+	return res
+}
+
+func unregisterProcess(p *Process) {
+	registry.Lock()
+	defer registry.Unlock()
+	for n, e := range registry.names {
+		if e == p {
+			delete(registry.names, n)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package actor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/clock"
+)
+
+type (
+	// Strategy determines which children a supervisor restarts when
+	// one of them exits
+	Strategy int
+
+	// Restart determines whether a child is restarted when it exits
+	Restart int
+
+	// StartFunc starts a supervised child, returning its Process
+	StartFunc func() *Process
+
+	// ChildSpec describes a child of a supervisor
+	ChildSpec struct {
+		ID      data.Value
+		Start   StartFunc
+		Restart Restart
+	}
+
+	// SupervisorSpec describes a supervisor. If more than Intensity
+	// restarts occur within Period, the supervisor terminates all of
+	// its children and exits with a reason of :restart-limit
+	SupervisorSpec struct {
+		Strategy  Strategy
+		Intensity int
+		Period    time.Duration
+		Children  []ChildSpec
+	}
+
+	// Child is a snapshot of a supervisor's child
+	Child struct {
+		ID      data.Value
+		Process *Process
+	}
+
+	supervisor struct {
+		SupervisorSpec
+		proc     *Process
+		mu       sync.Mutex
+		children []*child
+		retired  map[*Process]bool
+		restarts []time.Time
+	}
+
+	child struct {
+		ChildSpec
+		proc *Process
+	}
+)
+
+// Supervision strategies
+const (
+	// OneForOne restarts only the child that exited
+	OneForOne Strategy = iota
+
+	// OneForAll terminates and restarts all children when one exits
+	OneForAll
+)
+
+// Restart types
+const (
+	// Permanent children are always restarted
+	Permanent Restart = iota
+
+	// Transient children are restarted only if they exit abnormally
+	Transient
+
+	// Temporary children are never restarted
+	Temporary
+)
+
+// Default restart intensity
+const (
+	DefaultIntensity = 3
+	DefaultPeriod    = 5 * time.Second
+)
+
+// NewSupervisor starts the supervisor's children in order, linking
+// each of them to the supervisor's Process, which is returned. If a
+// child fails to start, those already started are terminated and the
+// reason for the failure is returned
+func NewSupervisor(spec SupervisorSpec) (*Process, data.Value) {
+	p := newProcess(0)
+	p.TrapExits(true)
+	s := &supervisor{
+		SupervisorSpec: spec,
+		proc:           p,
+		retired:        map[*Process]bool{},
+	}
+	p.sup = s
+	for _, cs := range spec.Children {
+		c := &child{ChildSpec: cs}
+		s.children = append(s.children, c)
+		if reason, ok := s.startChild(c); !ok {
+			s.terminateAll()
+			p.exit(reason)
+			return nil, reason
+		}
+	}
+	p.start(s.run)
+	return p, nil
+}
+
+// Children returns the current children of a supervisor's Process, in
+// the order that they were started. Returns false if the Process is
+// not a supervisor
+func Children(p *Process) ([]Child, bool) {
+	s := p.sup
+	if s == nil {
+		return nil, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := make([]Child, 0, len(s.children))
+	for _, c := range s.children {
+		if c.proc != nil {
+			res = append(res, Child{ID: c.ID, Process: c.proc})
+		}
+	}
+	return res, true
+}
+
+func (s *supervisor) run(p *Process) {
+	defer s.terminateAll()
+	for {
+		msg, ok := p.Receive().(data.Object)
+		if !ok {
+			continue
+		}
+		if t, _ := msg.Get(data.TypeKey); t != ExitSignal {
+			continue
+		}
+		from, _ := msg.Get(ProcessKey)
+		reason, _ := msg.Get(ReasonKey)
+		fp, _ := from.(*Process)
+		if s.retired[fp] {
+			delete(s.retired, fp)
+			continue
+		}
+		if c := s.childFor(fp); c != nil {
+			s.childExited(c, reason)
+			continue
+		}
+		if !Normal.Equal(reason) {
+			// the parent of the supervisor, or some other linked
+			// Process, has exited abnormally
+			p.Kill(reason)
+		}
+	}
+}
+
+func (s *supervisor) childFor(p *Process) *child {
+	if p == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.children {
+		if c.proc == p {
+			return c
+		}
+	}
+	return nil
+}
+
+func (s *supervisor) childExited(c *child, reason data.Value) {
+	s.setProcess(c, nil)
+	if !c.shouldRestart(reason) {
+		if c.Restart == Temporary {
+			s.removeChild(c)
+		}
+		return
+	}
+	if !s.allowRestart() {
+		s.proc.Kill(RestartLimit)
+		return
+	}
+	if s.Strategy == OneForAll {
+		s.terminateAll()
+		s.removeTemporary()
+		for _, o := range s.snapshot() {
+			if reason, ok := s.startChild(o); !ok {
+				s.childExited(o, reason)
+				return
+			}
+		}
+		return
+	}
+	if reason, ok := s.startChild(c); !ok {
+		s.childExited(c, reason)
+	}
+}
+
+func (s *supervisor) allowRestart() bool {
+	now := clock.Current().Now()
+	since := now.Add(-s.Period)
+	var recent []time.Time
+	for _, t := range s.restarts {
+		if t.After(since) {
+			recent = append(recent, t)
+		}
+	}
+	s.restarts = append(recent, now)
+	return len(s.restarts) <= s.Intensity
+}
+
+func (s *supervisor) startChild(c *child) (reason data.Value, ok bool) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			reason = reasonOf(rec)
+		}
+	}()
+	p := c.Start()
+	s.setProcess(c, p)
+	Link(s.proc, p)
+	return nil, true
+}
+
+// terminateAll sends a :shutdown exit signal to each child, in the
+// reverse of the order that they were started
+func (s *supervisor) terminateAll() {
+	children := s.snapshot()
+	for i := len(children) - 1; i >= 0; i-- {
+		c := children[i]
+		s.mu.Lock()
+		p := c.proc
+		c.proc = nil
+		s.mu.Unlock()
+		if p == nil {
+			continue
+		}
+		Unlink(s.proc, p)
+		if _, exited := p.Reason(); exited {
+			// its exit message may already be in the mailbox
+			s.retired[p] = true
+		}
+		p.Signal(s.proc, Shutdown)
+	}
+}
+
+func (s *supervisor) snapshot() []*child {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*child{}, s.children...)
+}
+
+func (s *supervisor) setProcess(c *child, p *Process) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c.proc = p
+}
+
+func (s *supervisor) removeChild(c *child) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, e := range s.children {
+		if e == c {
+			s.children = append(s.children[:i], s.children[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *supervisor) removeTemporary() {
+	for _, c := range s.snapshot() {
+		if c.Restart == Temporary {
+			s.removeChild(c)
+		}
+	}
+}
+
+func (c *child) shouldRestart(reason data.Value) bool {
+	switch c.Restart {
+	case Permanent:
+		return true
+	case Transient:
+		return !Normal.Equal(reason) && !Shutdown.Equal(reason)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,165 @@
+package actor_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/actor"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+)
+
+func worker(started chan *actor.Process) actor.StartFunc {
+	return func() *actor.Process {
+		p := actor.Spawn(func(p *actor.Process) {
+			for {
+				if m := p.Receive(); m == K("crash") {
+					panic(data.String("crashed"))
+				}
+			}
+		}, 0)
+		started <- p
+		return p
+	}
+}
+
+func childIDs(p *actor.Process) []data.Value {
+	c, _ := actor.Children(p)
+	res := make([]data.Value, len(c))
+	for i, e := range c {
+		res[i] = e.ID
+	}
+	return res
+}
+
+func TestOneForOne(t *testing.T) {
+	as := assert.New(t)
+
+	started := make(chan *actor.Process, 10)
+	sup, err := actor.NewSupervisor(actor.SupervisorSpec{
+		Strategy:  actor.OneForOne,
+		Intensity: 5,
+		Period:    time.Minute,
+		Children: []actor.ChildSpec{
+			{ID: K("a"), Start: worker(started)},
+			{ID: K("b"), Start: worker(started)},
+		},
+	})
+	as.Nil(err)
+	a, b := <-started, <-started
+	as.Equal([]data.Value{K("a"), K("b")}, childIDs(sup))
+
+	a.Send(K("crash"))
+	a2 := <-started
+	as.NotEqual(a, a2)
+	as.String("crashed", reason(a))
+	as.True(b.IsAlive())
+
+	sup.Kill(actor.Shutdown)
+	as.Equal(actor.Shutdown, reason(sup))
+	as.Equal(actor.Shutdown, reason(a2))
+	as.Equal(actor.Shutdown, reason(b))
+}
+
+func TestOneForAll(t *testing.T) {
+	as := assert.New(t)
+
+	started := make(chan *actor.Process, 10)
+	sup, _ := actor.NewSupervisor(actor.SupervisorSpec{
+		Strategy:  actor.OneForAll,
+		Intensity: 5,
+		Period:    time.Minute,
+		Children: []actor.ChildSpec{
+			{ID: K("a"), Start: worker(started)},
+			{ID: K("b"), Start: worker(started)},
+			{ID: K("t"), Start: worker(started), Restart: actor.Temporary},
+		},
+	})
+	a, b, tmp := <-started, <-started, <-started
+
+	b.Send(K("crash"))
+	a2, b2 := <-started, <-started
+	as.Equal(actor.Shutdown, reason(a))
+	as.Equal(actor.Shutdown, reason(tmp))
+	as.True(a2.IsAlive())
+	as.True(b2.IsAlive())
+	as.Equal([]data.Value{K("a"), K("b")}, childIDs(sup))
+	sup.Kill(actor.Kill)
+	as.Equal(actor.Kill, reason(sup))
+}
+
+func TestRestartIntensity(t *testing.T) {
+	as := assert.New(t)
+
+	started := make(chan *actor.Process, 10)
+	sup, _ := actor.NewSupervisor(actor.SupervisorSpec{
+		Intensity: 2,
+		Period:    time.Minute,
+		Children: []actor.ChildSpec{
+			{ID: K("a"), Start: worker(started)},
+		},
+	})
+	for i := 0; i < 2; i++ {
+		(<-started).Send(K("crash"))
+	}
+	last := <-started
+	last.Send(K("crash"))
+	as.Equal(actor.RestartLimit, reason(sup))
+	as.Equal(0, len(started))
+}
+
+func TestTransient(t *testing.T) {
+	as := assert.New(t)
+
+	started := make(chan *actor.Process, 10)
+	sup, _ := actor.NewSupervisor(actor.SupervisorSpec{
+		Intensity: 2,
+		Period:    time.Minute,
+		Children: []actor.ChildSpec{
+			{ID: K("a"), Start: worker(started), Restart: actor.Transient},
+		},
+	})
+	a := <-started
+	a.Kill(actor.Normal)
+	<-a.Done()
+	as.True(sup.IsAlive())
+	sup.Kill(actor.Shutdown)
+	<-sup.Done()
+	as.Equal(0, len(started))
+}
+
+func TestNestedSupervisor(t *testing.T) {
+	as := assert.New(t)
+
+	started := make(chan *actor.Process, 10)
+	inner := make(chan *actor.Process, 10)
+	top, _ := actor.NewSupervisor(actor.SupervisorSpec{
+		Intensity: 3,
+		Period:    time.Minute,
+		Children: []actor.ChildSpec{{
+			ID: K("inner"),
+			Start: func() *actor.Process {
+				p, _ := actor.NewSupervisor(actor.SupervisorSpec{
+					Intensity: 0,
+					Period:    time.Minute,
+					Children: []actor.ChildSpec{
+						{ID: K("w"), Start: worker(started)},
+					},
+				})
+				inner <- p
+				return p
+			},
+		}},
+	})
+	sup1 := <-inner
+	(<-started).Send(K("crash"))
+	as.Equal(actor.RestartLimit, reason(sup1))
+	sup2 := <-inner
+	as.True(sup2.IsAlive())
+	w := <-started
+
+	top.Kill(actor.Shutdown)
+	as.Equal(actor.Shutdown, reason(sup2))
+	as.Equal(actor.Shutdown, reason(w))
+}
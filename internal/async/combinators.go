@@ -0,0 +1,158 @@
+package async
+
+import (
+	"fmt"
+
+	"github.com/kode4food/ale/data"
+)
+
+// AllFailed is the type of error that Any fails with when none of its
+// Promises succeed
+const AllFailed = data.Keyword("all-failed")
+
+// ErrorsKey identifies the Errors of the Promises that Any waited on
+const ErrorsKey = data.Keyword("errors")
+
+// ErrAllFailed is the message of the Error that Any fails with
+const ErrAllFailed = "all %d promises failed"
+
+// Then returns a Promise that is resolved with the result of calling a
+// function with the Value of the provided Promise. If the provided
+// Promise fails, the returned Promise fails with the same Error
+func Then(p Promise, fn data.Function) Promise {
+	return chain(p, fn, nil)
+}
+
+// Catch returns a Promise that is resolved with the Value of the
+// provided Promise. If the provided Promise fails, the returned one is
+// instead resolved with the result of calling a function with the Error
+func Catch(p Promise, fn data.Function) Promise {
+	return chain(p, nil, fn)
+}
+
+// All returns a Promise that is resolved with a Vector of the Values of
+// all the provided Promises. If any of them fails, the returned Promise
+// fails with the first Error
+func All(promises ...Promise) Promise {
+	res := NewDeliverable().(*promise)
+	values := make(data.Values, len(promises))
+	remaining := make(chan struct{}, len(promises))
+	for i, p := range promises {
+		go func(i int, p Promise) {
+			v, err, ok := awaitUntil(p, res.done)
+			if !ok {
+				return
+			}
+			if err != nil {
+				res.Fail(err)
+				return
+			}
+			values[i] = v
+			remaining <- struct{}{}
+		}(i, p)
+	}
+	go func() {
+		for range promises {
+			select {
+			case <-remaining:
+			case <-res.done:
+				return
+			}
+		}
+		res.Deliver(data.NewVector(values...))
+	}()
+	return res
+}
+
+// Any returns a Promise that is resolved with the Value of the first of
+// the provided Promises to succeed. If they all fail, the returned
+// Promise fails with an Error that includes all of their Errors
+func Any(promises ...Promise) Promise {
+	res := NewDeliverable().(*promise)
+	errs := make(data.Values, len(promises))
+	failed := make(chan struct{}, len(promises))
+	for i, p := range promises {
+		go func(i int, p Promise) {
+			v, err, ok := awaitUntil(p, res.done)
+			if !ok {
+				return
+			}
+			if err == nil {
+				res.Deliver(v)
+				return
+			}
+			errs[i] = err
+			failed <- struct{}{}
+		}(i, p)
+	}
+	go func() {
+		for range promises {
+			select {
+			case <-failed:
+			case <-res.done:
+				return
+			}
+		}
+		res.Fail(data.NewError(AllFailed,
+			fmt.Sprintf(ErrAllFailed, len(promises)),
+			data.NewCons(ErrorsKey, data.NewVector(errs...)),
+		))
+	}()
+	return res
+}
+
+// Race returns a Promise that is resolved or fails along with the first
+// of the provided Promises to do so
+func Race(promises ...Promise) Promise {
+	res := NewDeliverable().(*promise)
+	for _, p := range promises {
+		go func(p Promise) {
+			v, err, ok := awaitUntil(p, res.done)
+			if !ok {
+				return
+			}
+			if err != nil {
+				res.Fail(err)
+				return
+			}
+			res.Deliver(v)
+		}(p)
+	}
+	return res
+}
+
+// awaitUntil waits for a Promise to be resolved, returning false if the
+// stop channel is closed first. This allows the goroutines that wait on
+// the Promises of a combinator to exit once its own result has settled
+func awaitUntil(
+	p Promise, stop <-chan struct{},
+) (data.Value, data.Error, bool) {
+	if p, ok := p.(*promise); ok {
+		return p.awaitUntil(stop)
+	}
+	v, err := p.Await()
+	return v, err, true
+}
+
+func chain(p Promise, onValue, onError data.Function) Promise {
+	res := NewDeliverable()
+	go func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				res.Fail(MakeError(rec))
+			}
+		}()
+		v, err := p.Await()
+		switch {
+		case err == nil && onValue != nil:
+			res.Deliver(onValue.Call(v))
+		case err == nil:
+			res.Deliver(v)
+		case onError != nil:
+			res.Deliver(onError.Call(err))
+		default:
+			res.Fail(err)
+		}
+	}()
+	return res
+}
@@ -0,0 +1,23 @@
+package async
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+)
+
+var goroutinePrefix = []byte("goroutine ")
+
+// goid returns the identifier of the calling goroutine, which is used
+// to track the ownership of locks. Go doesn't expose it, so it's parsed
+// from the header of the goroutine's stack trace
+func goid() uint64 {
+	var buf [64]byte
+	s := buf[:runtime.Stack(buf[:], false)]
+	s = bytes.TrimPrefix(s, goroutinePrefix)
+	if i := bytes.IndexByte(s, ' '); i > 0 {
+		s = s[:i]
+	}
+	id, _ := strconv.ParseUint(string(s), 10, 64)
+	return id
+}
@@ -0,0 +1,87 @@
+package async
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kode4food/ale/data"
+)
+
+// Latch is a countdown latch. Tasks can wait for it to be counted down
+// to zero by others
+type Latch struct {
+	mu    sync.Mutex
+	count int
+	done  chan struct{}
+}
+
+// LatchUnderflow is the type of error returned when a Latch is counted
+// down after it has reached zero
+const LatchUnderflow = data.Keyword("latch-underflow")
+
+// ErrLatchUnderflow is the message of the error returned when a Latch
+// is counted down after it has reached zero
+const ErrLatchUnderflow = "countdown has already reached zero"
+
+// NewLatch returns a new Latch that starts at the specified count
+func NewLatch(count int) *Latch {
+	l := &Latch{
+		count: count,
+		done:  make(chan struct{}),
+	}
+	if count <= 0 {
+		l.count = 0
+		close(l.done)
+	}
+	return l
+}
+
+// CountDown decrements the Latch, releasing its waiters when it
+// reaches zero. Returns the remaining count
+func (l *Latch) CountDown() (int, data.Error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.count == 0 {
+		return 0, data.NewError(LatchUnderflow, ErrLatchUnderflow)
+	}
+	l.count--
+	if l.count == 0 {
+		close(l.done)
+	}
+	return l.count, nil
+}
+
+// Count returns the remaining count
+func (l *Latch) Count() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.count
+}
+
+// Await blocks until the Latch reaches zero
+func (l *Latch) Await() {
+	<-l.done
+}
+
+// AwaitTimeout blocks until the Latch reaches zero, waiting at most the
+// provided duration. Returns whether it reached zero
+func (l *Latch) AwaitTimeout(d time.Duration) bool {
+	return wait(l.done, d)
+}
+
+// Type returns the type name of the Latch
+func (l *Latch) Type() data.Name {
+	return "countdown"
+}
+
+// Equal compares this Latch to another for identity
+func (l *Latch) Equal(v data.Value) bool {
+	if v, ok := v.(*Latch); ok {
+		return l == v
+	}
+	return false
+}
+
+func (l *Latch) String() string {
+	return data.DumpString(l)
+}
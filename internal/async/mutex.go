@@ -0,0 +1,269 @@
+package async
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kode4food/ale/data"
+)
+
+type (
+	// Locker is implemented by locks that have a single owner
+	Locker interface {
+		data.Value
+		Lock() data.Error
+		TryLock(time.Duration) (bool, data.Error)
+		Unlock() data.Error
+	}
+
+	// Mutex is a mutual exclusion lock that is owned by the task that
+	// locked it. It isn't reentrant
+	Mutex struct {
+		ch    chan struct{}
+		owner uint64
+	}
+
+	// RWMutex is a lock that can be held by many readers or a single
+	// writer. It isn't reentrant
+	RWMutex struct {
+		rw      sync.RWMutex
+		mu      sync.Mutex
+		readers map[uint64]bool
+		writer  uint64
+	}
+)
+
+// Lock misuse error types
+const (
+	NotLocked     = data.Keyword("not-locked")
+	NotOwner      = data.Keyword("not-owner")
+	AlreadyLocked = data.Keyword("already-locked")
+)
+
+// Error messages
+const (
+	ErrNotLocked     = "lock is not held"
+	ErrNotOwner      = "lock is held by another task"
+	ErrAlreadyLocked = "lock is already held by this task"
+	ErrNotReadLocked = "read lock is not held by this task"
+)
+
+// NewMutex returns a new unlocked Mutex
+func NewMutex() *Mutex {
+	return &Mutex{
+		ch: make(chan struct{}, 1),
+	}
+}
+
+// Lock blocks until the Mutex can be locked by the calling task.
+// Returns an error if the task already holds it
+func (m *Mutex) Lock() data.Error {
+	g := goid()
+	if atomic.LoadUint64(&m.owner) == g {
+		return data.NewError(AlreadyLocked, ErrAlreadyLocked)
+	}
+	m.ch <- struct{}{}
+	atomic.StoreUint64(&m.owner, g)
+	return nil
+}
+
+// TryLock attempts to lock the Mutex, waiting at most the provided
+// duration. Returns whether the Mutex was locked
+func (m *Mutex) TryLock(d time.Duration) (bool, data.Error) {
+	g := goid()
+	if atomic.LoadUint64(&m.owner) == g {
+		return false, data.NewError(AlreadyLocked, ErrAlreadyLocked)
+	}
+	if !acquire(m.ch, d) {
+		return false, nil
+	}
+	atomic.StoreUint64(&m.owner, g)
+	return true, nil
+}
+
+// Unlock unlocks the Mutex. Returns an error if it isn't held by the
+// calling task
+func (m *Mutex) Unlock() data.Error {
+	switch atomic.LoadUint64(&m.owner) {
+	case 0:
+		return data.NewError(NotLocked, ErrNotLocked)
+	case goid():
+		atomic.StoreUint64(&m.owner, 0)
+		<-m.ch
+		return nil
+	default:
+		return data.NewError(NotOwner, ErrNotOwner)
+	}
+}
+
+// Type returns the type name of the Mutex
+func (m *Mutex) Type() data.Name {
+	return "mutex"
+}
+
+// Equal compares this Mutex to another for identity
+func (m *Mutex) Equal(v data.Value) bool {
+	if v, ok := v.(*Mutex); ok {
+		return m == v
+	}
+	return false
+}
+
+func (m *Mutex) String() string {
+	return data.DumpString(m)
+}
+
+// NewRWMutex returns a new unlocked RWMutex
+func NewRWMutex() *RWMutex {
+	return &RWMutex{
+		readers: map[uint64]bool{},
+	}
+}
+
+// RLock blocks until the calling task can hold the RWMutex for reading
+func (m *RWMutex) RLock() data.Error {
+	g := goid()
+	if err := m.checkHeld(g); err != nil {
+		return err
+	}
+	m.rw.RLock()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.readers[g] = true
+	return nil
+}
+
+// RUnlock releases the calling task's hold on the RWMutex for reading
+func (m *RWMutex) RUnlock() data.Error {
+	g := goid()
+	m.mu.Lock()
+	if !m.readers[g] {
+		m.mu.Unlock()
+		return data.NewError(NotLocked, ErrNotReadLocked)
+	}
+	delete(m.readers, g)
+	m.mu.Unlock()
+	m.rw.RUnlock()
+	return nil
+}
+
+// Lock blocks until the calling task can hold the RWMutex for writing
+func (m *RWMutex) Lock() data.Error {
+	g := goid()
+	if err := m.checkHeld(g); err != nil {
+		return err
+	}
+	m.rw.Lock()
+	m.setWriter(g)
+	return nil
+}
+
+// TryLock attempts to hold the RWMutex for writing, waiting at most the
+// provided duration. Returns whether it's held
+func (m *RWMutex) TryLock(d time.Duration) (bool, data.Error) {
+	g := goid()
+	if err := m.checkHeld(g); err != nil {
+		return false, err
+	}
+	ch := make(chan struct{})
+	go func() {
+		m.rw.Lock()
+		close(ch)
+	}()
+	if !wait(ch, d) {
+		go func() {
+			// give up the lock once it's eventually obtained
+			<-ch
+			m.rw.Unlock()
+		}()
+		return false, nil
+	}
+	m.setWriter(g)
+	return true, nil
+}
+
+// Unlock releases the calling task's hold on the RWMutex for writing
+func (m *RWMutex) Unlock() data.Error {
+	g := goid()
+	m.mu.Lock()
+	switch m.writer {
+	case 0:
+		m.mu.Unlock()
+		return data.NewError(NotLocked, ErrNotLocked)
+	case g:
+		m.writer = 0
+		m.mu.Unlock()
+		m.rw.Unlock()
+		return nil
+	default:
+		m.mu.Unlock()
+		return data.NewError(NotOwner, ErrNotOwner)
+	}
+}
+
+func (m *RWMutex) checkHeld(g uint64) data.Error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.writer == g || m.readers[g] {
+		return data.NewError(AlreadyLocked, ErrAlreadyLocked)
+	}
+	return nil
+}
+
+func (m *RWMutex) setWriter(g uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.writer = g
+}
+
+// Type returns the type name of the RWMutex
+func (m *RWMutex) Type() data.Name {
+	return "rw-mutex"
+}
+
+// Equal compares this RWMutex to another for identity
+func (m *RWMutex) Equal(v data.Value) bool {
+	if v, ok := v.(*RWMutex); ok {
+		return m == v
+	}
+	return false
+}
+
+func (m *RWMutex) String() string {
+	return data.DumpString(m)
+}
+
+// acquire sends to a channel, waiting at most the provided duration
+func acquire(ch chan struct{}, d time.Duration) bool {
+	select {
+	case ch <- struct{}{}:
+		return true
+	default:
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case ch <- struct{}{}:
+		return true
+	case <-t.C:
+		return false
+	}
+}
+
+// wait waits for a channel to be closed, at most the provided duration
+func wait(ch <-chan struct{}, d time.Duration) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ch:
+		return true
+	case <-t.C:
+		return false
+	}
+}
@@ -0,0 +1,109 @@
+package async_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/assert"
+	"github.com/kode4food/ale/internal/async"
+)
+
+func inOtherTask(fn func() data.Error) data.Error {
+	res := make(chan data.Error)
+	go func() {
+		res <- fn()
+	}()
+	return <-res
+}
+
+func errorType(as *assert.Wrapper, err data.Error) data.Value {
+	as.NotNil(err)
+	return as.MustGet(err, data.TypeKey)
+}
+
+func TestMutex(t *testing.T) {
+	as := assert.New(t)
+	m := async.NewMutex()
+	as.Contains(":type mutex", m)
+	as.True(m.Equal(m))
+	as.False(m.Equal(async.NewMutex()))
+
+	as.Equal(async.NotLocked, errorType(as, m.Unlock()))
+	as.Nil(m.Lock())
+	as.Equal(async.AlreadyLocked, errorType(as, m.Lock()))
+	as.Equal(async.NotOwner, errorType(as, inOtherTask(m.Unlock)))
+
+	locked := make(chan bool)
+	go func() {
+		ok, _ := m.TryLock(10 * time.Millisecond)
+		locked <- ok
+	}()
+	as.False(<-locked)
+
+	as.Nil(m.Unlock())
+	ok, err := m.TryLock(0)
+	as.True(ok)
+	as.Nil(err)
+	_, err = m.TryLock(0)
+	as.Equal(async.AlreadyLocked, errorType(as, err))
+	as.Nil(m.Unlock())
+}
+
+func TestMutexExclusion(t *testing.T) {
+	as := assert.New(t)
+	m := async.NewMutex()
+	done := make(chan bool)
+	count := 0
+	for i := 0; i < 10; i++ {
+		go func() {
+			for j := 0; j < 100; j++ {
+				_ = m.Lock()
+				count++
+				_ = m.Unlock()
+			}
+			done <- true
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		<-done
+	}
+	as.Equal(1000, count)
+}
+
+func TestRWMutex(t *testing.T) {
+	as := assert.New(t)
+	m := async.NewRWMutex()
+	as.Contains(":type rw-mutex", m)
+
+	as.Equal(async.NotLocked, errorType(as, m.RUnlock()))
+	as.Equal(async.NotLocked, errorType(as, m.Unlock()))
+
+	as.Nil(m.RLock())
+	as.Nil(inOtherTask(func() data.Error {
+		if err := m.RLock(); err != nil {
+			return err
+		}
+		return m.RUnlock()
+	}))
+	as.Equal(async.AlreadyLocked, errorType(as, m.Lock()))
+	as.Equal(async.NotLocked, errorType(as, inOtherTask(m.RUnlock)))
+
+	locked := make(chan bool)
+	go func() {
+		ok, _ := m.TryLock(10 * time.Millisecond)
+		locked <- ok
+	}()
+	as.False(<-locked)
+	as.Nil(m.RUnlock())
+
+	as.Nil(m.Lock())
+	as.Equal(async.AlreadyLocked, errorType(as, m.RLock()))
+	as.Equal(async.NotOwner, errorType(as, inOtherTask(m.Unlock)))
+	as.Nil(m.Unlock())
+
+	ok, err := m.TryLock(10 * time.Millisecond)
+	as.True(ok)
+	as.Nil(err)
+	as.Nil(m.Unlock())
+}
@@ -1,6 +1,10 @@
 package async
 
 import (
+	"fmt"
+	"sync"
+	"time"
+
 	"github.com/kode4food/ale/data"
 	"github.com/kode4food/ale/internal/do"
 )
@@ -10,51 +14,154 @@ type (
 	Promise interface {
 		data.Function
 		IsResolved() bool
-	}
 
-	promiseStatus int
+		// Deliver resolves the Promise with a Value. Returns false if
+		// the Promise had already been resolved
+		Deliver(data.Value) bool
+
+		// Fail resolves the Promise with an Error. Returns false if the
+		// Promise had already been resolved
+		Fail(data.Error) bool
+
+		// Await waits for the Promise to be resolved, returning its
+		// Value or Error
+		Await() (data.Value, data.Error)
+
+		// AwaitTimeout waits for the Promise to be resolved, returning
+		// false if that doesn't happen within the provided duration
+		AwaitTimeout(time.Duration) (data.Value, data.Error, bool)
+	}
 
 	promise struct {
 		once     do.Action
 		resolver data.Function
-		result   interface{}
-		status   promiseStatus
+		settle   sync.Once
+		done     chan struct{}
+		result   data.Value
+		err      data.Error
 	}
 )
 
-const (
-	promisePending promiseStatus = iota
-	promiseResolved
-	promiseFailed
-)
+// PromiseFailed is the type of error a Promise fails with when its
+// resolver raises something other than an Error
+const PromiseFailed = data.Keyword("promise-failed")
 
 var promiseArityChecker = data.MakeFixedChecker(0)
 
-// NewPromise instantiates a new Promise
+// NewPromise instantiates a new Promise that is resolved by calling
+// the provided resolver the first time that its Value is requested
 func NewPromise(resolver data.Function) Promise {
 	return &promise{
 		once:     do.Once(),
 		resolver: resolver,
-		status:   promisePending,
+		done:     make(chan struct{}),
 	}
 }
 
-func (p *promise) Call(_ ...data.Value) data.Value {
+// NewDeliverable instantiates a new Promise that has no resolver. It
+// will only be resolved by a call to Deliver or Fail
+func NewDeliverable() Promise {
+	return NewPromise(nil)
+}
+
+// NewFuture instantiates a new Promise whose resolver is immediately
+// called asynchronously
+func NewFuture(resolver data.Function) Promise {
+	p := NewPromise(resolver).(*promise)
+	go p.resolve()
+	return p
+}
+
+// MakeError converts a value recovered from a panic into an Error. If
+// the value is already an Error, it is returned as is
+func MakeError(rec interface{}) data.Error {
+	if err, ok := rec.(data.Error); ok {
+		return err
+	}
+	msg := fmt.Sprint(rec)
+	if err, ok := rec.(error); ok {
+		msg = err.Error()
+	}
+	return data.NewError(PromiseFailed, msg,
+		data.NewCons(data.CauseKey, data.ErrorCause(rec)),
+	)
+}
+
+func (p *promise) resolve() {
+	if p.resolver == nil {
+		return
+	}
 	p.once(func() {
 		defer func() {
 			if rec := recover(); rec != nil {
-				p.result = rec
-				p.status = promiseFailed
+				p.Fail(MakeError(rec))
 			}
 		}()
-		p.result = p.resolver.Call()
-		p.status = promiseResolved
+		p.Deliver(p.resolver.Call())
 	})
+}
 
-	if p.status == promiseFailed {
-		panic(p.result)
+func (p *promise) Deliver(v data.Value) bool {
+	return p.complete(v, nil)
+}
+
+func (p *promise) Fail(err data.Error) bool {
+	return p.complete(data.Nil, err)
+}
+
+func (p *promise) complete(v data.Value, err data.Error) bool {
+	res := false
+	p.settle.Do(func() {
+		p.result = v
+		p.err = err
+		close(p.done)
+		res = true
+	})
+	return res
+}
+
+func (p *promise) Await() (data.Value, data.Error) {
+	p.resolve()
+	<-p.done
+	return p.result, p.err
+}
+
+func (p *promise) AwaitTimeout(d time.Duration) (data.Value, data.Error, bool) {
+	if p.resolver != nil && !p.IsResolved() {
+		go p.resolve()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-p.done:
+		return p.result, p.err, true
+	case <-t.C:
+		return data.Nil, nil, false
 	}
-	return p.result.(data.Value)
+}
+
+// awaitUntil waits for the Promise to be resolved, returning false if
+// the stop channel is closed first
+func (p *promise) awaitUntil(
+	stop <-chan struct{},
+) (data.Value, data.Error, bool) {
+	if p.resolver != nil && !p.IsResolved() {
+		go p.resolve()
+	}
+	select {
+	case <-p.done:
+		return p.result, p.err, true
+	case <-stop:
+		return data.Nil, nil, false
+	}
+}
+
+func (p *promise) Call(_ ...data.Value) data.Value {
+	res, err := p.Await()
+	if err != nil {
+		panic(err)
+	}
+	return res
 }
 
 func (p *promise) Convention() data.Convention {
@@ -66,7 +173,12 @@ func (p *promise) CheckArity(c int) error {
 }
 
 func (p *promise) IsResolved() bool {
-	return p.status != promisePending
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
 }
 
 func (p *promise) Type() data.Name {
@@ -2,7 +2,9 @@ package async_test
 
 import (
 	"errors"
+	"runtime"
 	"testing"
+	"time"
 
 	"github.com/kode4food/ale/data"
 	"github.com/kode4food/ale/internal/assert"
@@ -28,3 +30,88 @@ func TestPromiseFailure(t *testing.T) {
 	defer as.ExpectPanic("explosion")
 	c1.Call()
 }
+
+func TestPromiseDeliver(t *testing.T) {
+	as := assert.New(t)
+	p := async.NewDeliverable()
+	as.False(p.IsResolved())
+
+	_, _, ok := p.AwaitTimeout(time.Millisecond)
+	as.False(ok)
+
+	as.True(p.Deliver(S("hello")))
+	as.False(p.Deliver(S("goodbye")))
+	as.True(p.IsResolved())
+	as.String("hello", p.Call())
+}
+
+func TestPromiseStructuredFailure(t *testing.T) {
+	as := assert.New(t)
+	p := async.NewFuture(data.Applicative(func(_ ...data.Value) data.Value {
+		panic(errors.New("explosion"))
+	}, 0))
+	_, err := p.Await()
+	as.String("explosion", as.MustGet(err, data.MessageKey))
+	as.Equal(async.PromiseFailed, as.MustGet(err, data.TypeKey))
+
+	e := data.NewError("custom", "boom")
+	p = async.NewDeliverable()
+	p.Fail(e)
+	_, err = p.Await()
+	as.Equal(e, err)
+}
+
+func TestPromiseCombinators(t *testing.T) {
+	as := assert.New(t)
+	inc := data.Applicative(func(args ...data.Value) data.Value {
+		return args[0].(data.Integer) + 1
+	}, 1)
+	fail := data.NewError("failed", "failed")
+
+	p1 := async.NewDeliverable()
+	p2 := async.NewDeliverable()
+	then := async.Then(p1, inc)
+	all := async.All(p1, p2)
+	race := async.Race(p1, p2)
+	p1.Deliver(I(1))
+	as.Number(2, then.Call())
+	as.Number(1, race.Call())
+	as.False(all.IsResolved())
+	p2.Deliver(I(2))
+	as.String("[1 2]", all.Call())
+
+	p3 := async.NewDeliverable()
+	p3.Fail(fail)
+	caught := async.Catch(p3, data.Applicative(func(args ...data.Value) data.Value {
+		return as.MustGet(args[0].(data.Error), data.MessageKey)
+	}, 1))
+	as.String("failed", caught.Call())
+	as.String("1", async.Any(p3, p1).Call())
+
+	_, err := async.Any(p3, p3).Await()
+	as.Equal(async.AllFailed, as.MustGet(err, data.TypeKey))
+	_, err = async.All(p1, p3).Await()
+	as.Equal(fail, err)
+	_, err = async.Then(p3, inc).Await()
+	as.Equal(fail, err)
+}
+
+func TestPromiseCombinatorsRelease(t *testing.T) {
+	as := assert.New(t)
+
+	before := runtime.NumGoroutine()
+	pending := async.NewDeliverable()
+	done := async.NewDeliverable()
+	done.Deliver(I(1))
+	as.Number(1, async.Race(pending, done).Call())
+	as.Number(1, async.Any(pending, done).Call())
+	failed := async.NewDeliverable()
+	failed.Fail(data.NewError("failed", "failed"))
+	_, err := async.All(pending, failed).Await()
+	as.NotNil(err)
+
+	for i := 0; i < 100 && runtime.NumGoroutine() > before; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	as.True(runtime.NumGoroutine() <= before)
+}
@@ -0,0 +1,73 @@
+package async
+
+import (
+	"time"
+
+	"github.com/kode4food/ale/data"
+)
+
+// Semaphore limits the number of tasks that can hold one of its
+// permits at the same time. Permits aren't owned by the tasks that
+// acquire them, so any task may release one
+type Semaphore struct {
+	ch chan struct{}
+}
+
+// OverReleased is the type of error returned when a Semaphore has more
+// permits released than were acquired
+const OverReleased = data.Keyword("over-released")
+
+// ErrOverReleased is the message of the error returned when a Semaphore
+// has more permits released than were acquired
+const ErrOverReleased = "semaphore has no acquired permits to release"
+
+// NewSemaphore returns a new Semaphore with the specified number of
+// permits
+func NewSemaphore(permits int) *Semaphore {
+	return &Semaphore{
+		ch: make(chan struct{}, permits),
+	}
+}
+
+// Acquire blocks until a permit is available and acquires it
+func (s *Semaphore) Acquire() {
+	s.ch <- struct{}{}
+}
+
+// TryAcquire attempts to acquire a permit, waiting at most the
+// provided duration. Returns whether a permit was acquired
+func (s *Semaphore) TryAcquire(d time.Duration) bool {
+	return acquire(s.ch, d)
+}
+
+// Release releases a permit
+func (s *Semaphore) Release() data.Error {
+	select {
+	case <-s.ch:
+		return nil
+	default:
+		return data.NewError(OverReleased, ErrOverReleased)
+	}
+}
+
+// Available returns the number of permits that can be acquired
+func (s *Semaphore) Available() int {
+	return cap(s.ch) - len(s.ch)
+}
+
+// Type returns the type name of the Semaphore
+func (s *Semaphore) Type() data.Name {
+	return "semaphore"
+}
+
+// Equal compares this Semaphore to another for identity
+func (s *Semaphore) Equal(v data.Value) bool {
+	if v, ok := v.(*Semaphore); ok {
+		return s == v
+	}
+	return false
+}
+
+func (s *Semaphore) String() string {
+	return data.DumpString(s)
+}
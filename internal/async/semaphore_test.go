@@ -0,0 +1,61 @@
+package async_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kode4food/ale/internal/assert"
+	"github.com/kode4food/ale/internal/async"
+)
+
+func TestSemaphore(t *testing.T) {
+	as := assert.New(t)
+	s := async.NewSemaphore(2)
+	as.Contains(":type semaphore", s)
+	as.Equal(2, s.Available())
+
+	s.Acquire()
+	as.True(s.TryAcquire(0))
+	as.Equal(0, s.Available())
+	as.False(s.TryAcquire(10 * time.Millisecond))
+
+	acquired := make(chan bool)
+	go func() {
+		acquired <- s.TryAcquire(time.Second)
+	}()
+	// permits aren't owned, so any task can release them
+	as.Nil(inOtherTask(s.Release))
+	as.True(<-acquired)
+
+	as.Nil(s.Release())
+	as.Nil(s.Release())
+	as.Equal(async.OverReleased, errorType(as, s.Release()))
+}
+
+func TestLatch(t *testing.T) {
+	as := assert.New(t)
+	l := async.NewLatch(2)
+	as.Contains(":type countdown", l)
+	as.Equal(2, l.Count())
+	as.False(l.AwaitTimeout(10 * time.Millisecond))
+
+	done := make(chan bool)
+	go func() {
+		l.Await()
+		done <- true
+	}()
+
+	n, err := l.CountDown()
+	as.Equal(1, n)
+	as.Nil(err)
+	n, err = l.CountDown()
+	as.Equal(0, n)
+	as.Nil(err)
+	as.True(<-done)
+	as.True(l.AwaitTimeout(0))
+
+	_, err = l.CountDown()
+	as.Equal(async.LatchUnderflow, errorType(as, err))
+
+	as.True(async.NewLatch(0).AwaitTimeout(0))
+}
@@ -0,0 +1,93 @@
+package clock
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// Clock is a source of the current time and of Timers
+	Clock interface {
+		Now() time.Time
+		NewTimer(d time.Duration) Timer
+	}
+
+	// Timer delivers the time on its channel once its duration has
+	// elapsed, unless it is stopped first
+	Timer interface {
+		C() <-chan time.Time
+		Stop() bool
+	}
+
+	realClock struct{}
+
+	realTimer struct {
+		*time.Timer
+	}
+
+	// Ref holds the Clock that a group of timers are measured by. The
+	// Clock it holds can be replaced, such as by a Fake one in tests
+	Ref struct {
+		current atomic.Value
+	}
+
+	holder struct {
+		Clock
+	}
+)
+
+// Real is the Clock that reports the system's time
+var Real Clock = realClock{}
+
+var global = NewRef(Real)
+
+// NewRef returns a Ref that holds the provided Clock
+func NewRef(c Clock) *Ref {
+	r := &Ref{}
+	r.current.Store(holder{c})
+	return r
+}
+
+// Current returns the Clock that the Ref holds
+func (r *Ref) Current() Clock {
+	return r.current.Load().(holder).Clock
+}
+
+// Use replaces the Clock that the Ref holds, returning a function that
+// restores the previous one
+func (r *Ref) Use(c Clock) func() {
+	prev := r.Current()
+	r.current.Store(holder{c})
+	return func() {
+		r.current.Store(holder{prev})
+	}
+}
+
+// Current returns the Clock that is currently in use by the process
+func Current() Clock {
+	return global.Current()
+}
+
+// Use replaces the Clock that is currently in use by the process,
+// returning a function that restores the previous one
+func Use(c Clock) func() {
+	return global.Use(c)
+}
+
+// Sleep blocks for the provided duration, as measured by a Clock
+func Sleep(c Clock, d time.Duration) {
+	t := c.NewTimer(d)
+	<-t.C()
+}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
@@ -0,0 +1,173 @@
+package clock
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// Cron is a parsed cron-style schedule of minutes, hours, days of
+	// the month, months and days of the week
+	Cron struct {
+		minute, hour, dom, month, dow uint64
+		domAny, dowAny                bool
+	}
+
+	cronField struct {
+		min, max int
+	}
+)
+
+// Error messages
+const (
+	ErrCronFieldCount = "cron expression requires 5 fields: %s"
+	ErrCronField      = "invalid cron field: %s"
+	ErrCronRange      = "cron value out of range: %s"
+)
+
+var cronFields = [5]cronField{
+	{0, 59}, // minute
+	{0, 23}, // hour
+	{1, 31}, // day of the month
+	{1, 12}, // month
+	{0, 7},  // day of the week, where 0 and 7 are Sunday
+}
+
+var cronAliases = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronHorizon bounds the search for a matching time, so that schedules
+// that can never match (the 30th of February) terminate
+const cronHorizon = 5
+
+// ParseCron parses a cron expression of the form "minute hour
+// day-of-month month day-of-week". Each field may be a wildcard, a
+// value, a range, or a comma-separated list of them, and may include a
+// step. The common @-prefixed aliases, such as @hourly, are accepted
+func ParseCron(expr string) (*Cron, error) {
+	if alias, ok := cronAliases[expr]; ok {
+		expr = alias
+	}
+	parts := strings.Fields(expr)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf(ErrCronFieldCount, expr)
+	}
+	var bits [5]uint64
+	for i, p := range parts {
+		b, err := parseCronField(p, cronFields[i])
+		if err != nil {
+			return nil, err
+		}
+		bits[i] = b
+	}
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+	return &Cron{
+		minute: bits[0],
+		hour:   bits[1],
+		dom:    bits[2],
+		month:  bits[3],
+		dow:    bits[4],
+		domAny: parts[2] == "*",
+		dowAny: parts[4] == "*",
+	}, nil
+}
+
+func parseCronField(s string, f cronField) (uint64, error) {
+	var res uint64
+	for _, part := range strings.Split(s, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf(ErrCronField, s)
+			}
+			step = n
+			part = part[:i]
+		}
+
+		low, high := f.min, f.max
+		switch i := strings.Index(part, "-"); {
+		case part == "*":
+		case i >= 0:
+			l, err1 := strconv.Atoi(part[:i])
+			h, err2 := strconv.Atoi(part[i+1:])
+			if err1 != nil || err2 != nil || l > h {
+				return 0, fmt.Errorf(ErrCronField, s)
+			}
+			low, high = l, h
+		default:
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return 0, fmt.Errorf(ErrCronField, s)
+			}
+			low, high = n, n
+			if step != 1 {
+				high = f.max
+			}
+		}
+
+		if low < f.min || high > f.max {
+			return 0, fmt.Errorf(ErrCronRange, s)
+		}
+		for n := low; n <= high; n += step {
+			res |= 1 << uint(n)
+		}
+	}
+	if res == 0 {
+		return 0, fmt.Errorf(ErrCronField, s)
+	}
+	return res, nil
+}
+
+// Next returns the first time after the provided one that matches the
+// schedule. Returns false if no such time can be found
+func (c *Cron) Next(t time.Time) (time.Time, bool) {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Year() + cronHorizon
+	for t.Year() <= limit {
+		switch {
+		case !has(c.month, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !has(c.hour, t.Hour()):
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case !has(c.minute, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (c *Cron) dayMatches(t time.Time) bool {
+	dom := has(c.dom, t.Day())
+	dow := has(c.dow, int(t.Weekday()))
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dow
+	case c.dowAny:
+		return dom
+	default:
+		// like cron, a restricted day of the month or of the week
+		return dom || dow
+	}
+}
+
+func has(bits uint64, n int) bool {
+	return bits&(1<<uint(n)) != 0
+}
@@ -0,0 +1,61 @@
+package clock_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kode4food/ale/internal/assert"
+	"github.com/kode4food/ale/internal/clock"
+)
+
+func next(as *assert.Wrapper, expr string, from time.Time) time.Time {
+	c, err := clock.ParseCron(expr)
+	as.Nil(err)
+	res, ok := c.Next(from)
+	as.True(ok)
+	return res
+}
+
+func TestCronNext(t *testing.T) {
+	as := assert.New(t)
+
+	// 2026-10-19 12:00 is a Monday
+	as.Equal(epoch.Add(time.Minute), next(as, "* * * * *", epoch))
+	as.Equal(epoch.Add(15*time.Minute), next(as, "*/15 * * * *", epoch))
+	as.Equal(epoch.Add(time.Hour), next(as, "@hourly", epoch))
+	as.Equal(
+		time.Date(2026, time.October, 19, 14, 30, 0, 0, time.UTC),
+		next(as, "30 9-17/5 * * *", epoch),
+	)
+	as.Equal(
+		time.Date(2026, time.October, 25, 0, 0, 0, 0, time.UTC),
+		next(as, "0 0 * * 7", epoch),
+	)
+	as.Equal(
+		time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC),
+		next(as, "@yearly", epoch),
+	)
+	as.Equal(
+		time.Date(2026, time.October, 21, 8, 0, 0, 0, time.UTC),
+		next(as, "0 8 21,28 * 3", epoch),
+	)
+
+	c, err := clock.ParseCron("0 0 30 2 *")
+	as.Nil(err)
+	_, ok := c.Next(epoch)
+	as.False(ok)
+}
+
+func TestCronErrors(t *testing.T) {
+	as := assert.New(t)
+
+	_, err := clock.ParseCron("* * *")
+	as.EqualError(err, fmt.Sprintf(clock.ErrCronFieldCount, "* * *"))
+	_, err = clock.ParseCron("60 * * * *")
+	as.EqualError(err, fmt.Sprintf(clock.ErrCronRange, "60"))
+	_, err = clock.ParseCron("*/0 * * * *")
+	as.EqualError(err, fmt.Sprintf(clock.ErrCronField, "*/0"))
+	_, err = clock.ParseCron("5-1 * * * *")
+	as.EqualError(err, fmt.Sprintf(clock.ErrCronField, "5-1"))
+}
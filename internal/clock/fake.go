@@ -0,0 +1,97 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+type (
+	// Fake is a Clock whose time only moves when it is advanced, making
+	// the code that depends on it deterministic
+	Fake struct {
+		mu     sync.Mutex
+		now    time.Time
+		timers []*fakeTimer
+	}
+
+	fakeTimer struct {
+		clock    *Fake
+		c        chan time.Time
+		deadline time.Time
+	}
+)
+
+// NewFake returns a Fake Clock that starts at the provided time
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+// Now returns the Fake Clock's current time
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// NewTimer returns a Timer that fires when the Fake Clock is advanced
+// past its deadline
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t := &fakeTimer{
+		clock:    f,
+		c:        make(chan time.Time, 1),
+		deadline: f.now.Add(d),
+	}
+	if d <= 0 {
+		t.c <- f.now
+		return t
+	}
+	f.timers = append(f.timers, t)
+	return t
+}
+
+// Advance moves the Fake Clock forward, firing any Timers whose
+// deadlines have been reached in the order of those deadlines
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+	sort.SliceStable(f.timers, func(i, j int) bool {
+		return f.timers[i].deadline.Before(f.timers[j].deadline)
+	})
+	var pending []*fakeTimer
+	for _, t := range f.timers {
+		if t.deadline.After(f.now) {
+			pending = append(pending, t)
+			continue
+		}
+		t.c <- t.deadline
+	}
+	f.timers = pending
+}
+
+// Timers returns the number of Timers that are waiting to fire
+func (f *Fake) Timers() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.timers)
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	f := t.clock
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, e := range f.timers {
+		if e == t {
+			f.timers = append(f.timers[:i], f.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kode4food/ale/internal/assert"
+	"github.com/kode4food/ale/internal/clock"
+)
+
+var epoch = time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+
+func TestFakeClock(t *testing.T) {
+	as := assert.New(t)
+
+	c := clock.NewFake(epoch)
+	t1 := c.NewTimer(time.Second)
+	t2 := c.NewTimer(2 * time.Second)
+	t3 := c.NewTimer(3 * time.Second)
+	as.Equal(3, c.Timers())
+	as.True(t3.Stop())
+	as.False(t3.Stop())
+
+	c.Advance(time.Second)
+	as.Equal(epoch.Add(time.Second), c.Now())
+	as.Equal(epoch.Add(time.Second), <-t1.C())
+	as.Equal(1, c.Timers())
+
+	select {
+	case <-t2.C():
+		as.Fail("timer fired early")
+	default:
+	}
+
+	c.Advance(5 * time.Second)
+	as.Equal(epoch.Add(2*time.Second), <-t2.C())
+	as.Equal(0, c.Timers())
+
+	done := make(chan bool)
+	go func() {
+		clock.Sleep(c, 0)
+		done <- true
+	}()
+	as.True(<-done)
+}
+
+func TestUseClock(t *testing.T) {
+	as := assert.New(t)
+
+	c := clock.NewFake(epoch)
+	restore := clock.Use(c)
+	as.Equal(epoch, clock.Current().Now())
+	restore()
+	as.Equal(clock.Real, clock.Current())
+}
@@ -0,0 +1,142 @@
+package clock
+
+import (
+	"time"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/async"
+	"github.com/kode4food/ale/internal/stream"
+)
+
+type (
+	// Scheduler runs jobs after a delay or on a recurring schedule.
+	// Each job is represented by a cancellation Scope that is a child
+	// of the Scheduler's, so cancelling the Scheduler cancels them all
+	Scheduler struct {
+		clock   Clock
+		scope   *stream.Scope
+		onError ErrorHandler
+	}
+
+	// ErrorHandler is called when a scheduled job fails. The job is
+	// cancelled after the handler returns
+	ErrorHandler func(job *stream.Scope, err data.Error)
+
+	// NextFunc returns the time that a job should next run, given the
+	// time that it last ran. Returns false if it shouldn't run again
+	NextFunc func(prev time.Time) (time.Time, bool)
+)
+
+// SchedulerType is the type name for a Scheduler
+const SchedulerType = data.String("scheduler")
+
+// NewScheduler returns a new Scheduler whose jobs are timed by the
+// provided Clock
+func NewScheduler(c Clock, onError ErrorHandler) *Scheduler {
+	return &Scheduler{
+		clock:   c,
+		scope:   stream.NewScope(nil),
+		onError: onError,
+	}
+}
+
+// After schedules a job to run once, after the provided delay
+func (s *Scheduler) After(d time.Duration, job func()) *stream.Scope {
+	first := true
+	return s.Schedule(func(prev time.Time) (time.Time, bool) {
+		if !first {
+			return prev, false
+		}
+		first = false
+		return prev.Add(d), true
+	}, job)
+}
+
+// Every schedules a job to run repeatedly at the provided interval
+func (s *Scheduler) Every(d time.Duration, job func()) *stream.Scope {
+	return s.Schedule(func(prev time.Time) (time.Time, bool) {
+		return prev.Add(d), true
+	}, job)
+}
+
+// Cron schedules a job to run at the times matched by a Cron
+func (s *Scheduler) Cron(c *Cron, job func()) *stream.Scope {
+	return s.Schedule(c.Next, job)
+}
+
+// Schedule runs a job at the times returned by the provided NextFunc.
+// The timer for the job's next run is started before the job itself
+// is called, so that runs don't drift by the time that a job takes
+func (s *Scheduler) Schedule(next NextFunc, job func()) *stream.Scope {
+	res := stream.NewScope(s.scope)
+	at, ok := next(s.clock.Now())
+	if !ok {
+		res.Cancel()
+		return res
+	}
+	t := s.clock.NewTimer(at.Sub(s.clock.Now()))
+
+	go func() {
+		defer res.Cancel()
+		for {
+			select {
+			case <-t.C():
+			case <-res.Done():
+				t.Stop()
+				return
+			}
+			at, ok = next(at)
+			if ok {
+				t = s.clock.NewTimer(at.Sub(s.clock.Now()))
+			}
+			if !s.run(res, job) || !ok {
+				t.Stop()
+				return
+			}
+		}
+	}()
+	return res
+}
+
+func (s *Scheduler) run(scope *stream.Scope, job func()) (ok bool) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			if s.onError != nil {
+				s.onError(scope, async.MakeError(rec))
+			}
+			ok = false
+		}
+	}()
+	if scope.IsCancelled() {
+		return false
+	}
+	job()
+	return true
+}
+
+// Cancel cancels the Scheduler and all of its jobs
+func (s *Scheduler) Cancel() {
+	s.scope.Cancel()
+}
+
+// IsCancelled returns whether the Scheduler has been cancelled
+func (s *Scheduler) IsCancelled() bool {
+	return s.scope.IsCancelled()
+}
+
+// Type returns the type name of the Scheduler
+func (s *Scheduler) Type() data.Name {
+	return data.Name(SchedulerType)
+}
+
+// Equal compares this Scheduler to another for identity
+func (s *Scheduler) Equal(v data.Value) bool {
+	if v, ok := v.(*Scheduler); ok {
+		return s == v
+	}
+	return false
+}
+
+func (s *Scheduler) String() string {
+	return data.DumpString(s)
+}
@@ -0,0 +1,77 @@
+package clock_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/assert"
+	"github.com/kode4food/ale/internal/clock"
+	"github.com/kode4food/ale/internal/stream"
+)
+
+func TestSchedulerAfter(t *testing.T) {
+	as := assert.New(t)
+
+	c := clock.NewFake(epoch)
+	s := clock.NewScheduler(c, nil)
+	ran := make(chan time.Time, 1)
+	job := s.After(time.Second, func() { ran <- c.Now() })
+	as.Contains(":type scheduler", s)
+
+	c.Advance(time.Second)
+	as.Equal(epoch.Add(time.Second), <-ran)
+	<-job.Done()
+	as.True(job.IsCancelled())
+}
+
+func TestSchedulerEvery(t *testing.T) {
+	as := assert.New(t)
+
+	c := clock.NewFake(epoch)
+	s := clock.NewScheduler(c, nil)
+	ran := make(chan time.Time)
+	job := s.Every(time.Minute, func() { ran <- c.Now() })
+
+	for i := 1; i <= 3; i++ {
+		c.Advance(time.Minute)
+		as.Equal(epoch.Add(time.Duration(i)*time.Minute), <-ran)
+	}
+
+	s.Cancel()
+	as.True(s.IsCancelled())
+	<-job.Done()
+	as.True(job.IsCancelled())
+}
+
+func TestSchedulerCron(t *testing.T) {
+	as := assert.New(t)
+
+	c := clock.NewFake(epoch)
+	s := clock.NewScheduler(c, nil)
+	cron, _ := clock.ParseCron("*/30 * * * *")
+	ran := make(chan time.Time)
+	job := s.Cron(cron, func() { ran <- c.Now() })
+
+	c.Advance(30 * time.Minute)
+	as.Equal(epoch.Add(30*time.Minute), <-ran)
+	c.Advance(30 * time.Minute)
+	as.Equal(epoch.Add(time.Hour), <-ran)
+	job.Cancel()
+}
+
+func TestSchedulerError(t *testing.T) {
+	as := assert.New(t)
+
+	c := clock.NewFake(epoch)
+	errs := make(chan data.Error, 1)
+	s := clock.NewScheduler(c, func(_ *stream.Scope, err data.Error) {
+		errs <- err
+	})
+	job := s.Every(time.Second, func() { panic(errors.New("boom")) })
+	c.Advance(time.Second)
+	as.EqualError(<-errs, "boom")
+	<-job.Done()
+	as.True(job.IsCancelled())
+}
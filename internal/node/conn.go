@@ -0,0 +1,359 @@
+package node
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/actor"
+	"github.com/kode4food/ale/internal/wire"
+)
+
+type conn struct {
+	node    *Node
+	peer    data.Keyword
+	addr    string
+	net     net.Conn
+	reader  *bufio.Reader
+	watcher *actor.Process
+	once    sync.Once
+	done    chan struct{}
+
+	wmu sync.Mutex
+
+	mu      sync.Mutex
+	proxies map[uint64]*actor.Process
+	exits   map[uint64]data.Value
+	watched map[uint64]bool
+	pending map[uint64]chan data.Value
+}
+
+// Frame operations
+const (
+	hello   = data.Keyword("hello")
+	send    = data.Keyword("send")
+	exit    = data.Keyword("exit")
+	whereis = data.Keyword("whereis")
+	found   = data.Keyword("found")
+)
+
+var frameArgs = map[data.Keyword]int{
+	send:    2,
+	exit:    2,
+	whereis: 2,
+	found:   2,
+}
+
+// processExtension is the name of the wire extension that carries
+// process references
+const processExtension = data.Keyword("process")
+
+// MaxFrameSize is the size of the largest frame that will be accepted
+// from another Node
+const MaxFrameSize = 16 << 20
+
+// Error messages
+const (
+	ErrFrameTooLarge = "frame exceeds maximum size: %d"
+	ErrInvalidRef    = "invalid process reference: %s"
+)
+
+const proxyMailboxSize = 16
+
+func newConn(n *Node, nc net.Conn) *conn {
+	return &conn{
+		node:    n,
+		net:     nc,
+		reader:  bufio.NewReader(nc),
+		done:    make(chan struct{}),
+		proxies: map[uint64]*actor.Process{},
+		exits:   map[uint64]data.Value{},
+		watched: map[uint64]bool{},
+		pending: map[uint64]chan data.Value{},
+	}
+}
+
+func (c *conn) start() {
+	c.watcher = actor.Spawn(c.forwardExits, 0)
+	go c.read()
+}
+
+func (c *conn) read() {
+	defer c.close()
+	for {
+		v, err := c.readFrame()
+		switch {
+		case err == nil:
+			c.dispatch(v)
+		case isWireError(err):
+			// the frame was intact, but its value wasn't
+			continue
+		default:
+			return
+		}
+	}
+}
+
+func (c *conn) dispatch(v data.Value) {
+	f, ok := v.(data.Vector)
+	if !ok || f.Count() == 0 {
+		return
+	}
+	op, _ := f.Values()[0].(data.Keyword)
+	args := f.Values()[1:]
+	if len(args) != frameArgs[op] {
+		return
+	}
+	switch op {
+	case send:
+		// the read loop mustn't block, so a message is dropped if its
+		// recipient's mailbox is full
+		if p, ok := c.node.exported(id(args[0])); ok {
+			p.TrySend(args[1])
+		}
+	case exit:
+		i := id(args[0])
+		c.mu.Lock()
+		p := c.proxies[i]
+		delete(c.proxies, i)
+		if c.exits != nil {
+			c.exits[i] = args[1]
+		}
+		c.mu.Unlock()
+		if p != nil {
+			p.Kill(args[1])
+		}
+	case whereis:
+		var res data.Value = data.Nil
+		name, _ := args[1].(data.Keyword)
+		if p, ok := c.node.registered(name); ok {
+			res = p
+		}
+		_ = c.send(data.NewVector(found, args[0], res))
+	case found:
+		c.mu.Lock()
+		ch := c.pending[id(args[0])]
+		delete(c.pending, id(args[0]))
+		c.mu.Unlock()
+		if ch != nil {
+			ch <- args[1]
+		}
+	}
+}
+
+// forwardExits is the body of the process that monitors the local
+// processes that the peer has been sent references to, and tells the
+// peer when they exit
+func (c *conn) forwardExits(p *actor.Process) {
+	for {
+		msg, ok := p.Receive().(data.Object)
+		if !ok {
+			continue
+		}
+		v, _ := msg.Get(actor.ProcessKey)
+		e, ok := v.(*actor.Process)
+		if !ok {
+			continue
+		}
+		reason, _ := msg.Get(actor.ReasonKey)
+		c.mu.Lock()
+		delete(c.watched, e.ID())
+		c.mu.Unlock()
+		i := data.Integer(e.ID())
+		if c.send(data.NewVector(exit, i, reason)) != nil {
+			_ = c.send(data.NewVector(exit, i, data.String(reason.String())))
+		}
+	}
+}
+
+// proxy returns the local process that stands in for a process of the
+// peer, creating it if necessary
+func (c *conn) proxy(i uint64) *actor.Process {
+	c.mu.Lock()
+	if c.proxies == nil {
+		c.mu.Unlock()
+		return exited(NoConnection)
+	}
+	if p, ok := c.proxies[i]; ok && p.IsAlive() {
+		c.mu.Unlock()
+		return p
+	}
+	if reason, ok := c.exits[i]; ok {
+		c.mu.Unlock()
+		return exited(reason)
+	}
+	p := actor.Spawn(func(p *actor.Process) {
+		for {
+			msg := data.NewVector(send, data.Integer(i), p.Receive())
+			if err := c.send(msg); err != nil {
+				if !isWireError(err) {
+					c.close()
+				}
+				panic(err)
+			}
+		}
+	}, proxyMailboxSize)
+	c.proxies[i] = p
+	c.mu.Unlock()
+
+	c.node.addRemote(p, ref{node: c.peer, addr: c.addr, id: i})
+	return p
+}
+
+func (c *conn) request(name data.Keyword) (data.Value, error) {
+	req := c.node.newRequest()
+	ch := make(chan data.Value, 1)
+	c.mu.Lock()
+	if c.pending == nil {
+		c.mu.Unlock()
+		return nil, fmt.Errorf(ErrConnectionClosed, c.peer)
+	}
+	c.pending[req] = ch
+	c.mu.Unlock()
+
+	if err := c.send(data.NewVector(whereis, data.Integer(req), name)); err != nil {
+		return nil, err
+	}
+	select {
+	case v := <-ch:
+		return v, nil
+	case <-c.done:
+		return nil, fmt.Errorf(ErrConnectionClosed, c.peer)
+	}
+}
+
+func (c *conn) close() {
+	c.once.Do(func() {
+		_ = c.net.Close()
+		c.node.removePeer(c)
+		c.mu.Lock()
+		proxies := c.proxies
+		c.proxies = nil
+		c.exits = nil
+		c.pending = nil
+		close(c.done)
+		c.mu.Unlock()
+
+		if c.watcher != nil {
+			c.watcher.Kill(actor.Shutdown)
+		}
+		for _, p := range proxies {
+			p.Kill(NoConnection)
+		}
+	})
+}
+
+// send writes a frame to the peer. The local processes that the frame
+// references are monitored once it has been written, so that the
+// peer always learns of their exits after learning of them
+func (c *conn) send(v data.Value) error {
+	var local []*actor.Process
+	b, err := wire.MarshalWith(v, func(
+		v data.Value,
+	) (data.Keyword, data.Value, bool) {
+		p, ok := v.(*actor.Process)
+		if !ok {
+			return "", nil, false
+		}
+		r := c.node.refFor(p)
+		if r.node == c.node.name {
+			local = append(local, p)
+		}
+		return processExtension, data.NewVector(
+			r.node, data.String(r.addr), data.Integer(r.id),
+		), true
+	})
+	if err != nil {
+		return &wireError{err}
+	}
+
+	var size [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(size[:], uint64(len(b)))
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if _, err := c.net.Write(size[:n]); err != nil {
+		return err
+	}
+	if _, err := c.net.Write(b); err != nil {
+		return err
+	}
+	for _, p := range local {
+		c.watch(p)
+	}
+	return nil
+}
+
+func (c *conn) watch(p *actor.Process) {
+	c.mu.Lock()
+	if c.watched[p.ID()] {
+		c.mu.Unlock()
+		return
+	}
+	c.watched[p.ID()] = true
+	c.mu.Unlock()
+	actor.Monitor(c.watcher, p)
+}
+
+func (c *conn) readFrame() (data.Value, error) {
+	size, err := binary.ReadUvarint(c.reader)
+	if err != nil {
+		return nil, err
+	}
+	if size > MaxFrameSize {
+		return nil, fmt.Errorf(ErrFrameTooLarge, size)
+	}
+	b := make([]byte, size)
+	if _, err := io.ReadFull(c.reader, b); err != nil {
+		return nil, err
+	}
+	v, err := wire.UnmarshalWith(b, c.internal)
+	if err != nil {
+		return nil, &wireError{err}
+	}
+	return v, nil
+}
+
+func (c *conn) internal(
+	name data.Keyword, payload data.Value,
+) (data.Value, error) {
+	if name != processExtension {
+		return nil, fmt.Errorf(wire.ErrUnknownExtension, name)
+	}
+	v, ok := payload.(data.Vector)
+	if !ok || v.Count() != 3 {
+		return nil, fmt.Errorf(ErrInvalidRef, payload)
+	}
+	e := v.Values()
+	node, ok1 := e[0].(data.Keyword)
+	addr, ok2 := e[1].(data.String)
+	i, ok3 := e[2].(data.Integer)
+	if !ok1 || !ok2 || !ok3 {
+		return nil, fmt.Errorf(ErrInvalidRef, payload)
+	}
+	r := ref{node: node, addr: string(addr), id: uint64(i)}
+	if node == c.peer {
+		return c.proxy(r.id), nil
+	}
+	return c.node.process(r), nil
+}
+
+func id(v data.Value) uint64 {
+	if i, ok := v.(data.Integer); ok {
+		return uint64(i)
+	}
+	return 0
+}
+
+type wireError struct {
+	error
+}
+
+func isWireError(err error) bool {
+	var w *wireError
+	return errors.As(err, &w)
+}
@@ -0,0 +1,415 @@
+// Package node lets actors in separate Ale processes exchange messages
+// over TCP. Processes are referenced across the wire by the name of
+// their Node and an identifier, and are represented on other Nodes by
+// local proxy processes that forward whatever they receive
+package node
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/actor"
+)
+
+type (
+	// Node is an identity through which local processes can be reached
+	// by, and can reach, the processes of connected Nodes
+	Node struct {
+		name     data.Keyword
+		cookie   data.String
+		listener net.Listener
+		janitor  *actor.Process
+		nextReq  uint64
+
+		mu      sync.Mutex
+		peers   map[data.Keyword]*conn
+		names   map[data.Keyword]*actor.Process
+		exports map[uint64]*actor.Process
+		remotes map[*actor.Process]ref
+		closed  bool
+	}
+
+	ref struct {
+		node data.Keyword
+		addr string
+		id   uint64
+	}
+)
+
+const (
+	// NodeType is the type name for a Node
+	NodeType = data.String("node")
+
+	// NoConnection is the exit reason of a remote process' proxy when
+	// the connection to its Node is lost
+	NoConnection = data.Keyword("noconnection")
+
+	// NoProcess is the exit reason of a remote process' proxy when the
+	// process doesn't exist
+	NoProcess = data.Keyword("noproc")
+)
+
+// Error messages
+const (
+	ErrNodeClosed       = "node has been stopped: %s"
+	ErrNotConnected     = "node is not connected: %s"
+	ErrConnectionClosed = "connection to node was closed: %s"
+	ErrInvalidHandshake = "invalid handshake from: %s"
+	ErrDuplicateNode    = "node is already connected: %s"
+	ErrNameTaken        = "name is already registered with node: %s"
+)
+
+// Start starts a Node with the provided name, listening for connections
+// from other Nodes at the provided TCP address. Only Nodes that were
+// started with the same cookie can connect to one another. The cookie
+// is exchanged as is, so Nodes should only talk over a trusted network
+func Start(name data.Keyword, addr string, cookie string) (*Node, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	n := &Node{
+		name:     name,
+		cookie:   data.String(cookie),
+		listener: l,
+		peers:    map[data.Keyword]*conn{},
+		names:    map[data.Keyword]*actor.Process{},
+		exports:  map[uint64]*actor.Process{},
+		remotes:  map[*actor.Process]ref{},
+	}
+	n.janitor = actor.Spawn(n.forget, 0)
+	go n.accept()
+	return n, nil
+}
+
+// Name returns the Node's name
+func (n *Node) Name() data.Name {
+	return data.Name(n.name)
+}
+
+// Addr returns the TCP address that the Node is listening at
+func (n *Node) Addr() string {
+	return n.listener.Addr().String()
+}
+
+// Connect connects to the Node listening at the provided address,
+// returning its name. If the Node is already connected, the existing
+// connection is kept
+func (n *Node) Connect(addr string) (data.Keyword, error) {
+	c, err := n.dial(addr)
+	if err != nil {
+		return "", err
+	}
+	return c.peer, nil
+}
+
+// Peers returns the names of the connected Nodes, in order
+func (n *Node) Peers() []data.Keyword {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	res := make([]data.Keyword, 0, len(n.peers))
+	for name := range n.peers {
+		res = append(res, name)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i] < res[j]
+	})
+	return res
+}
+
+// Register makes a local process reachable by connected Nodes under the
+// provided name. Only the processes that are registered with the Node,
+// rather than with the process-wide registry, can be looked up by them
+func (n *Node) Register(name data.Keyword, p *actor.Process) error {
+	n.mu.Lock()
+	if e, ok := n.names[name]; ok && e.IsAlive() {
+		n.mu.Unlock()
+		return fmt.Errorf(ErrNameTaken, name)
+	}
+	n.names[name] = p
+	n.mu.Unlock()
+	actor.Monitor(n.janitor, p)
+	return nil
+}
+
+// Unregister stops the process that is registered with the provided
+// name from being reachable by connected Nodes, returning whether there
+// was one
+func (n *Node) Unregister(name data.Keyword) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	_, ok := n.names[name]
+	delete(n.names, name)
+	return ok
+}
+
+func (n *Node) registered(name data.Keyword) (*actor.Process, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	p, ok := n.names[name]
+	return p, ok
+}
+
+// Whereis asks a connected Node for the process that is registered
+// with it under the provided name. Returns false if there is none
+func (n *Node) Whereis(
+	peer data.Keyword, name data.Keyword,
+) (*actor.Process, bool, error) {
+	c, err := n.peer(peer)
+	if err != nil {
+		return nil, false, err
+	}
+	res, err := c.request(name)
+	if err != nil {
+		return nil, false, err
+	}
+	p, ok := res.(*actor.Process)
+	return p, ok, nil
+}
+
+// Close stops the Node, dropping all of its connections. Proxies for
+// the processes of the Nodes it was connected to exit with a reason of
+// :noconnection
+func (n *Node) Close() {
+	n.mu.Lock()
+	if n.closed {
+		n.mu.Unlock()
+		return
+	}
+	n.closed = true
+	peers := n.peers
+	n.peers = map[data.Keyword]*conn{}
+	n.mu.Unlock()
+
+	_ = n.listener.Close()
+	for _, c := range peers {
+		c.close()
+	}
+	n.janitor.Kill(actor.Shutdown)
+}
+
+func (n *Node) accept() {
+	for {
+		nc, err := n.listener.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			if _, err := n.handshake(nc, true); err != nil {
+				_ = nc.Close()
+			}
+		}()
+	}
+}
+
+func (n *Node) dial(addr string) (*conn, error) {
+	nc, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	c, err := n.handshake(nc, false)
+	if err != nil {
+		_ = nc.Close()
+		if c != nil {
+			// another connection to the same Node won the race
+			return c, nil
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// handshake exchanges names and cookies with the Node at the other end
+// of a new connection. The accepting side only replies once it has
+// checked the cookie and registered the connection, so that it's known
+// to both Nodes when Connect returns
+func (n *Node) handshake(nc net.Conn, accepting bool) (*conn, error) {
+	c := newConn(n, nc)
+	hi := data.NewVector(hello, n.name, data.String(n.Addr()), n.cookie)
+	if !accepting {
+		if err := c.send(hi); err != nil {
+			return nil, err
+		}
+	}
+	v, err := c.readFrame()
+	if err != nil {
+		return nil, err
+	}
+	f, ok := v.(data.Vector)
+	if !ok || f.Count() != 4 || f.Values()[0] != hello {
+		return nil, fmt.Errorf(ErrInvalidHandshake, nc.RemoteAddr())
+	}
+	peer, ok1 := f.Values()[1].(data.Keyword)
+	addr, ok2 := f.Values()[2].(data.String)
+	cookie, ok3 := f.Values()[3].(data.String)
+	if !ok1 || !ok2 || !ok3 || !n.checkCookie(cookie) {
+		return nil, fmt.Errorf(ErrInvalidHandshake, nc.RemoteAddr())
+	}
+	c.peer, c.addr = peer, string(addr)
+
+	n.mu.Lock()
+	if n.closed {
+		n.mu.Unlock()
+		return nil, fmt.Errorf(ErrNodeClosed, n.name)
+	}
+	if e, ok := n.peers[peer]; ok {
+		n.mu.Unlock()
+		if accepting {
+			// let the other side learn who it reached
+			_ = c.send(hi)
+		}
+		return e, fmt.Errorf(ErrDuplicateNode, peer)
+	}
+	n.peers[peer] = c
+	n.mu.Unlock()
+
+	if accepting {
+		if err := c.send(hi); err != nil {
+			c.close()
+			return nil, err
+		}
+	}
+	c.start()
+	return c, nil
+}
+
+func (n *Node) checkCookie(cookie data.String) bool {
+	return subtle.ConstantTimeCompare([]byte(cookie), []byte(n.cookie)) == 1
+}
+
+func (n *Node) peer(name data.Keyword) (*conn, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.closed {
+		return nil, fmt.Errorf(ErrNodeClosed, n.name)
+	}
+	if c, ok := n.peers[name]; ok {
+		return c, nil
+	}
+	return nil, fmt.Errorf(ErrNotConnected, name)
+}
+
+func (n *Node) removePeer(c *conn) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.peers[c.peer] == c {
+		delete(n.peers, c.peer)
+	}
+}
+
+// refFor returns the reference by which a process is known to other
+// Nodes. Local processes are exported so that messages can be routed
+// to them
+func (n *Node) refFor(p *actor.Process) ref {
+	n.mu.Lock()
+	if r, ok := n.remotes[p]; ok {
+		n.mu.Unlock()
+		return r
+	}
+	id := p.ID()
+	_, exported := n.exports[id]
+	n.exports[id] = p
+	n.mu.Unlock()
+
+	if !exported {
+		actor.Monitor(n.janitor, p)
+	}
+	return ref{node: n.name, addr: n.Addr(), id: id}
+}
+
+func (n *Node) exported(id uint64) (*actor.Process, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	p, ok := n.exports[id]
+	return p, ok
+}
+
+// process returns the process that a reference refers to. A process of
+// a Node that isn't connected isn't reached by dialing the address in
+// its reference, because that address came from a peer. It's instead
+// represented by one that has exited with a reason of :noconnection
+func (n *Node) process(r ref) *actor.Process {
+	if r.node == n.name {
+		if p, ok := n.exported(r.id); ok {
+			return p
+		}
+		return exited(NoProcess)
+	}
+	c, err := n.peer(r.node)
+	if err != nil {
+		return exited(NoConnection)
+	}
+	return c.proxy(r.id)
+}
+
+func (n *Node) addRemote(p *actor.Process, r ref) {
+	n.mu.Lock()
+	n.remotes[p] = r
+	n.mu.Unlock()
+	actor.Monitor(n.janitor, p)
+}
+
+// forget is the body of the process that removes exported processes,
+// registered names and proxies from the Node's tables once they've
+// exited
+func (n *Node) forget(p *actor.Process) {
+	for {
+		msg, ok := p.Receive().(data.Object)
+		if !ok {
+			continue
+		}
+		v, _ := msg.Get(actor.ProcessKey)
+		e, ok := v.(*actor.Process)
+		if !ok {
+			continue
+		}
+		n.mu.Lock()
+		if n.exports[e.ID()] == e {
+			delete(n.exports, e.ID())
+		}
+		for name, p := range n.names {
+			if p == e {
+				delete(n.names, name)
+			}
+		}
+		delete(n.remotes, e)
+		n.mu.Unlock()
+	}
+}
+
+func (n *Node) newRequest() uint64 {
+	return atomic.AddUint64(&n.nextReq, 1)
+}
+
+// exited returns a process that has already exited with the provided
+// reason, standing in for one that can't be reached
+func exited(reason data.Value) *actor.Process {
+	p := actor.Spawn(func(p *actor.Process) {
+		p.Receive()
+	}, 0)
+	p.Kill(reason)
+	<-p.Done()
+	return p
+}
+
+// Type returns the type name of the Node
+func (n *Node) Type() data.Name {
+	return data.Name(NodeType)
+}
+
+// Equal compares this Node to another for identity
+func (n *Node) Equal(v data.Value) bool {
+	if v, ok := v.(*Node); ok {
+		return n == v
+	}
+	return false
+}
+
+func (n *Node) String() string {
+	return data.DumpString(n)
+}
@@ -0,0 +1,230 @@
+package node_test
+
+import (
+	"testing"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/actor"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+	"github.com/kode4food/ale/internal/node"
+)
+
+const cookie = "node-test"
+
+func startNode(as *assert.Wrapper, name string) *node.Node {
+	n, err := node.Start(K(name), "127.0.0.1:0", cookie)
+	as.Nil(err)
+	return n
+}
+
+func collect(out chan data.Value) *actor.Process {
+	return actor.Spawn(func(p *actor.Process) {
+		for {
+			out <- p.Receive()
+		}
+	}, 0)
+}
+
+func reason(as *assert.Wrapper, v data.Value) data.Value {
+	msg := v.(data.Object)
+	as.Equal(actor.Down, as.MustGet(msg, data.TypeKey))
+	return as.MustGet(msg, actor.ReasonKey)
+}
+
+func TestConnect(t *testing.T) {
+	as := assert.New(t)
+
+	a := startNode(as, "conn-a")
+	b := startNode(as, "conn-b")
+	defer a.Close()
+	defer b.Close()
+
+	peer, err := a.Connect(b.Addr())
+	as.Nil(err)
+	as.Equal(K("conn-b"), peer)
+	peer, err = a.Connect(b.Addr())
+	as.Nil(err)
+	as.Equal(K("conn-b"), peer)
+	as.Equal([]data.Keyword{K("conn-b")}, a.Peers())
+	as.Equal(data.Name("conn-a"), a.Name())
+	as.Contains(":type node", a)
+
+	_, _, err = a.Whereis(K("missing"), K("x"))
+	as.EqualError(err, "node is not connected: :missing")
+}
+
+func TestCookie(t *testing.T) {
+	as := assert.New(t)
+
+	a := startNode(as, "cookie-a")
+	b, err := node.Start(K("cookie-b"), "127.0.0.1:0", "other")
+	as.Nil(err)
+	defer a.Close()
+	defer b.Close()
+
+	_, err = a.Connect(b.Addr())
+	as.NotNil(err)
+	as.Equal(0, len(a.Peers()))
+	as.Equal(0, len(b.Peers()))
+}
+
+func TestRegistry(t *testing.T) {
+	as := assert.New(t)
+
+	a := startNode(as, "reg-a")
+	b := startNode(as, "reg-b")
+	defer a.Close()
+	defer b.Close()
+	_, _ = a.Connect(b.Addr())
+
+	p := actor.Spawn(func(p *actor.Process) { p.Receive() }, 0)
+	as.Nil(actor.Register(K("reg-global"), p))
+	defer actor.Unregister(K("reg-global"))
+	_, ok, err := a.Whereis(K("reg-b"), K("reg-global"))
+	as.Nil(err)
+	as.False(ok)
+
+	as.Nil(b.Register(K("reg-local"), p))
+	as.EqualError(
+		b.Register(K("reg-local"), p),
+		"name is already registered with node: :reg-local",
+	)
+	_, ok, _ = a.Whereis(K("reg-b"), K("reg-local"))
+	as.True(ok)
+
+	as.True(b.Unregister(K("reg-local")))
+	as.False(b.Unregister(K("reg-local")))
+	_, ok, _ = a.Whereis(K("reg-b"), K("reg-local"))
+	as.False(ok)
+	p.Kill(actor.Shutdown)
+}
+
+func TestRemoteSend(t *testing.T) {
+	as := assert.New(t)
+
+	a := startNode(as, "send-a")
+	b := startNode(as, "send-b")
+	defer a.Close()
+	defer b.Close()
+	_, err := a.Connect(b.Addr())
+	as.Nil(err)
+
+	// echo replies to the process that's the first element of a message
+	echo := actor.Spawn(func(p *actor.Process) {
+		for {
+			msg := p.Receive().(data.Vector).Values()
+			msg[0].(*actor.Process).Send(data.NewVector(K("echo"), msg[1]))
+		}
+	}, 0)
+	as.Nil(b.Register(K("node-echo"), echo))
+	defer echo.Kill(actor.Shutdown)
+
+	remote, ok, err := a.Whereis(K("send-b"), K("node-echo"))
+	as.Nil(err)
+	as.True(ok)
+	as.NotEqual(echo, remote)
+
+	again, _, _ := a.Whereis(K("send-b"), K("node-echo"))
+	as.Equal(remote, again)
+
+	out := make(chan data.Value)
+	local := collect(out)
+	remote.Send(data.NewVector(local, S("hello")))
+	as.String(`[:echo "hello"]`, <-out)
+
+	_, ok, err = a.Whereis(K("send-b"), K("not-registered"))
+	as.Nil(err)
+	as.False(ok)
+}
+
+func TestRemoteMonitor(t *testing.T) {
+	as := assert.New(t)
+
+	a := startNode(as, "mon-a")
+	b := startNode(as, "mon-b")
+	defer a.Close()
+	_, _ = a.Connect(b.Addr())
+
+	target := actor.Spawn(func(p *actor.Process) { p.Receive() }, 0)
+	as.Nil(b.Register(K("node-target"), target))
+	other := actor.Spawn(func(p *actor.Process) { p.Receive() }, 0)
+	as.Nil(b.Register(K("node-other"), other))
+	defer other.Kill(actor.Shutdown)
+
+	out := make(chan data.Value)
+	w := collect(out)
+
+	remote, _, _ := a.Whereis(K("mon-b"), K("node-target"))
+	actor.Monitor(w, remote)
+	target.Kill(K("crashed"))
+	as.Equal(K("crashed"), reason(as, <-out))
+
+	remote, _, _ = a.Whereis(K("mon-b"), K("node-other"))
+	actor.Monitor(w, remote)
+	b.Close()
+	as.Equal(node.NoConnection, reason(as, <-out))
+	as.True(other.IsAlive())
+}
+
+func TestThirdNode(t *testing.T) {
+	as := assert.New(t)
+
+	a := startNode(as, "third-a")
+	b := startNode(as, "third-b")
+	c := startNode(as, "third-c")
+	defer a.Close()
+	defer b.Close()
+	defer c.Close()
+	_, _ = a.Connect(b.Addr())
+	_, _ = a.Connect(c.Addr())
+
+	out := make(chan data.Value)
+	sink := collect(out)
+	as.Nil(c.Register(K("third-sink"), sink))
+	defer sink.Kill(actor.Shutdown)
+
+	fwd := actor.Spawn(func(p *actor.Process) {
+		for {
+			msg := p.Receive().(data.Vector).Values()
+			msg[0].(*actor.Process).Send(msg[1])
+		}
+	}, 0)
+	as.Nil(b.Register(K("third-fwd"), fwd))
+	defer fwd.Kill(actor.Shutdown)
+
+	// a sends b a reference to a process on c, which b doesn't dial
+	remoteSink, _, _ := a.Whereis(K("third-c"), K("third-sink"))
+	remoteFwd, _, _ := a.Whereis(K("third-b"), K("third-fwd"))
+	remoteFwd.Send(data.NewVector(remoteSink, K("dropped")))
+
+	// once b is connected to c, the reference resolves through it
+	_, _ = b.Connect(c.Addr())
+	remoteFwd.Send(data.NewVector(remoteSink, K("relayed")))
+	as.Equal(K("relayed"), <-out)
+	as.Equal([]data.Keyword{K("third-a"), K("third-c")}, b.Peers())
+}
+
+func TestUnencodable(t *testing.T) {
+	as := assert.New(t)
+
+	a := startNode(as, "enc-a")
+	b := startNode(as, "enc-b")
+	defer a.Close()
+	defer b.Close()
+	_, _ = a.Connect(b.Addr())
+
+	sink := actor.Spawn(func(p *actor.Process) { p.Receive() }, 0)
+	as.Nil(b.Register(K("enc-sink"), sink))
+	defer sink.Kill(actor.Shutdown)
+
+	out := make(chan data.Value)
+	w := collect(out)
+	remote, _, _ := a.Whereis(K("enc-b"), K("enc-sink"))
+	actor.Monitor(w, remote)
+	remote.Send(data.Applicative(func(...data.Value) data.Value {
+		return data.Nil
+	}))
+	as.Contains("value can't be encoded", reason(as, <-out))
+	as.Equal([]data.Keyword{K("enc-b")}, a.Peers())
+}
@@ -36,7 +36,8 @@ func ToValues(s data.Sequence) data.Values {
 	case data.CountedSequence:
 		res := make(data.Values, s.Count())
 		idx := 0
-		for f, r, ok := s.Split(); ok; f, r, ok = r.Split() {
+		it := data.MakeIterator(s)
+		for f, ok := it.Next(); ok; f, ok = it.Next() {
 			res[idx] = f
 			idx++
 		}
@@ -48,7 +49,8 @@ func ToValues(s data.Sequence) data.Values {
 
 func uncountedToValues(s data.Sequence) data.Values {
 	res := data.Values{}
-	for f, r, ok := s.Split(); ok; f, r, ok = r.Split() {
+	it := data.MakeIterator(s)
+	for f, ok := it.Next(); ok; f, ok = it.Next() {
 		res = append(res, f)
 	}
 	return res
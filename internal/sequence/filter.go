@@ -5,11 +5,10 @@ import "github.com/kode4food/ale/data"
 // Filter creates a new filtered Sequence
 func Filter(s data.Sequence, filter data.Function) data.Sequence {
 	var res LazyResolver
-	next := s
+	it := data.MakeIterator(s)
 
 	res = func() (data.Value, data.Sequence, bool) {
-		for f, r, ok := next.Split(); ok; f, r, ok = r.Split() {
-			next = r
+		for f, ok := it.Next(); ok; f, ok = it.Next() {
 			if data.Truthy(filter.Call(f)) {
 				return f, NewLazy(res), true
 			}
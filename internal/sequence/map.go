@@ -0,0 +1,18 @@
+package sequence
+
+import "github.com/kode4food/ale/data"
+
+// Map creates a new Sequence whose elements are the result of applying
+// the mapper to each element of the provided Sequence
+func Map(s data.Sequence, mapper data.Function) data.Sequence {
+	var res LazyResolver
+	it := data.MakeIterator(s)
+
+	res = func() (data.Value, data.Sequence, bool) {
+		if f, ok := it.Next(); ok {
+			return mapper.Call(f), NewLazy(res), true
+		}
+		return data.Nil, data.EmptyList, false
+	}
+	return NewLazy(res)
+}
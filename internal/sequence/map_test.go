@@ -0,0 +1,28 @@
+package sequence_test
+
+import (
+	"testing"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+	"github.com/kode4food/ale/internal/sequence"
+)
+
+func TestMap(t *testing.T) {
+	as := assert.New(t)
+
+	double := data.Applicative(func(args ...data.Value) data.Value {
+		return args[0].(data.Integer) * 2
+	}, 1)
+
+	m := sequence.Map(V(I(1), I(2), I(3)), double)
+	as.String("[2 4 6]", sequence.ToVector(m))
+
+	m = sequence.Map(L(I(4)), double)
+	as.Equal(I(8), m.First())
+	as.True(m.Rest().IsEmpty())
+
+	m = sequence.Map(data.EmptyList, double)
+	as.True(m.IsEmpty())
+}
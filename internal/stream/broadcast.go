@@ -0,0 +1,278 @@
+package stream
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/kode4food/ale/data"
+)
+
+type (
+	// BufferPolicy determines what happens when a value is offered to a
+	// Channel whose buffer is full
+	BufferPolicy int
+
+	// TopicFunc returns the topic of a value being published
+	TopicFunc func(data.Value) data.Value
+
+	// Broadcast distributes the values of a source Sequence to any
+	// number of subscribed Channels. If the Broadcast was created with
+	// a TopicFunc, subscribers only receive the values whose topic
+	// matches their own
+	Broadcast struct {
+		mu     sync.Mutex
+		topic  TopicFunc
+		subs   []*subscriber
+		closed bool
+	}
+
+	subscriber struct {
+		ch     *channelWrapper
+		topic  data.Value
+		policy BufferPolicy
+	}
+)
+
+// Buffer policies
+const (
+	// Block waits until there is room in the buffer
+	Block BufferPolicy = iota
+
+	// DropOldest discards the oldest buffered value to make room
+	DropOldest
+
+	// DropNewest discards the value being offered
+	DropNewest
+)
+
+// BroadcastType is the type name for a Broadcast
+const BroadcastType = data.String("broadcast")
+
+// NewMult returns a Broadcast that distributes every value of the
+// source Sequence to all of its subscribers
+func NewMult(src data.Sequence) *Broadcast {
+	return NewPub(src, nil)
+}
+
+// NewPub returns a Broadcast that distributes the values of the source
+// Sequence to the subscribers of their topic
+func NewPub(src data.Sequence, topic TopicFunc) *Broadcast {
+	b := &Broadcast{topic: topic}
+	go b.run(src)
+	return b
+}
+
+// Offer writes a Value to a Channel, following the provided policy if
+// its buffer is full. Returns whether the Value was written
+func Offer(s Selectable, v data.Value, p BufferPolicy) bool {
+	return offer(s.channel(), channelResult{value: v}, p)
+}
+
+func offer(ch *channelWrapper, r channelResult, p BufferPolicy) (res bool) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			// the Channel was closed while we were writing to it
+			res = false
+		}
+	}()
+
+	if atomic.LoadUint32(&ch.status) != channelReady {
+		return false
+	}
+	switch p {
+	case DropNewest:
+		select {
+		case ch.seq <- r:
+			return true
+		default:
+			return false
+		}
+	case DropOldest:
+		for {
+			select {
+			case ch.seq <- r:
+				return true
+			default:
+			}
+			select {
+			case <-ch.seq:
+			default:
+			}
+		}
+	default:
+		select {
+		case ch.seq <- r:
+		case <-ch.cancel:
+			ch.Close()
+			return false
+		}
+		if atomic.LoadUint32(&ch.status) == channelCloseRequested {
+			ch.Close()
+		}
+		return true
+	}
+}
+
+// Merge returns a Channel that receives the values of all the source
+// Sequences as they become available. The Channel is closed once all
+// of the sources are exhausted
+func Merge(size int, sources ...data.Sequence) (Emitter, data.Sequence) {
+	e, s := NewChannel(size)
+	ch := e.(Selectable).channel()
+	var wg sync.WaitGroup
+	wg.Add(len(sources))
+	for _, src := range sources {
+		go func(src data.Sequence) {
+			defer wg.Done()
+			defer func() {
+				if rec := recover(); rec != nil {
+					offer(ch, channelResult{value: data.Nil, error: rec}, Block)
+				}
+			}()
+			it := data.MakeIterator(src)
+			for v, ok := it.Next(); ok; v, ok = it.Next() {
+				if !offer(ch, channelResult{value: v}, Block) {
+					return
+				}
+			}
+		}(src)
+	}
+	go func() {
+		wg.Wait()
+		e.Close()
+	}()
+	return e, s
+}
+
+// Split returns a pair of Channels. Values of the source Sequence for
+// which the predicate returns true are written to the first, and the
+// rest are written to the second
+func Split(
+	src data.Sequence, pred func(data.Value) bool, size int, p BufferPolicy,
+) (Emitter, data.Sequence, Emitter, data.Sequence) {
+	te, ts := NewChannel(size)
+	fe, fs := NewChannel(size)
+	tc := te.(Selectable).channel()
+	fc := fe.(Selectable).channel()
+	go func() {
+		defer te.Close()
+		defer fe.Close()
+		defer func() {
+			if rec := recover(); rec != nil {
+				r := channelResult{value: data.Nil, error: rec}
+				offer(tc, r, p)
+				offer(fc, r, p)
+			}
+		}()
+		it := data.MakeIterator(src)
+		for v, ok := it.Next(); ok; v, ok = it.Next() {
+			ch := fc
+			if pred(v) {
+				ch = tc
+			}
+			offer(ch, channelResult{value: v}, p)
+			if isDone(tc) && isDone(fc) {
+				return
+			}
+		}
+	}()
+	return te, ts, fe, fs
+}
+
+// HasTopics returns whether the Broadcast was created with a TopicFunc
+func (b *Broadcast) HasTopics() bool {
+	return b.topic != nil
+}
+
+// Subscribe returns a new Channel that receives values from the
+// Broadcast. If topic is nil, the Channel receives every value. The
+// Channel is closed when the source Sequence is
+// exhausted. Closing it will unsubscribe it
+func (b *Broadcast) Subscribe(
+	topic data.Value, size int, p BufferPolicy,
+) (Emitter, data.Sequence) {
+	e, s := NewChannel(size)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		e.Close()
+		return e, s
+	}
+	b.subs = append(b.subs, &subscriber{
+		ch:     e.(Selectable).channel(),
+		topic:  topic,
+		policy: p,
+	})
+	return e, s
+}
+
+func (b *Broadcast) run(src data.Sequence) {
+	defer b.close()
+	defer func() {
+		if rec := recover(); rec != nil {
+			for _, s := range b.subscribers() {
+				offer(s.ch, channelResult{value: data.Nil, error: rec}, s.policy)
+			}
+		}
+	}()
+	it := data.MakeIterator(src)
+	for v, ok := it.Next(); ok; v, ok = it.Next() {
+		var topic data.Value
+		if b.topic != nil {
+			topic = b.topic(v)
+		}
+		for _, s := range b.subscribers() {
+			if s.topic != nil && (topic == nil || !topic.Equal(s.topic)) {
+				continue
+			}
+			offer(s.ch, channelResult{value: v}, s.policy)
+		}
+	}
+}
+
+func (b *Broadcast) subscribers() []*subscriber {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	res := make([]*subscriber, 0, len(b.subs))
+	for _, s := range b.subs {
+		switch atomic.LoadUint32(&s.ch.status) {
+		case channelReady:
+			res = append(res, s)
+		case channelCloseRequested:
+			s.ch.Close()
+		}
+	}
+	b.subs = res
+	return res
+}
+
+func (b *Broadcast) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for _, s := range b.subs {
+		s.ch.Close()
+	}
+	b.subs = nil
+}
+
+func isDone(ch *channelWrapper) bool {
+	return atomic.LoadUint32(&ch.status) != channelReady
+}
+
+// Type returns the type name of the Broadcast
+func (b *Broadcast) Type() data.Name {
+	return data.Name(BroadcastType)
+}
+
+// Equal compares this Broadcast to another for identity
+func (b *Broadcast) Equal(v data.Value) bool {
+	if v, ok := v.(*Broadcast); ok {
+		return b == v
+	}
+	return false
+}
+
+func (b *Broadcast) String() string {
+	return data.DumpString(b)
+}
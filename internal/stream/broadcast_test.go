@@ -0,0 +1,106 @@
+package stream_test
+
+import (
+	"testing"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+	"github.com/kode4food/ale/internal/sequence"
+	"github.com/kode4food/ale/internal/stream"
+)
+
+func TestMult(t *testing.T) {
+	as := assert.New(t)
+
+	e, s := stream.NewChannel(0)
+	m := stream.NewMult(s)
+	_, s1 := m.Subscribe(nil, 3, stream.Block)
+	_, s2 := m.Subscribe(nil, 3, stream.Block)
+
+	go func() {
+		e.Write(I(1))
+		e.Write(I(2))
+		e.Close()
+	}()
+
+	as.String("[1 2]", sequence.ToVector(s2))
+	as.String("[1 2]", sequence.ToVector(s1))
+
+	_, s3 := m.Subscribe(nil, 0, stream.Block)
+	as.True(s3.IsEmpty())
+}
+
+func TestPub(t *testing.T) {
+	as := assert.New(t)
+
+	e, s := stream.NewChannel(0)
+	p := stream.NewPub(s, func(v data.Value) data.Value {
+		return data.Bool(v.(data.Integer)%2 == 0)
+	})
+	_, even := p.Subscribe(data.True, 10, stream.Block)
+	_, odd := p.Subscribe(data.False, 10, stream.Block)
+	_, all := p.Subscribe(nil, 10, stream.Block)
+
+	for i := 0; i < 5; i++ {
+		e.Write(I(int64(i)))
+	}
+	e.Close()
+
+	as.String("[0 2 4]", sequence.ToVector(even))
+	as.String("[1 3]", sequence.ToVector(odd))
+	as.String("[0 1 2 3 4]", sequence.ToVector(all))
+}
+
+func TestOfferPolicies(t *testing.T) {
+	as := assert.New(t)
+
+	e, s := stream.NewChannel(2)
+	w := e.(stream.Selectable)
+	as.True(stream.Offer(w, I(1), stream.DropNewest))
+	as.True(stream.Offer(w, I(2), stream.DropNewest))
+	as.False(stream.Offer(w, I(3), stream.DropNewest))
+	as.True(stream.Offer(w, I(4), stream.DropOldest))
+	e.Close()
+	as.String("[2 4]", sequence.ToVector(s))
+	as.False(stream.Offer(w, I(5), stream.Block))
+}
+
+func TestSlowSubscriber(t *testing.T) {
+	as := assert.New(t)
+
+	e, s := stream.NewChannel(0)
+	m := stream.NewMult(s)
+	_, slow := m.Subscribe(nil, 1, stream.DropNewest)
+	_, fast := m.Subscribe(nil, 0, stream.Block)
+
+	go func() {
+		for i := 0; i < 5; i++ {
+			e.Write(I(int64(i)))
+		}
+		e.Close()
+	}()
+
+	as.String("[0 1 2 3 4]", sequence.ToVector(fast))
+	as.String("[0]", sequence.ToVector(slow))
+}
+
+func TestMergeSplit(t *testing.T) {
+	as := assert.New(t)
+
+	_, s := stream.Merge(0,
+		data.NewVector(I(1), I(2)),
+		data.NewVector(I(3)),
+	)
+	v := sequence.ToVector(s)
+	as.Equal(3, v.Count())
+
+	_, ts, _, fs := stream.Split(
+		data.NewVector(I(1), I(2), I(3), I(4)),
+		func(v data.Value) bool {
+			return v.(data.Integer)%2 == 0
+		}, 4, stream.Block,
+	)
+	as.String("[2 4]", sequence.ToVector(ts))
+	as.String("[1 3]", sequence.ToVector(fs))
+}
@@ -2,6 +2,7 @@ package stream
 
 import (
 	"runtime"
+	"sync"
 	"sync/atomic"
 
 	"github.com/kode4food/ale/data"
@@ -23,6 +24,8 @@ type (
 
 	channelWrapper struct {
 		seq    chan channelResult
+		cancel chan struct{}
+		once   sync.Once
 		status uint32
 	}
 
@@ -59,10 +62,28 @@ const (
 
 var emptyResult = channelResult{value: data.Nil, error: nil}
 
+// Cancel is called by the reading side of a Channel to signal that no
+// more values will be consumed. Any blocked writer is released, and
+// the Channel is closed by the next write
+func (ch *channelWrapper) Cancel() {
+	ch.once.Do(func() {
+		atomic.CompareAndSwapUint32(
+			&ch.status, channelReady, channelCloseRequested,
+		)
+		close(ch.cancel)
+	})
+}
+
 func (ch *channelWrapper) Close() {
-	if atomic.LoadUint32(&ch.status) != channelClosed {
-		atomic.StoreUint32(&ch.status, channelClosed)
-		close(ch.seq)
+	for {
+		status := atomic.LoadUint32(&ch.status)
+		if status == channelClosed {
+			return
+		}
+		if atomic.CompareAndSwapUint32(&ch.status, status, channelClosed) {
+			close(ch.seq)
+			return
+		}
 	}
 }
 
@@ -71,6 +92,7 @@ func NewChannel(size int) (Emitter, data.Sequence) {
 	seq := make(chan channelResult, size)
 	ch := &channelWrapper{
 		seq:    seq,
+		cancel: make(chan struct{}),
 		status: channelReady,
 	}
 	return NewChannelEmitter(ch), NewChannelSequence(ch)
@@ -93,7 +115,10 @@ func NewChannelEmitter(ch *channelWrapper) Emitter {
 // Write will send a Value to the Go chan
 func (e *channelEmitter) Write(v data.Value) {
 	if atomic.LoadUint32(&e.ch.status) == channelReady {
-		e.ch.seq <- channelResult{v, nil}
+		select {
+		case e.ch.seq <- channelResult{v, nil}:
+		case <-e.ch.cancel:
+		}
 	}
 	if atomic.LoadUint32(&e.ch.status) == channelCloseRequested {
 		e.Close()
@@ -103,7 +128,10 @@ func (e *channelEmitter) Write(v data.Value) {
 // Error will send an Error to the Go chan
 func (e *channelEmitter) Error(err interface{}) {
 	if atomic.LoadUint32(&e.ch.status) == channelReady {
-		e.ch.seq <- channelResult{data.Nil, err}
+		select {
+		case e.ch.seq <- channelResult{data.Nil, err}:
+		case <-e.ch.cancel:
+		}
 	}
 	e.Close()
 }
@@ -138,11 +166,7 @@ func NewChannelSequence(ch *channelWrapper) data.Sequence {
 		rest:   data.EmptyList,
 	}
 	runtime.SetFinalizer(r, func(c *channelSequence) {
-		defer func() { recover() }()
-		if atomic.LoadUint32(&c.ch.status) == channelReady {
-			atomic.StoreUint32(&c.ch.status, channelCloseRequested)
-			<-ch.seq // consume whatever is there
-		}
+		c.ch.Cancel()
 	})
 	return r
 }
@@ -180,9 +204,21 @@ func (c *channelSequence) Split() (data.Value, data.Sequence, bool) {
 	return r.result.value, r.rest, r.ok
 }
 
+// Cancel signals to the writer that the Sequence will no longer be
+// consumed. Its next write will close the Channel
+func (c *channelSequence) Cancel() {
+	c.ch.Cancel()
+}
+
+// IsCancelled returns whether the Channel will accept more values
+func (c *channelSequence) IsCancelled() bool {
+	return atomic.LoadUint32(&c.ch.status) != channelReady
+}
+
 func (c *channelSequence) Prepend(v data.Value) data.Sequence {
 	return &channelSequence{
 		once:   do.Never(),
+		ch:     c.ch,
 		ok:     true,
 		result: channelResult{value: v, error: nil},
 		rest:   c,
@@ -56,3 +56,19 @@ func TestChannel(t *testing.T) {
 	go check()
 	wg.Wait()
 }
+
+func TestChannelConcurrentClose(t *testing.T) {
+	as := assert.New(t)
+
+	e, seq := stream.NewChannel(0)
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e.Close()
+		}()
+	}
+	wg.Wait()
+	as.True(seq.IsEmpty())
+}
@@ -0,0 +1,38 @@
+package stream
+
+import "github.com/kode4food/ale/data"
+
+// EmitFunc is called by a generator's producer to emit a value. It
+// raises a cancellation error once the Sequence has been cancelled
+type EmitFunc func(data.Value)
+
+// Generate runs a producer asynchronously, returning the Sequence of
+// the values it emits. When the Sequence is cancelled or abandoned, or
+// when the optional parent Scope is cancelled, the producer's next
+// emit raises a cancellation error so that it can unwind. If the
+// producer fails for any other reason, the Sequence raises its error
+func Generate(parent *Scope, producer func(EmitFunc)) data.Sequence {
+	e, s := NewChannel(0)
+	ch := e.(*channelEmitter).ch
+	detach := func() {}
+	if parent != nil {
+		detach = parent.OnCancel(ch.Cancel)
+	}
+
+	go func() {
+		defer detach()
+		defer func() {
+			if rec := recover(); rec != nil && !IsCancelledError(rec) {
+				e.Error(rec)
+				return
+			}
+			e.Close()
+		}()
+		producer(func(v data.Value) {
+			if !offer(ch, channelResult{value: v}, Block) {
+				panic(NewCancelledError())
+			}
+		})
+	}()
+	return s
+}
@@ -0,0 +1,65 @@
+package stream
+
+import "github.com/kode4food/ale/data"
+
+// Inbox is a buffered Channel that is never closed. Its reads can be
+// interrupted, and writes to it can take part in a Select
+type Inbox struct {
+	ch *channelWrapper
+}
+
+// NewInbox returns a new Inbox with the specified buffer size
+func NewInbox(size int) *Inbox {
+	return &Inbox{
+		ch: &channelWrapper{
+			seq:    make(chan channelResult, size),
+			cancel: make(chan struct{}),
+			status: channelReady,
+		},
+	}
+}
+
+// Send writes a Value to the Inbox, blocking until there's room for it
+// or until either of the done channels is closed. Returns whether it
+// was written
+func (i *Inbox) Send(v data.Value, interrupt, done <-chan struct{}) bool {
+	select {
+	case i.ch.seq <- channelResult{value: v}:
+		return true
+	case <-interrupt:
+		return false
+	case <-done:
+		return false
+	}
+}
+
+// TrySend writes a Value to the Inbox if there's room for it, without
+// blocking. Returns whether it was written
+func (i *Inbox) TrySend(v data.Value) bool {
+	select {
+	case i.ch.seq <- channelResult{value: v}:
+		return true
+	default:
+		return false
+	}
+}
+
+// Receive reads a Value from the Inbox, blocking until one is available
+// or until any of the interrupt channels are ready. Returns false if
+// it was interrupted
+func (i *Inbox) Receive(
+	interrupt, done <-chan struct{},
+) (data.Value, bool) {
+	select {
+	case r := <-i.ch.seq:
+		return r.value, true
+	case <-interrupt:
+		return nil, false
+	case <-done:
+		return nil, false
+	}
+}
+
+func (i *Inbox) channel() *channelWrapper {
+	return i.ch
+}
@@ -0,0 +1,39 @@
+package stream_test
+
+import (
+	"testing"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+	"github.com/kode4food/ale/internal/stream"
+)
+
+func TestInbox(t *testing.T) {
+	as := assert.New(t)
+
+	i := stream.NewInbox(1)
+	done := make(chan struct{})
+	as.True(i.Send(I(1), nil, done))
+	as.False(i.TrySend(I(9)))
+	v, ok := i.Receive(nil, done)
+	as.True(ok)
+	as.Equal(I(1), v)
+
+	idx, res := stream.Select([]stream.SelectCase{
+		{Channel: i, Value: I(2), Write: true},
+	}, false)
+	as.Equal(0, idx)
+	as.Equal(data.True, res)
+
+	close(done)
+	as.False(i.Send(I(3), nil, done))
+	v, ok = i.Receive(nil, nil)
+	as.True(ok)
+	as.Equal(I(2), v)
+
+	interrupt := make(chan struct{})
+	close(interrupt)
+	_, ok = i.Receive(interrupt, nil)
+	as.False(ok)
+}
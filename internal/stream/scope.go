@@ -0,0 +1,156 @@
+package stream
+
+import (
+	"sync"
+
+	"github.com/kode4food/ale/data"
+)
+
+type (
+	// Canceller is implemented by values that can be cancelled
+	Canceller interface {
+		Cancel()
+		IsCancelled() bool
+	}
+
+	// Scope is a cancellation signal that can be shared by a group of
+	// tasks. Cancelling a Scope also cancels all of its children. A
+	// Scope can be read from in a Select, completing when cancelled
+	Scope struct {
+		mu        sync.Mutex
+		ch        *channelWrapper
+		children  map[int]func()
+		nextChild int
+		detach    func()
+		cancelled bool
+	}
+)
+
+const (
+	// ScopeType is the type name for a Scope
+	ScopeType = data.String("scope")
+
+	// Cancelled is the type of error raised when a task is cancelled
+	Cancelled = data.Keyword("cancelled")
+
+	// ErrCancelled is the message of the error raised when a task is
+	// cancelled
+	ErrCancelled = "task was cancelled"
+)
+
+// NewScope returns a new Scope. If a parent is provided, the Scope will
+// be cancelled along with it
+func NewScope(parent *Scope) *Scope {
+	s := &Scope{
+		ch: &channelWrapper{
+			seq:    make(chan channelResult),
+			cancel: make(chan struct{}),
+			status: channelReady,
+		},
+		children: map[int]func(){},
+	}
+	if parent != nil {
+		s.detach = parent.OnCancel(s.Cancel)
+	}
+	return s
+}
+
+// NewCancelledError returns the error that is raised when a task is
+// cancelled
+func NewCancelledError() data.Error {
+	return data.NewError(Cancelled, ErrCancelled)
+}
+
+// IsCancelledError returns whether a value recovered from a panic is
+// the result of a task being cancelled
+func IsCancelledError(rec interface{}) bool {
+	if err, ok := rec.(data.Error); ok {
+		t, _ := err.Get(data.TypeKey)
+		return t == Cancelled
+	}
+	return false
+}
+
+// OnCancel registers a function to be called when the Scope is
+// cancelled. If it already has been, the function is called right
+// away. The returned function unregisters it
+func (s *Scope) OnCancel(fn func()) func() {
+	s.mu.Lock()
+	if s.cancelled {
+		s.mu.Unlock()
+		fn()
+		return func() {}
+	}
+	id := s.nextChild
+	s.nextChild++
+	s.children[id] = fn
+	s.mu.Unlock()
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.children, id)
+	}
+}
+
+// Cancel cancels the Scope and all of its children
+func (s *Scope) Cancel() {
+	s.mu.Lock()
+	if s.cancelled {
+		s.mu.Unlock()
+		return
+	}
+	s.cancelled = true
+	children := s.children
+	s.children = nil
+	s.mu.Unlock()
+
+	if s.detach != nil {
+		s.detach()
+	}
+	s.ch.Cancel()
+	s.ch.Close()
+	for _, fn := range children {
+		fn()
+	}
+}
+
+// IsCancelled returns whether the Scope has been cancelled
+func (s *Scope) IsCancelled() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cancelled
+}
+
+// Done returns a channel that is closed when the Scope is cancelled
+func (s *Scope) Done() <-chan struct{} {
+	return s.ch.cancel
+}
+
+// Check raises a cancellation error if the Scope has been cancelled
+func (s *Scope) Check() {
+	if s.IsCancelled() {
+		panic(NewCancelledError())
+	}
+}
+
+func (s *Scope) channel() *channelWrapper {
+	return s.ch
+}
+
+// Type returns the type name of the Scope
+func (s *Scope) Type() data.Name {
+	return data.Name(ScopeType)
+}
+
+// Equal compares this Scope to another for identity
+func (s *Scope) Equal(v data.Value) bool {
+	if v, ok := v.(*Scope); ok {
+		return s == v
+	}
+	return false
+}
+
+func (s *Scope) String() string {
+	return data.DumpString(s)
+}
@@ -0,0 +1,106 @@
+package stream_test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+	"github.com/kode4food/ale/internal/stream"
+)
+
+func TestScope(t *testing.T) {
+	as := assert.New(t)
+
+	parent := stream.NewScope(nil)
+	child := stream.NewScope(parent)
+	other := stream.NewScope(nil)
+	as.False(child.IsCancelled())
+	as.Contains(":type scope", child)
+
+	parent.Cancel()
+	as.True(parent.IsCancelled())
+	as.True(child.IsCancelled())
+	as.False(other.IsCancelled())
+	as.True(stream.NewScope(parent).IsCancelled())
+
+	idx, _ := stream.Select([]stream.SelectCase{
+		{Channel: child},
+	}, false)
+	as.Equal(0, idx)
+
+	defer func() {
+		rec := recover()
+		as.True(stream.IsCancelledError(rec))
+		as.False(stream.IsCancelledError(S("blah")))
+	}()
+	child.Check()
+}
+
+func counter(unwound chan bool) func(stream.EmitFunc) {
+	return func(emit stream.EmitFunc) {
+		defer func() { unwound <- true }()
+		for i := 0; ; i++ {
+			emit(I(int64(i)))
+		}
+	}
+}
+
+func TestGenerateCancel(t *testing.T) {
+	as := assert.New(t)
+
+	unwound := make(chan bool, 1)
+	s := stream.Generate(nil, counter(unwound))
+	f1, r, _ := s.Split()
+	f2, r, _ := r.Split()
+	as.Number(0, f1)
+	as.Number(1, f2)
+	r.(stream.Canceller).Cancel()
+	as.True(r.(stream.Canceller).IsCancelled())
+	as.True(<-unwound)
+}
+
+func TestGenerateScope(t *testing.T) {
+	as := assert.New(t)
+
+	unwound := make(chan bool, 1)
+	scope := stream.NewScope(nil)
+	s := stream.Generate(scope, counter(unwound))
+	as.Number(0, s.First())
+	scope.Cancel()
+	as.True(<-unwound)
+}
+
+func TestGenerateAbandoned(t *testing.T) {
+	as := assert.New(t)
+
+	unwound := make(chan bool, 1)
+	func() {
+		s := stream.Generate(nil, counter(unwound))
+		as.Number(0, s.First())
+	}()
+
+	for i := 0; i < 10; i++ {
+		runtime.GC()
+		select {
+		case <-unwound:
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	as.Fail("producer was not unwound")
+}
+
+func TestGenerateError(t *testing.T) {
+	as := assert.New(t)
+
+	s := stream.Generate(nil, func(emit stream.EmitFunc) {
+		emit(I(1))
+		panic(data.NewError("boom", "exploded"))
+	})
+	as.Number(1, s.First())
+	defer as.ExpectPanic("exploded")
+	s.Rest().First()
+}
@@ -0,0 +1,110 @@
+package stream
+
+import (
+	"reflect"
+	"sync/atomic"
+
+	"github.com/kode4food/ale/data"
+)
+
+type (
+	// Selectable is implemented by either end of a Channel, allowing
+	// them to take part in a Select
+	Selectable interface {
+		channel() *channelWrapper
+	}
+
+	// SelectCase is either a read from, or a write to, a Channel
+	SelectCase struct {
+		Channel Selectable
+		Value   data.Value
+		Write   bool
+	}
+)
+
+// Select blocks until one of the cases can proceed and returns the
+// index of that case. For a read, the value read is returned, or Nil
+// if the Channel was closed. For a write, True is returned if the value
+// was sent, and False if the Channel was closed. If block is false and
+// no case can proceed immediately, an index of -1 is returned
+func Select(cases []SelectCase, block bool) (int, data.Value) {
+	sc := make([]reflect.SelectCase, len(cases), len(cases)+1)
+	for i, c := range cases {
+		ch := c.Channel.channel()
+		if !c.Write {
+			sc[i] = reflect.SelectCase{
+				Dir:  reflect.SelectRecv,
+				Chan: reflect.ValueOf(ch.seq),
+			}
+			continue
+		}
+		if atomic.LoadUint32(&ch.status) != channelReady {
+			return i, data.False
+		}
+		sc[i] = reflect.SelectCase{
+			Dir:  reflect.SelectSend,
+			Chan: reflect.ValueOf(ch.seq),
+			Send: reflect.ValueOf(channelResult{value: c.Value}),
+		}
+	}
+	if !block {
+		sc = append(sc, reflect.SelectCase{Dir: reflect.SelectDefault})
+	}
+
+	idx, v, ok, closed := selectCase(cases, sc)
+	if closed {
+		return idx, data.False
+	}
+	if idx == len(cases) {
+		return -1, data.Nil
+	}
+	c := cases[idx]
+	if c.Write {
+		ch := c.Channel.channel()
+		if atomic.LoadUint32(&ch.status) == channelCloseRequested {
+			ch.Close()
+		}
+		return idx, data.True
+	}
+	if !ok {
+		return idx, data.Nil
+	}
+	r := v.Interface().(channelResult)
+	if r.error != nil {
+		panic(r.error)
+	}
+	return idx, r.value
+}
+
+// selectCase performs the select. If it panics because a write raced
+// with the closing of its Channel, the index of that write is returned
+// as closed. Any other panic is raised again
+func selectCase(
+	cases []SelectCase, sc []reflect.SelectCase,
+) (idx int, v reflect.Value, ok bool, closed bool) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			idx, closed = closedWrite(cases, rec), true
+		}
+	}()
+	idx, v, ok = reflect.Select(sc)
+	return
+}
+
+func closedWrite(cases []SelectCase, rec interface{}) int {
+	for i, c := range cases {
+		ch := c.Channel.channel()
+		if c.Write && atomic.LoadUint32(&ch.status) == channelClosed {
+			return i
+		}
+	}
+	panic(rec)
+}
+
+func (e *channelEmitter) channel() *channelWrapper {
+	return e.ch
+}
+
+func (c *channelSequence) channel() *channelWrapper {
+	return c.ch
+}
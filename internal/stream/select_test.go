@@ -0,0 +1,75 @@
+package stream_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+	"github.com/kode4food/ale/internal/stream"
+)
+
+func TestSelectRead(t *testing.T) {
+	as := assert.New(t)
+
+	e1, s1 := stream.NewChannel(0)
+	_, s2 := stream.NewChannel(0)
+	go e1.Write(S("hello"))
+
+	idx, res := stream.Select([]stream.SelectCase{
+		{Channel: s2.(stream.Selectable)},
+		{Channel: s1.(stream.Selectable)},
+	}, true)
+	as.Equal(1, idx)
+	as.String("hello", res)
+
+	e1.Close()
+	idx, res = stream.Select([]stream.SelectCase{
+		{Channel: s1.(stream.Selectable)},
+	}, true)
+	as.Equal(0, idx)
+	as.Equal(data.Nil, res)
+}
+
+func TestSelectReadError(t *testing.T) {
+	as := assert.New(t)
+
+	e, s := stream.NewChannel(1)
+	e.Error(errors.New("boom"))
+
+	defer as.ExpectPanic("boom")
+	stream.Select([]stream.SelectCase{
+		{Channel: s.(stream.Selectable)},
+	}, true)
+}
+
+func TestSelectWrite(t *testing.T) {
+	as := assert.New(t)
+
+	e, s := stream.NewChannel(1)
+	idx, res := stream.Select([]stream.SelectCase{
+		{Channel: e.(stream.Selectable), Value: I(42), Write: true},
+	}, true)
+	as.Equal(0, idx)
+	as.Equal(data.True, res)
+	as.Number(42, s.First())
+
+	e.Close()
+	idx, res = stream.Select([]stream.SelectCase{
+		{Channel: e.(stream.Selectable), Value: I(43), Write: true},
+	}, true)
+	as.Equal(0, idx)
+	as.Equal(data.False, res)
+}
+
+func TestSelectDefault(t *testing.T) {
+	as := assert.New(t)
+
+	_, s := stream.NewChannel(0)
+	idx, res := stream.Select([]stream.SelectCase{
+		{Channel: s.(stream.Selectable)},
+	}, false)
+	as.Equal(-1, idx)
+	as.Equal(data.Nil, res)
+}
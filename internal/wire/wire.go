@@ -0,0 +1,343 @@
+// Package wire implements a binary encoding of Ale's data Values, so
+// that they can be sent between processes. Values that the encoding
+// doesn't support directly can be carried as extensions
+package wire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/kode4food/ale/data"
+)
+
+type (
+	// ExternalFunc converts a Value that the encoding doesn't support
+	// into a named extension with a payload that it does. Returns false
+	// if the Value can't be converted
+	ExternalFunc func(data.Value) (data.Keyword, data.Value, bool)
+
+	// InternalFunc converts a named extension's payload back into the
+	// Value that it represents
+	InternalFunc func(data.Keyword, data.Value) (data.Value, error)
+
+	tag byte
+
+	encoder struct {
+		bytes.Buffer
+		external ExternalFunc
+	}
+
+	decoder struct {
+		*bytes.Reader
+		internal InternalFunc
+		depth    int
+	}
+)
+
+const (
+	tagNil tag = iota
+	tagTrue
+	tagFalse
+	tagInteger
+	tagBigInt
+	tagFloat
+	tagRatio
+	tagString
+	tagKeyword
+	tagLocalSymbol
+	tagQualifiedSymbol
+	tagList
+	tagVector
+	tagObject
+	tagError
+	tagCons
+	tagExtension
+)
+
+// Error messages
+const (
+	ErrUnsupportedValue = "value can't be encoded: %s"
+	ErrUnknownExtension = "unknown extension: %s"
+	ErrMalformedValue   = "malformed encoded value"
+)
+
+// MaxDepth is the deepest that encoded Values can be nested. Anything
+// nested more deeply is rejected as malformed when decoded
+const MaxDepth = 1024
+
+// Marshal encodes a Value
+func Marshal(v data.Value) ([]byte, error) {
+	return MarshalWith(v, nil)
+}
+
+// MarshalWith encodes a Value, converting any that the encoding doesn't
+// support using the provided ExternalFunc
+func MarshalWith(v data.Value, ext ExternalFunc) (res []byte, err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = rec.(error)
+		}
+	}()
+	e := &encoder{external: ext}
+	e.value(v)
+	return e.Bytes(), nil
+}
+
+// Unmarshal decodes a Value
+func Unmarshal(b []byte) (data.Value, error) {
+	return UnmarshalWith(b, nil)
+}
+
+// UnmarshalWith decodes a Value, converting its extensions using the
+// provided InternalFunc
+func UnmarshalWith(b []byte, in InternalFunc) (res data.Value, err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = rec.(error)
+		}
+	}()
+	d := &decoder{
+		Reader:   bytes.NewReader(b),
+		internal: in,
+	}
+	res = d.value()
+	if d.Len() != 0 {
+		return nil, errors.New(ErrMalformedValue)
+	}
+	return res, nil
+}
+
+func (e *encoder) value(v data.Value) {
+	switch v := v.(type) {
+	case data.Null:
+		e.tag(tagNil)
+	case data.Bool:
+		if v {
+			e.tag(tagTrue)
+		} else {
+			e.tag(tagFalse)
+		}
+	case data.Integer:
+		e.tag(tagInteger)
+		e.varint(int64(v))
+	case *data.BigInt:
+		e.tag(tagBigInt)
+		e.string((*big.Int)(v).String())
+	case data.Float:
+		e.tag(tagFloat)
+		e.uvarint(math.Float64bits(float64(v)))
+	case *data.Ratio:
+		e.tag(tagRatio)
+		e.string((*big.Rat)(v).String())
+	case data.String:
+		e.tag(tagString)
+		e.string(string(v))
+	case data.Keyword:
+		e.tag(tagKeyword)
+		e.string(string(v))
+	case data.LocalSymbol:
+		e.tag(tagLocalSymbol)
+		e.string(string(v.Name()))
+	case data.QualifiedSymbol:
+		e.tag(tagQualifiedSymbol)
+		e.string(string(v.Domain()))
+		e.string(string(v.Name()))
+	case data.List:
+		e.tag(tagList)
+		e.sequence(v, v.Count())
+	case data.Vector:
+		e.tag(tagVector)
+		e.sequence(v, v.Count())
+	case data.Error:
+		e.tag(tagError)
+		e.object(v)
+	case data.Object:
+		e.tag(tagObject)
+		e.object(v)
+	case data.Cons:
+		e.tag(tagCons)
+		e.value(v.Car())
+		e.value(v.Cdr())
+	default:
+		e.extension(v)
+	}
+}
+
+func (e *encoder) extension(v data.Value) {
+	if e.external != nil {
+		if name, payload, ok := e.external(v); ok {
+			e.tag(tagExtension)
+			e.string(string(name))
+			e.value(payload)
+			return
+		}
+	}
+	panic(fmt.Errorf(ErrUnsupportedValue, v))
+}
+
+func (e *encoder) sequence(s data.Sequence, count int) {
+	e.uvarint(uint64(count))
+	for f, r, ok := s.Split(); ok; f, r, ok = r.Split() {
+		e.value(f)
+	}
+}
+
+func (e *encoder) object(o data.Object) {
+	e.uvarint(uint64(o.Count()))
+	for f, r, ok := o.Split(); ok; f, r, ok = r.Split() {
+		p := f.(data.Pair)
+		e.value(p.Car())
+		e.value(p.Cdr())
+	}
+}
+
+func (e *encoder) tag(t tag) {
+	e.WriteByte(byte(t))
+}
+
+func (e *encoder) varint(i int64) {
+	var buf [binary.MaxVarintLen64]byte
+	e.Write(buf[:binary.PutVarint(buf[:], i)])
+}
+
+func (e *encoder) uvarint(i uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	e.Write(buf[:binary.PutUvarint(buf[:], i)])
+}
+
+func (e *encoder) string(s string) {
+	e.uvarint(uint64(len(s)))
+	e.WriteString(s)
+}
+
+func (d *decoder) value() data.Value {
+	if d.depth++; d.depth > MaxDepth {
+		panic(errors.New(ErrMalformedValue))
+	}
+	defer func() { d.depth-- }()
+
+	switch tag(d.byte()) {
+	case tagNil:
+		return data.Nil
+	case tagTrue:
+		return data.True
+	case tagFalse:
+		return data.False
+	case tagInteger:
+		return data.Integer(d.varint())
+	case tagBigInt:
+		return d.number(data.ParseInteger)
+	case tagFloat:
+		return data.Float(math.Float64frombits(d.uvarint()))
+	case tagRatio:
+		return d.number(data.ParseRatio)
+	case tagString:
+		return data.String(d.string())
+	case tagKeyword:
+		return data.Keyword(d.string())
+	case tagLocalSymbol:
+		return data.NewLocalSymbol(data.Name(d.string()))
+	case tagQualifiedSymbol:
+		domain := data.Name(d.string())
+		return data.NewQualifiedSymbol(data.Name(d.string()), domain)
+	case tagList:
+		return data.NewList(d.values()...)
+	case tagVector:
+		return data.NewVector(d.values()...)
+	case tagObject:
+		return data.NewObject(d.pairs()...)
+	case tagError:
+		return data.MakeError(data.NewObject(d.pairs()...))
+	case tagCons:
+		car := d.value()
+		return data.NewCons(car, d.value())
+	case tagExtension:
+		return d.extension()
+	default:
+		panic(errors.New(ErrMalformedValue))
+	}
+}
+
+func (d *decoder) extension() data.Value {
+	name := data.Keyword(d.string())
+	payload := d.value()
+	if d.internal == nil {
+		panic(fmt.Errorf(ErrUnknownExtension, name))
+	}
+	res, err := d.internal(name, payload)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+func (d *decoder) number(parse func(string) (data.Number, error)) data.Value {
+	res, err := parse(d.string())
+	if err != nil {
+		panic(errors.New(ErrMalformedValue))
+	}
+	return res
+}
+
+func (d *decoder) values() data.Values {
+	res := make(data.Values, d.count())
+	for i := range res {
+		res[i] = d.value()
+	}
+	return res
+}
+
+func (d *decoder) pairs() data.Pairs {
+	res := make(data.Pairs, d.count())
+	for i := range res {
+		k := d.value()
+		res[i] = data.NewCons(k, d.value())
+	}
+	return res
+}
+
+func (d *decoder) count() int {
+	n := d.uvarint()
+	if n > uint64(d.Len()) {
+		// every element requires at least one byte
+		panic(errors.New(ErrMalformedValue))
+	}
+	return int(n)
+}
+
+func (d *decoder) byte() byte {
+	b, err := d.ReadByte()
+	if err != nil {
+		panic(errors.New(ErrMalformedValue))
+	}
+	return b
+}
+
+func (d *decoder) varint() int64 {
+	res, err := binary.ReadVarint(d)
+	if err != nil {
+		panic(errors.New(ErrMalformedValue))
+	}
+	return res
+}
+
+func (d *decoder) uvarint() uint64 {
+	res, err := binary.ReadUvarint(d)
+	if err != nil {
+		panic(errors.New(ErrMalformedValue))
+	}
+	return res
+}
+
+func (d *decoder) string() string {
+	n := d.count()
+	buf := make([]byte, n)
+	if _, err := d.Read(buf); err != nil && n > 0 {
+		panic(errors.New(ErrMalformedValue))
+	}
+	return string(buf)
+}
@@ -0,0 +1,116 @@
+package wire_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+	"github.com/kode4food/ale/internal/wire"
+	"github.com/kode4food/ale/read"
+)
+
+func roundTrip(as *assert.Wrapper, v data.Value) data.Value {
+	b, err := wire.Marshal(v)
+	as.Nil(err)
+	res, err := wire.Unmarshal(b)
+	as.Nil(err)
+	return res
+}
+
+func TestRoundTrip(t *testing.T) {
+	as := assert.New(t)
+
+	src := `
+		[nil #t #f 42 -7 99999999999999999999999 3.5 1/3 "hello"
+		 :kw sym ns/sym '(1 2 3) [] {:a 1 "b" [2]}]
+	`
+	v, _ := data.Last(read.FromString(data.String(src)))
+	res := roundTrip(as, v)
+	as.True(v.Equal(res))
+	as.String(v.String(), res)
+
+	c := data.NewCons(K("a"), I(1))
+	as.True(c.Equal(roundTrip(as, c)))
+
+	e := data.NewError(K("oops"), "it broke")
+	res = roundTrip(as, e)
+	_, ok := res.(data.Error)
+	as.True(ok)
+	as.EqualError(res.(data.Error), "it broke")
+}
+
+type opaque struct{ data.Value }
+
+func TestExtensions(t *testing.T) {
+	as := assert.New(t)
+
+	fn := data.Applicative(func(...data.Value) data.Value {
+		return data.Nil
+	})
+	_, err := wire.Marshal(V(I(1), fn))
+	as.EqualError(err, fmt.Sprintf(wire.ErrUnsupportedValue, fn))
+
+	ext := func(v data.Value) (data.Keyword, data.Value, bool) {
+		if o, ok := v.(*opaque); ok {
+			return K("opaque"), o.Value, true
+		}
+		return "", nil, false
+	}
+	b, err := wire.MarshalWith(V(&opaque{S("inner")}), ext)
+	as.Nil(err)
+
+	_, err = wire.Unmarshal(b)
+	as.EqualError(err, fmt.Sprintf(wire.ErrUnknownExtension, ":opaque"))
+
+	res, err := wire.UnmarshalWith(b, func(
+		name data.Keyword, payload data.Value,
+	) (data.Value, error) {
+		as.Equal(K("opaque"), name)
+		return V(K("decoded"), payload), nil
+	})
+	as.Nil(err)
+	as.String(`[[:decoded "inner"]]`, res)
+
+	_, err = wire.UnmarshalWith(b, func(
+		data.Keyword, data.Value,
+	) (data.Value, error) {
+		return nil, errors.New("refused")
+	})
+	as.EqualError(err, "refused")
+}
+
+func TestMalformed(t *testing.T) {
+	as := assert.New(t)
+
+	b, _ := wire.Marshal(V(S("hello"), I(1)))
+	for i := 0; i < len(b); i++ {
+		_, err := wire.Unmarshal(b[:i])
+		as.EqualError(err, wire.ErrMalformedValue)
+	}
+	_, err := wire.Unmarshal(append(b, 0))
+	as.EqualError(err, wire.ErrMalformedValue)
+	_, err = wire.Unmarshal([]byte{0xff})
+	as.EqualError(err, wire.ErrMalformedValue)
+}
+
+func nested(depth int) []byte {
+	var res []byte
+	for i := 1; i < depth; i++ {
+		res = append(res, 11, 1) // a list of one element
+	}
+	return append(res, 0)
+}
+
+func TestNestingDepth(t *testing.T) {
+	as := assert.New(t)
+
+	_, err := wire.Unmarshal(nested(wire.MaxDepth))
+	as.Nil(err)
+	_, err = wire.Unmarshal(nested(wire.MaxDepth + 1))
+	as.EqualError(err, wire.ErrMalformedValue)
+	_, err = wire.Unmarshal(nested(8 << 20))
+	as.EqualError(err, wire.ErrMalformedValue)
+}
@@ -0,0 +1,155 @@
+// Package bytecode implements a versioned binary format for compiled
+// lambdas, so that source can be compiled ahead of time and then loaded
+// without having to be read or compiled again
+package bytecode
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/env"
+	"github.com/kode4food/ale/internal/wire"
+	"github.com/kode4food/ale/runtime/vm"
+)
+
+// Module is the compiled form of a source's top-level forms. Each form
+// is compiled into a Lambda that's run in turn
+type Module []*vm.Lambda
+
+// Version is the version of the format that Marshal produces, and the
+// only one that Unmarshal accepts
+const Version = 1
+
+// Error messages
+const (
+	ErrNotCompiled        = "data is not a compiled module"
+	ErrUnsupportedVersion = "unsupported bytecode version: %d"
+	ErrUnexpectedValue    = "unexpected value in compiled module: %s"
+)
+
+const (
+	lambdaExtension  = data.Keyword("lambda")
+	closureExtension = data.Keyword("closure")
+	nameExtension    = data.Keyword("name")
+	globalExtension  = data.Keyword("global")
+)
+
+var magic = []byte("ale\x00")
+
+// Run runs the Module's top-level forms in order, returning the value of
+// the last one. The globals that each form refers to are resolved in its
+// namespace right before it's run
+func (m Module) Run() data.Value {
+	var res data.Value = data.Nil
+	l := linker{}
+	for _, fn := range m {
+		l.link(fn)
+		res = fn.Call().(data.Function).Call()
+	}
+	return res
+}
+
+// Link resolves the globals that the Module's forms refer to. Run does
+// this for each form right before running it, so Link is only needed to
+// inspect a Module whose forms were already evaluated, like one that
+// Compile returned
+func (m Module) Link() {
+	l := linker{}
+	for _, fn := range m {
+		l.link(fn)
+	}
+}
+
+// IsCompiled returns whether the provided bytes start like a Module
+// that was produced by Marshal
+func IsCompiled(b []byte) bool {
+	return bytes.HasPrefix(b, magic)
+}
+
+// Marshal encodes a Module, including any lambdas that are nested in the
+// constants of its forms
+func Marshal(m Module) ([]byte, error) {
+	forms := make(data.Values, len(m))
+	for i, l := range m {
+		forms[i] = l
+	}
+	body, err := wire.MarshalWith(data.NewVector(forms...), external)
+	if err != nil {
+		return nil, err
+	}
+	res := append([]byte{}, magic...)
+	res = append(res, Version)
+	return append(res, body...), nil
+}
+
+// Unmarshal decodes a Module whose global references will be resolved
+// in the provided namespace. Every lambda is verified as it's decoded,
+// so that corrupt data is rejected before any of it can be run
+func Unmarshal(ns env.Namespace, b []byte) (Module, error) {
+	if !IsCompiled(b) || len(b) == len(magic) {
+		return nil, errors.New(ErrNotCompiled)
+	}
+	if v := b[len(magic)]; v != Version {
+		return nil, fmt.Errorf(ErrUnsupportedVersion, v)
+	}
+	d := &decoder{
+		globals:  ns,
+		captures: map[*vm.Lambda]int{},
+	}
+	body, err := wire.UnmarshalWith(b[len(magic)+1:], d.internal)
+	if err != nil {
+		return nil, err
+	}
+	forms, ok := body.(data.Vector)
+	if !ok {
+		return nil, fmt.Errorf(ErrUnexpectedValue, body)
+	}
+	vals := forms.Values()
+	res := make(Module, len(vals))
+	for i, f := range vals {
+		l, ok := f.(*vm.Lambda)
+		if !ok {
+			return nil, fmt.Errorf(ErrUnexpectedValue, f)
+		}
+		if err := d.checkCaptures(l, 0); err != nil {
+			return nil, err
+		}
+		res[i] = l
+	}
+	return res, nil
+}
+
+func external(v data.Value) (data.Keyword, data.Value, bool) {
+	switch v := v.(type) {
+	case *vm.Lambda:
+		return lambdaExtension, encodeLambda(v), true
+	case data.Name:
+		return nameExtension, data.String(v), true
+	case *global:
+		return globalExtension, v.symbol, true
+	}
+	if l, ok := vm.ClosureLambda(v); ok {
+		return closureExtension, l, true
+	}
+	return "", nil, false
+}
+
+func encodeLambda(l *vm.Lambda) data.Value {
+	arity := make(data.Values, len(l.Arity))
+	for i, a := range l.Arity {
+		arity[i] = data.Integer(a)
+	}
+	code := make(data.Values, len(l.Code))
+	for i, w := range l.Code {
+		code[i] = data.Integer(w)
+	}
+	return data.NewVector(
+		data.Integer(l.StackSize),
+		data.Integer(l.LocalCount),
+		data.NewVector(arity...),
+		data.NewVector(code...),
+		data.NewVector(l.Constants...),
+	)
+}
@@ -0,0 +1,249 @@
+package bytecode_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/kode4food/ale/core/bootstrap"
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/env"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+	"github.com/kode4food/ale/internal/wire"
+	"github.com/kode4food/ale/runtime/bytecode"
+	"github.com/kode4food/ale/runtime/isa"
+	"github.com/kode4food/ale/runtime/vm"
+)
+
+func newNamespace() env.Namespace {
+	e := env.NewEnvironment()
+	bootstrap.Into(e)
+	return e.GetAnonymous()
+}
+
+func compileAndLoad(as *assert.Wrapper, src data.String) bytecode.Module {
+	b, err := bytecode.Marshal(bytecode.Compile(newNamespace(), src))
+	as.Nil(err)
+	as.True(bytecode.IsCompiled(b))
+	m, err := bytecode.Unmarshal(newNamespace(), b)
+	as.Nil(err)
+	return m
+}
+
+func TestRoundTrip(t *testing.T) {
+	as := assert.New(t)
+
+	m := compileAndLoad(as, `
+		(define (double x) (* x 2))
+		(define-macro (twice form) (list 'double form))
+		(define values
+		  [nil true 1.5 99999999999999999999 1/3 "str" :kwd 'sym
+		   'ale/qualified '(1 2) {:a 1} (cons 1 2)])
+		(define counter
+		  (lambda-rec count (n)
+		    (if (> n 0) (count (dec n)) :done)))
+		(let [adder (lambda (x) (lambda (y) (+ x y)))]
+		  [(twice 21) ((adder 1) 2) (counter 100) (length values)])
+	`)
+	as.Equal(5, len(m))
+	as.String("[42 3 :done 12]", m.Run())
+}
+
+func TestGlobalsResolvedAtLoad(t *testing.T) {
+	as := assert.New(t)
+
+	m := bytecode.Compile(newNamespace(), `
+		(declare name)
+		(define (greeting) (str "hello, " name))
+	`)
+	b, err := bytecode.Marshal(m)
+	as.Nil(err)
+
+	ns := newNamespace()
+	ns.Declare("name").Bind(S("ale"))
+	m, err = bytecode.Unmarshal(ns, b)
+	as.Nil(err)
+	m.Run()
+	fn, ok := env.ResolveValue(ns, data.NewLocalSymbol("greeting"))
+	as.True(ok)
+	as.String("hello, ale", fn.(data.Function).Call())
+}
+
+func TestLink(t *testing.T) {
+	as := assert.New(t)
+
+	ns := newNamespace()
+	m := bytecode.Compile(ns, "(inc 1)")
+	inc := env.MustResolveValue(ns, data.NewLocalSymbol("inc"))
+	m.Link()
+	linked := false
+	for _, c := range m[0].Constants {
+		linked = linked || c == inc
+	}
+	as.True(linked)
+	as.String("2", m.Run())
+}
+
+func TestArityPreserved(t *testing.T) {
+	as := assert.New(t)
+
+	m := compileAndLoad(as, `(lambda [(x) x] [(x y . z) y])`)
+	fn := m.Run().(data.Function)
+	as.Nil(fn.CheckArity(1))
+	as.Nil(fn.CheckArity(5))
+	as.EqualError(fn.CheckArity(0), "expected at least 1 arguments, got 0")
+}
+
+func TestUnsupportedValue(t *testing.T) {
+	as := assert.New(t)
+
+	m := bytecode.Compile(newNamespace(), `
+		(define-macro (embed) (list (let [x 1] (lambda () x))))
+		(embed)
+	`)
+	_, err := bytecode.Marshal(m)
+	as.NotNil(err)
+	as.Contains("value can't be encoded", S(err.Error()))
+}
+
+func TestBadHeader(t *testing.T) {
+	as := assert.New(t)
+
+	ns := newNamespace()
+	_, err := bytecode.Unmarshal(ns, []byte("(+ 1 2)"))
+	as.EqualError(err, bytecode.ErrNotCompiled)
+
+	b, err := bytecode.Marshal(bytecode.Module{})
+	as.Nil(err)
+	b[4] = bytecode.Version + 1
+	_, err = bytecode.Unmarshal(ns, b)
+	as.EqualError(err, fmt.Sprintf(bytecode.ErrUnsupportedVersion, 2))
+
+	b, err = bytecode.Marshal(bytecode.Compile(ns, "(+ 1 2)"))
+	as.Nil(err)
+	_, err = bytecode.Unmarshal(ns, b[:len(b)-3])
+	as.NotNil(err)
+
+	// values that are nested too deeply are rejected, not recursed into
+	b = append([]byte("ale\x00"), bytecode.Version)
+	b = append(b, bytes.Repeat([]byte{11, 1}, 1000000)...)
+	_, err = bytecode.Unmarshal(ns, append(b, 0))
+	as.EqualError(err, wire.ErrMalformedValue)
+}
+
+func unmarshalLambda(l *vm.Lambda) error {
+	b, err := bytecode.Marshal(bytecode.Module{l})
+	if err != nil {
+		return err
+	}
+	_, err = bytecode.Unmarshal(newNamespace(), b)
+	return err
+}
+
+func TestVerification(t *testing.T) {
+	as := assert.New(t)
+
+	code := func(words ...isa.Word) *vm.Lambda {
+		return &vm.Lambda{
+			Constants: data.Values{S("value")},
+			Code:      words,
+			StackSize: 1,
+		}
+	}
+	w := func(oc isa.Opcode) isa.Word {
+		return isa.Word(oc)
+	}
+
+	as.Nil(unmarshalLambda(code(w(isa.Const), 0, w(isa.Return))))
+
+	as.EqualError(
+		unmarshalLambda(code(w(isa.Const), 0, 999)),
+		fmt.Sprintf(bytecode.ErrUnknownOpcode, 999),
+	)
+	as.EqualError(
+		unmarshalLambda(code(w(isa.Const))),
+		fmt.Sprintf(bytecode.ErrTruncatedCode, isa.Const),
+	)
+	as.EqualError(
+		unmarshalLambda(code(w(isa.Jump), 1, w(isa.RetNil))),
+		fmt.Sprintf(bytecode.ErrBadJumpTarget, 1),
+	)
+	as.EqualError(
+		unmarshalLambda(code(w(isa.Const), 1, w(isa.Return))),
+		fmt.Sprintf(bytecode.ErrConstantRange, 1),
+	)
+	as.EqualError(
+		unmarshalLambda(code(w(isa.Load), 0, w(isa.Return))),
+		fmt.Sprintf(bytecode.ErrLocalRange, 0),
+	)
+	as.EqualError(
+		unmarshalLambda(code(
+			w(isa.Const), 0, w(isa.Dup), w(isa.Dup),
+			w(isa.Pop), w(isa.Pop), w(isa.Return),
+		)),
+		fmt.Sprintf(bytecode.ErrStackSize, 1, 3),
+	)
+
+	huge := code(w(isa.Const), 0, w(isa.Return))
+	huge.StackSize = 1 << 40
+	as.EqualError(
+		unmarshalLambda(huge),
+		fmt.Sprintf(bytecode.ErrStackSize, 1<<40, 1),
+	)
+	huge = code(w(isa.Const), 0, w(isa.Return))
+	huge.LocalCount = 1 << 40
+	as.EqualError(
+		unmarshalLambda(huge),
+		fmt.Sprintf(bytecode.ErrLocalCount, 1<<40, 0),
+	)
+
+	as.EqualError(
+		unmarshalLambda(code(w(isa.Arg), 0, w(isa.Return))),
+		fmt.Sprintf(bytecode.ErrArgRange, 0),
+	)
+	args := code(w(isa.Arg), 1, w(isa.Return))
+	args.Arity = []int{1}
+	as.EqualError(
+		unmarshalLambda(args),
+		fmt.Sprintf(bytecode.ErrArgRange, 1),
+	)
+	args.Arity = []int{1, data.OrMore}
+	as.Nil(unmarshalLambda(args))
+	as.EqualError(
+		unmarshalLambda(code(w(isa.RestArg), 1, w(isa.Return))),
+		fmt.Sprintf(bytecode.ErrArgRange, 1),
+	)
+
+	as.EqualError(
+		unmarshalLambda(code(w(isa.Closure), 0, w(isa.Return))),
+		fmt.Sprintf(bytecode.ErrClosureRange, 0),
+	)
+	inner := code(w(isa.Closure), 1, w(isa.Return))
+	outer := func(words ...isa.Word) *vm.Lambda {
+		res := code(words...)
+		res.Constants = data.Values{inner}
+		res.StackSize = 2
+		return res
+	}
+	as.EqualError(
+		unmarshalLambda(outer(w(isa.Const), 0, w(isa.Return))),
+		fmt.Sprintf(bytecode.ErrNotInstantiated, 0),
+	)
+	as.EqualError(
+		unmarshalLambda(outer(
+			w(isa.One), w(isa.Const), 0, w(isa.Call1), w(isa.Return),
+		)),
+		fmt.Sprintf(bytecode.ErrClosureRange, 1),
+	)
+	instantiated := outer(
+		w(isa.One), w(isa.Two), w(isa.Const), 0,
+		w(isa.Call), 2, w(isa.Return),
+	)
+	instantiated.StackSize = 3
+	as.Nil(unmarshalLambda(instantiated))
+	as.EqualError(
+		unmarshalLambda(code(w(isa.Const), 0, w(isa.RetNil))),
+		fmt.Sprintf(bytecode.ErrVerifyFailed, "invalid stack end-state: 1"),
+	)
+}
@@ -0,0 +1,120 @@
+package bytecode
+
+import (
+	"github.com/kode4food/ale/compiler/encoder"
+	"github.com/kode4food/ale/compiler/generate"
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/env"
+	"github.com/kode4food/ale/read"
+	"github.com/kode4food/ale/runtime/isa"
+	"github.com/kode4food/ale/runtime/vm"
+)
+
+type (
+	// compiling wraps the namespaces that a Module is compiled in. They
+	// don't own any entries, so the compiler refers to globals by name
+	// rather than embedding their values, unless it's calling them
+	compiling struct {
+		env.Namespace
+		environment *env.Environment
+	}
+
+	// entry wraps the entries of a compiling namespace, so that the
+	// functions bound to them are seen by the compiler as globals
+	entry struct {
+		env.Entry
+		symbol data.Symbol
+	}
+
+	// global stands in for a function that's bound to a global, so that
+	// when the compiler embeds it in a call, it's encoded by name. When a
+	// Module is decoded, it stands in for the function until it's linked
+	global struct {
+		data.Function
+		symbol data.Symbol
+	}
+)
+
+// Compile compiles the top-level forms of the specified raw source into
+// a Module. Each form is evaluated once it's compiled, so that the forms
+// that follow can make use of the globals and macros that it defines.
+// Other than the root, the namespace's domain is the only one that the
+// forms can refer to by qualified name
+func Compile(ns env.Namespace, src data.String) Module {
+	c := newCompiling(ns)
+	var res Module
+	forms := read.FromString(src)
+	for f, r, ok := forms.Split(); ok; f, r, ok = r.Split() {
+		e := encoder.NewEncoder(c)
+		generate.Value(e, f)
+		e.Emit(isa.Return)
+		l := vm.LambdaFromEncoder(e)
+		setGlobals(l, ns)
+		l.Call().(data.Function).Call()
+		res = append(res, l)
+	}
+	return res
+}
+
+// setGlobals sets the namespace of a Lambda, and of the Lambdas that are
+// nested in it, to the one that was wrapped while compiling it. Its code
+// must only see the bound values themselves when it's run
+func setGlobals(l *vm.Lambda, ns env.Namespace) {
+	l.Globals = ns
+	for _, n := range nested(l) {
+		setGlobals(n, ns)
+	}
+}
+
+func newCompiling(ns env.Namespace) *compiling {
+	orig := ns.Environment()
+	e := env.NewEnvironment()
+	e.Get(env.RootDomain, func() env.Namespace {
+		return &compiling{
+			Namespace:   orig.GetRoot(),
+			environment: e,
+		}
+	})
+	res := &compiling{
+		Namespace:   ns,
+		environment: e,
+	}
+	e.Get(ns.Domain(), func() env.Namespace {
+		return res
+	})
+	return res
+}
+
+func (c *compiling) Environment() *env.Environment {
+	return c.environment
+}
+
+func (c *compiling) Declare(n data.Name) env.Entry {
+	return c.wrap(c.Namespace.Declare(n))
+}
+
+func (c *compiling) Resolve(n data.Name) (env.Entry, bool) {
+	if e, ok := c.Namespace.Resolve(n); ok {
+		return c.wrap(e), true
+	}
+	return nil, false
+}
+
+func (c *compiling) wrap(e env.Entry) env.Entry {
+	n := e.Name()
+	if e.Owner().Domain() == env.RootDomain {
+		return &entry{Entry: e, symbol: env.RootSymbol(n)}
+	}
+	return &entry{Entry: e, symbol: data.NewLocalSymbol(n)}
+}
+
+func (e *entry) Value() data.Value {
+	v := e.Entry.Value()
+	if fn, ok := v.(data.Function); ok {
+		return &global{
+			Function: fn,
+			symbol:   e.symbol,
+		}
+	}
+	return v
+}
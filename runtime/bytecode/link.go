@@ -0,0 +1,79 @@
+package bytecode
+
+import (
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/env"
+	"github.com/kode4food/ale/runtime/isa"
+	"github.com/kode4food/ale/runtime/vm"
+)
+
+// linker resolves the globals that the Lambdas of a decoded Module refer
+// to. The functions that were embedded by the compiler are resolved by
+// name, and like the compiler does, the values of bound globals that are
+// owned by a Lambda's namespace are embedded, so that they needn't be
+// resolved each time the code refers to them
+type linker map[*vm.Lambda]bool
+
+func (l linker) link(fn *vm.Lambda) {
+	if l[fn] {
+		return
+	}
+	l[fn] = true
+	for i, c := range fn.Constants {
+		if g, ok := c.(*global); ok {
+			fn.Constants[i] = env.MustResolveValue(fn.Globals, g.symbol)
+		}
+	}
+	for _, n := range nested(fn) {
+		l.link(n)
+	}
+
+	// the code was verified when it was decoded
+	code, _ := unflatten(fn.Code)
+	res := make(isa.Instructions, 0, len(code))
+	linked := false
+	for i := 0; i < len(code); i++ {
+		inst := code[i]
+		if inst.Opcode == isa.Const && i+1 < len(code) &&
+			code[i+1].Opcode == isa.Resolve {
+			if v, ok := resolveOwned(fn, fn.Constants[inst.Args[0]]); ok {
+				idx := isa.Word(len(fn.Constants))
+				fn.Constants = append(fn.Constants, v)
+				res = append(res, isa.New(isa.Const, idx))
+				linked = true
+				i++
+				continue
+			}
+		}
+		res = append(res, inst)
+	}
+	if linked {
+		fn.Code = isa.Flatten(res)
+	}
+}
+
+func resolveOwned(fn *vm.Lambda, v data.Value) (data.Value, bool) {
+	s, ok := v.(data.Symbol)
+	if !ok {
+		return nil, false
+	}
+	e, ok := env.ResolveSymbol(fn.Globals, s)
+	if !ok || !e.IsBound() || e.Owner() != fn.Globals {
+		return nil, false
+	}
+	return e.Value(), true
+}
+
+// nested returns the Lambdas that are nested in the constants of the
+// provided Lambda, whether or not they've been instantiated as closures
+func nested(l *vm.Lambda) []*vm.Lambda {
+	var res []*vm.Lambda
+	for _, c := range l.Constants {
+		if n, ok := c.(*vm.Lambda); ok {
+			res = append(res, n)
+		} else if n, ok := vm.ClosureLambda(c); ok {
+			res = append(res, n)
+		}
+	}
+	return res
+}
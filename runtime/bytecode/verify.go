@@ -0,0 +1,300 @@
+package bytecode
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kode4food/ale/compiler/ir/analysis"
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/env"
+	"github.com/kode4food/ale/runtime/isa"
+	"github.com/kode4food/ale/runtime/vm"
+)
+
+// decoder decodes the lambdas of a Module. It records how many values
+// each of them expects to capture, so that the lambdas whose constants
+// include them can be checked to provide that many
+type decoder struct {
+	globals  env.Namespace
+	captures map[*vm.Lambda]int
+}
+
+// Error messages
+const (
+	ErrMalformedLambda  = "malformed lambda"
+	ErrUnknownOpcode    = "unknown opcode: %d"
+	ErrTruncatedCode    = "truncated instruction: %s"
+	ErrBadJumpTarget    = "jump target is not an instruction: %d"
+	ErrConstantRange    = "constant index out of range: %d"
+	ErrLocalRange       = "local index out of range: %d"
+	ErrArgRange         = "argument index out of range: %d"
+	ErrClosureRange     = "closure index out of range: %d"
+	ErrNotInstantiated  = "lambda constant is not instantiated: %d"
+	ErrStackSize        = "stack size %d doesn't match required: %d"
+	ErrLocalCount       = "local count %d doesn't match required: %d"
+	ErrVerifyFailed     = "lambda failed verification: %v"
+	ErrUnknownExtension = "unknown extension: %s"
+)
+
+const lambdaFields = 5
+
+func (d *decoder) internal(k data.Keyword, v data.Value) (data.Value, error) {
+	switch k {
+	case lambdaExtension:
+		return d.lambda(v)
+	case closureExtension:
+		l, ok := v.(*vm.Lambda)
+		if !ok {
+			return nil, errors.New(ErrMalformedLambda)
+		}
+		if err := d.checkCaptures(l, 0); err != nil {
+			return nil, err
+		}
+		return l.Call(), nil
+	case nameExtension:
+		if s, ok := v.(data.String); ok {
+			return data.Name(s), nil
+		}
+		return nil, fmt.Errorf(ErrUnexpectedValue, v)
+	case globalExtension:
+		if s, ok := v.(data.Symbol); ok {
+			return &global{symbol: s}, nil
+		}
+		return nil, fmt.Errorf(ErrUnexpectedValue, v)
+	default:
+		return nil, fmt.Errorf(ErrUnknownExtension, k)
+	}
+}
+
+func (d *decoder) lambda(v data.Value) (*vm.Lambda, error) {
+	f, ok := v.(data.Vector)
+	if !ok || f.Count() != lambdaFields {
+		return nil, errors.New(ErrMalformedLambda)
+	}
+	fields := f.Values()
+	stackSize, ok1 := toInts(fields[0])
+	localCount, ok2 := toInts(fields[1])
+	arity, ok3 := toInts(fields[2])
+	code, ok4 := toInts(fields[3])
+	constants, ok5 := fields[4].(data.Vector)
+	if !(ok1 && ok2 && ok3 && ok4 && ok5) ||
+		len(stackSize) != 1 || len(localCount) != 1 || len(arity) > 2 {
+		return nil, errors.New(ErrMalformedLambda)
+	}
+
+	res := &vm.Lambda{
+		Globals:    d.globals,
+		Constants:  constants.Values(),
+		StackSize:  stackSize[0],
+		LocalCount: localCount[0],
+		Code:       make([]isa.Word, len(code)),
+	}
+	for i, w := range code {
+		res.Code[i] = isa.Word(w)
+	}
+	if len(arity) != 0 {
+		res.Arity = arity
+		res.ArityChecker = data.MakeChecker(arity...)
+	}
+	if err := d.verify(res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// checkCaptures checks that a Lambda that's instantiated with the
+// provided number of values doesn't refer to any more than that
+func (d *decoder) checkCaptures(l *vm.Lambda, count int) error {
+	if n := d.captures[l]; n > count {
+		return fmt.Errorf(ErrClosureRange, n-1)
+	}
+	return nil
+}
+
+// toInts converts either a single Integer or a Vector of them into a
+// slice of non-negative ints, the exception being data.OrMore
+func toInts(v data.Value) ([]int, bool) {
+	var vals data.Values
+	switch v := v.(type) {
+	case data.Integer:
+		vals = data.Values{v}
+	case data.Vector:
+		vals = v.Values()
+	default:
+		return nil, false
+	}
+	res := make([]int, len(vals))
+	for i, e := range vals {
+		n, ok := e.(data.Integer)
+		if !ok || n < data.OrMore {
+			return nil, false
+		}
+		res[i] = int(n)
+	}
+	return res, true
+}
+
+// verify checks that a Lambda's code can be run safely. Its jumps and
+// stack effects are checked by the same analysis that the compiler
+// applies to the code that it generates, and its stack size and local
+// count must be exactly what that code requires
+func (d *decoder) verify(l *vm.Lambda) (err error) {
+	if l.StackSize < 0 || l.LocalCount < 0 {
+		return errors.New(ErrMalformedLambda)
+	}
+	code, err := unflatten(l.Code)
+	if err != nil {
+		return err
+	}
+	if err := d.checkIndexes(l, code); err != nil {
+		return err
+	}
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf(ErrVerifyFailed, rec)
+		}
+	}()
+	analysis.Verify(code)
+	if size, _ := analysis.CalculateStackSize(code); size != l.StackSize {
+		return fmt.Errorf(ErrStackSize, l.StackSize, size)
+	}
+	if count := analysis.CalculateLocalCount(code); count != l.LocalCount {
+		return fmt.Errorf(ErrLocalCount, l.LocalCount, count)
+	}
+	return nil
+}
+
+// checkIndexes checks the indexes that a Lambda's instructions refer to
+// and records how many captured values it expects. A lambda constant is
+// instantiated by the call that immediately follows its loading, so the
+// number of values it captures can be checked against that call
+func (d *decoder) checkIndexes(l *vm.Lambda, code isa.Instructions) error {
+	args := argLimit(l)
+	captures := 0
+	for i, inst := range code {
+		switch inst.Opcode {
+		case isa.Const:
+			idx := int(inst.Args[0])
+			if idx >= len(l.Constants) {
+				return fmt.Errorf(ErrConstantRange, idx)
+			}
+			c, ok := l.Constants[idx].(*vm.Lambda)
+			if !ok {
+				continue
+			}
+			count, ok := callCount(code, i+1)
+			if !ok {
+				return fmt.Errorf(ErrNotInstantiated, idx)
+			}
+			if err := d.checkCaptures(c, count); err != nil {
+				return err
+			}
+		case isa.Load, isa.Store:
+			if idx := int(inst.Args[0]); idx >= l.LocalCount {
+				return fmt.Errorf(ErrLocalRange, idx)
+			}
+		case isa.Arg:
+			if idx := int(inst.Args[0]); args >= 0 && idx >= args {
+				return fmt.Errorf(ErrArgRange, idx)
+			}
+		case isa.RestArg:
+			if idx := int(inst.Args[0]); args >= 0 && idx > args {
+				return fmt.Errorf(ErrArgRange, idx)
+			}
+		case isa.Closure:
+			if idx := int(inst.Args[0]); idx >= captures {
+				captures = idx + 1
+			}
+		}
+	}
+	d.captures[l] = captures
+	return nil
+}
+
+// argLimit returns the most arguments that a Lambda accepts, or -1 if
+// it accepts any number of them. A Lambda without an arity accepts none
+func argLimit(l *vm.Lambda) int {
+	switch len(l.Arity) {
+	case 0:
+		return 0
+	case 1:
+		return l.Arity[0]
+	default:
+		return l.Arity[1]
+	}
+}
+
+// callCount returns the number of arguments that the instruction at the
+// provided index passes, as long as it's a call
+func callCount(code isa.Instructions, idx int) (int, bool) {
+	if idx >= len(code) {
+		return 0, false
+	}
+	switch inst := code[idx]; inst.Opcode {
+	case isa.Call0:
+		return 0, true
+	case isa.Call1:
+		return 1, true
+	case isa.Call, isa.TailCall:
+		return int(inst.Args[0]), true
+	default:
+		return 0, false
+	}
+}
+
+// unflatten reverses what isa.Flatten does to a set of instructions. A
+// label is anchored for every jump, right before the instruction that it
+// targets. When several labels share an instruction, the ones targeted
+// by later jumps, which belong to more deeply nested branches, come first
+func unflatten(code []isa.Word) (isa.Instructions, error) {
+	var insts isa.Instructions
+	at := make([]int, len(code)+1)
+	for i := range at {
+		at[i] = -1
+	}
+	for pc := 0; pc < len(code); {
+		oc := isa.Opcode(code[pc])
+		effect, ok := isa.Effects[oc]
+		if !ok || effect.Ignore {
+			return nil, fmt.Errorf(ErrUnknownOpcode, code[pc])
+		}
+		end := pc + effect.Size
+		if end > len(code) {
+			return nil, fmt.Errorf(ErrTruncatedCode, oc)
+		}
+		at[pc] = len(insts)
+		args := make([]isa.Word, effect.Size-1)
+		copy(args, code[pc+1:end])
+		insts = append(insts, &isa.Instruction{Opcode: oc, Args: args})
+		pc = end
+	}
+	at[len(code)] = len(insts)
+
+	labels := make([][]isa.Word, len(insts)+1)
+	for i, inst := range insts {
+		if inst.Opcode != isa.Jump && inst.Opcode != isa.CondJump {
+			continue
+		}
+		target := -1
+		if idx := int(inst.Args[0]); idx < len(at) {
+			target = at[idx]
+		}
+		if target < 0 {
+			return nil, fmt.Errorf(ErrBadJumpTarget, inst.Args[0])
+		}
+		labels[target] = append(labels[target], isa.Word(i))
+		inst.Args[0] = isa.Word(i)
+	}
+
+	res := make(isa.Instructions, 0, len(insts))
+	for i, lbls := range labels {
+		for j := len(lbls) - 1; j >= 0; j-- {
+			res = append(res, isa.New(isa.Label, lbls[j]))
+		}
+		if i < len(insts) {
+			res = append(res, insts[i])
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,268 @@
+// Package disasm produces human-readable listings of the code that the
+// compiler produced for a lambda. The code from before optimization is
+// only listed if it was retained, which vm.RetainSource enables
+package disasm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/env"
+	"github.com/kode4food/ale/runtime/isa"
+	"github.com/kode4food/ale/runtime/vm"
+)
+
+type (
+	// listing accumulates the disassembly of a lambda and the lambdas
+	// that are nested in it
+	listing struct {
+		strings.Builder
+		globals map[data.Value]data.Symbol
+		seen    map[*vm.Lambda]bool
+	}
+
+	// function is a lambda, along with the values that were captured by
+	// the closure it was instantiated as, if any
+	function struct {
+		name  string
+		cells data.Values
+		*vm.Lambda
+	}
+)
+
+// Error messages
+const (
+	ErrNotLambda = "value is not a compiled lambda: %s"
+)
+
+const rootName = "lambda"
+
+// Value disassembles a compiled lambda, or a closure that was
+// instantiated from one, along with the lambdas that are nested in its
+// constants. Functions that are bound in the root namespace are only
+// referred to by name, while others are listed once
+func Value(v data.Value) (string, error) {
+	fn, ok := asFunction(rootName, v)
+	if !ok {
+		return "", fmt.Errorf(ErrNotLambda, v)
+	}
+	l := &listing{
+		globals: globalNames(fn.Globals),
+		seen:    map[*vm.Lambda]bool{fn.Lambda: true},
+	}
+	l.function(fn)
+	return l.String(), nil
+}
+
+func asFunction(name string, v data.Value) (*function, bool) {
+	if l, ok := v.(*vm.Lambda); ok {
+		return &function{name: name, Lambda: l}, true
+	}
+	if l, cells, ok := vm.ClosureCells(v); ok {
+		return &function{name: name, cells: cells, Lambda: l}, true
+	}
+	return nil, false
+}
+
+// globalNames maps the functions that are bound to the globals of a
+// namespace and its root to their names. A constant that's one of them
+// was embedded by the compiler as a reference, rather than nested
+func globalNames(ns env.Namespace) map[data.Value]data.Symbol {
+	res := map[data.Value]data.Symbol{}
+	if ns == nil {
+		return res
+	}
+	for _, ns := range []env.Namespace{ns, ns.Environment().GetRoot()} {
+		for _, n := range ns.Declared() {
+			e, ok := ns.Resolve(n)
+			if !ok || !e.IsBound() {
+				continue
+			}
+			if v := e.Value(); isFunctionRef(v) {
+				if _, ok := res[v]; !ok {
+					res[v] = qualify(ns, n)
+				}
+			}
+		}
+	}
+	return res
+}
+
+func qualify(ns env.Namespace, n data.Name) data.Symbol {
+	if ns.Domain() == env.RootDomain {
+		return env.RootSymbol(n)
+	}
+	return data.NewLocalSymbol(n)
+}
+
+// isFunctionRef returns whether a value is a function that's referred
+// to by pointer, meaning that it can be identified by a map key
+func isFunctionRef(v data.Value) bool {
+	_, ok := v.(data.Function)
+	return ok && reflect.ValueOf(v).Kind() == reflect.Ptr
+}
+
+func (l *listing) function(fn *function) {
+	fmt.Fprintf(&l.Builder, "%s:\n", fn.name)
+	fmt.Fprintf(&l.Builder, "  stack size:  %d\n", fn.StackSize)
+	fmt.Fprintf(&l.Builder, "  local count: %d\n", fn.LocalCount)
+
+	var nested []*function
+	if len(fn.cells) != 0 {
+		l.WriteString("  closure cells:\n")
+		for i, c := range fn.cells {
+			fmt.Fprintf(&l.Builder, "    %d: %s\n", i, data.MaybeQuoteString(c))
+		}
+	}
+	if len(fn.Constants) != 0 {
+		l.WriteString("  constants:\n")
+		for i := range fn.Constants {
+			if n, ok := l.nested(fn, i); ok {
+				nested = append(nested, n)
+			}
+			fmt.Fprintf(&l.Builder, "    %d: %s\n", i, l.constant(fn, i))
+		}
+	}
+
+	if fn.Source != nil {
+		l.WriteString("  pre-optimization:\n")
+		l.source(fn)
+		l.WriteString("  post-optimization:\n")
+	} else {
+		l.WriteString("  code:\n")
+	}
+	l.code(fn)
+
+	for _, n := range nested {
+		l.WriteString("\n")
+		l.function(n)
+	}
+}
+
+// constant describes one of a function's constants. Functions are
+// described by the name of the global they're bound to, or otherwise by
+// the name of their listing
+func (l *listing) constant(fn *function, idx int) string {
+	c := fn.Constants[idx]
+	if s, ok := l.global(c); ok {
+		return s.String()
+	}
+	if _, ok := asFunction("", c); ok {
+		return nestedName(fn, idx)
+	}
+	return data.MaybeQuoteString(c)
+}
+
+// nested returns the function that one of a function's constants needs
+// to be listed as, unless it's bound in the root or was already listed
+func (l *listing) nested(fn *function, idx int) (*function, bool) {
+	c := fn.Constants[idx]
+	if s, ok := l.global(c); ok && isRoot(s) {
+		return nil, false
+	}
+	n, ok := asFunction(nestedName(fn, idx), c)
+	if !ok || l.seen[n.Lambda] {
+		return nil, false
+	}
+	l.seen[n.Lambda] = true
+	if s, ok := l.global(c); ok {
+		n.name = fmt.Sprintf("%s (%s)", n.name, s)
+	}
+	return n, true
+}
+
+func isRoot(s data.Symbol) bool {
+	q, ok := s.(data.QualifiedSymbol)
+	return ok && q.Domain() == env.RootDomain
+}
+
+func nestedName(fn *function, idx int) string {
+	return fmt.Sprintf("%s/%d", strings.Fields(fn.name)[0], idx)
+}
+
+// global returns the name of the global that a function is bound to
+func (l *listing) global(v data.Value) (data.Symbol, bool) {
+	if !isFunctionRef(v) {
+		return nil, false
+	}
+	s, ok := l.globals[v]
+	return s, ok
+}
+
+// source lists the instructions that the compiler produced, before they
+// were optimized. Offsets and label targets are those that the
+// instructions would have if they were flattened
+func (l *listing) source(fn *function) {
+	offsets := map[isa.Word]int{}
+	pc := 0
+	for _, inst := range fn.Source {
+		if inst.Opcode == isa.Label {
+			offsets[inst.Args[0]] = pc
+		}
+		pc += size(inst.Opcode)
+	}
+
+	pc = 0
+	for _, inst := range fn.Source {
+		comment := l.comment(fn, inst)
+		switch inst.Opcode {
+		case isa.Jump, isa.CondJump:
+			comment = fmt.Sprintf("-> %04d", offsets[inst.Args[0]])
+		}
+		l.instruction(pc, inst, comment)
+		pc += size(inst.Opcode)
+	}
+}
+
+// code lists the optimized instructions that the VM runs
+func (l *listing) code(fn *function) {
+	code := fn.Code
+	for pc := 0; pc < len(code); {
+		oc := isa.Opcode(code[pc])
+		n := size(oc)
+		if n == 0 || pc+n > len(code) {
+			fmt.Fprintf(&l.Builder, "    %04d  %d\n", pc, code[pc])
+			pc++
+			continue
+		}
+		args := append([]isa.Word{}, code[pc+1:pc+n]...)
+		inst := &isa.Instruction{Opcode: oc, Args: args}
+		comment := l.comment(fn, inst)
+		switch oc {
+		case isa.Jump, isa.CondJump:
+			comment = fmt.Sprintf("-> %04d", args[0])
+		}
+		l.instruction(pc, inst, comment)
+		pc += n
+	}
+}
+
+func (l *listing) comment(fn *function, inst *isa.Instruction) string {
+	if inst.Opcode != isa.Const {
+		return ""
+	}
+	if idx := int(inst.Args[0]); idx < len(fn.Constants) {
+		return l.constant(fn, idx)
+	}
+	return ""
+}
+
+func (l *listing) instruction(pc int, inst *isa.Instruction, comment string) {
+	if comment == "" {
+		fmt.Fprintf(&l.Builder, "    %04d  %s\n", pc, inst)
+		return
+	}
+	fmt.Fprintf(&l.Builder, "    %04d  %-20s ; %s\n", pc, inst, comment)
+}
+
+// size returns the number of words that an instruction occupies once
+// it's flattened. Labels and other ignored instructions occupy none
+func size(oc isa.Opcode) int {
+	effect, ok := isa.Effects[oc]
+	if !ok || effect.Ignore {
+		return 0
+	}
+	return effect.Size
+}
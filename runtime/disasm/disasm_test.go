@@ -0,0 +1,92 @@
+package disasm_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kode4food/ale/core/bootstrap"
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/env"
+	"github.com/kode4food/ale/eval"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+	"github.com/kode4food/ale/runtime/disasm"
+	"github.com/kode4food/ale/runtime/isa"
+	"github.com/kode4food/ale/runtime/vm"
+)
+
+func newNamespace() env.Namespace {
+	e := env.NewEnvironment()
+	bootstrap.Into(e)
+	return e.GetAnonymous()
+}
+
+func TestNestedLambdas(t *testing.T) {
+	as := assert.New(t)
+	defer vm.RetainSource(vm.RetainSource(true))
+
+	ns := newNamespace()
+	fn := eval.String(ns, `
+		(define (double x) (* x 2))
+		(define (pick x)
+		  (if (> x 10)
+		      (lambda () (double x))
+		      (lambda () "small")))
+		pick
+	`)
+	res, err := disasm.Value(fn)
+	as.Nil(err)
+	s := S(res)
+
+	as.Contains("lambda:\n  stack size:  3\n  local count: 0\n", s)
+	as.Contains("    2: ale/>\n", s)
+	as.Contains("    0019  CondJump(2)          ; -> 0024\n", s)
+	as.Contains("    0024  Label(2)\n", s)
+	as.Contains("    0019  CondJump(26)         ; -> 0026\n", s)
+	as.Contains("    0028  Const(4)             ; lambda/4\n", s)
+	as.Contains("\nlambda/3:\n", s)
+	as.Contains("\nlambda/4:\n", s)
+	as.Contains("    1: \"small\"\n", s)
+
+	// functions that aren't bound in the root are listed once
+	as.Contains("\nlambda/4/1 (double):\n", s)
+	as.Equal(1, strings.Count(res, "(double):"))
+}
+
+func TestClosureCells(t *testing.T) {
+	as := assert.New(t)
+
+	ns := newNamespace()
+	fn := eval.String(ns, `(let [x "captured"] (lambda () x))`)
+	res, err := disasm.Value(fn)
+	as.Nil(err)
+	as.Contains("  closure cells:\n    0: \"captured\"\n", S(res))
+	as.Contains("    0010  Closure(0)\n", S(res))
+}
+
+func TestNotRetained(t *testing.T) {
+	as := assert.New(t)
+
+	l := &vm.Lambda{
+		Constants: data.Values{I(42)},
+		Code: []isa.Word{
+			isa.Word(isa.Const), 0, isa.Word(isa.Return),
+		},
+		StackSize: 1,
+	}
+	res, err := disasm.Value(l)
+	as.Nil(err)
+	as.String(`lambda:
+  stack size:  1
+  local count: 0
+  constants:
+    0: 42
+  code:
+    0000  Const(0)             ; 42
+    0002  Return()
+`, S(res))
+
+	_, err = disasm.Value(I(42))
+	as.EqualError(err, fmt.Sprintf(disasm.ErrNotLambda, "42"))
+}
@@ -17,8 +17,10 @@ const (
 const closureType = "%s-closure"
 
 type closure struct {
-	lambda *Lambda
-	values data.Values
+	lambda  *Lambda
+	values  data.Values
+	monitor *Monitor
+	origin  *closure
 }
 
 func newClosure(lambda *Lambda, values data.Values) *closure {
@@ -28,8 +30,65 @@ func newClosure(lambda *Lambda, values data.Values) *closure {
 	}
 }
 
+// ClosureLambda returns the Lambda that the provided Value was
+// instantiated from, as long as it's a closure that captured no values
+func ClosureLambda(v data.Value) (*Lambda, bool) {
+	if l, values, ok := ClosureCells(v); ok && len(values) == 0 {
+		return l, true
+	}
+	return nil, false
+}
+
+// ClosureCells returns the Lambda that the provided Value was
+// instantiated from, and the values that it captured, as long as it's
+// a closure
+func ClosureCells(v data.Value) (*Lambda, data.Values, bool) {
+	if c, ok := v.(*closure); ok {
+		return c.lambda, c.values, true
+	}
+	return nil, nil, false
+}
+
+// bind returns a copy of the closure that's run by the provided Monitor
+// when it's called from Go. The copy is equal to the closure
+func (c *closure) bind(m *Monitor) *closure {
+	return &closure{
+		lambda:  c.lambda,
+		values:  c.values,
+		monitor: m,
+		origin:  c.unwrap(),
+	}
+}
+
+func (c *closure) unwrap() *closure {
+	if c.origin != nil {
+		return c.origin
+	}
+	return c
+}
+
+// monitorFor returns the Monitor that the closure is run by when it's
+// called by VM code that's run by the provided one, if any
+func (c *closure) monitorFor(m *Monitor) *Monitor {
+	if m != nil {
+		return m
+	}
+	return c.monitor
+}
+
 // Call turns closure into a Function
 func (c *closure) Call(args ...data.Value) data.Value {
+	return c.exec(c.monitor, args...)
+}
+
+// exec runs the closure. Calls from VM code to other closures pass the
+// Monitor along directly, while the closures that are passed to Go
+// functions are bound to it
+func (c *closure) exec(m *Monitor, args ...data.Value) data.Value {
+	if m != nil {
+		m.enter()
+		defer m.exit()
+	}
 	current := c
 	lambda := current.lambda
 	code := lambda.Code
@@ -45,6 +104,9 @@ nextPC:
 	PC++
 
 opSwitch:
+	if m != nil {
+		m.step()
+	}
 	op := isa.Opcode(code[PC])
 	switch op {
 	case isa.Nil:
@@ -293,6 +355,10 @@ opSwitch:
 	case isa.Call0:
 		SP1 := SP + 1
 		fn := stack[SP1].(data.Function)
+		if vc, ok := fn.(*closure); ok {
+			stack[SP1] = vc.exec(vc.monitorFor(m))
+			goto nextPC
+		}
 		stack[SP1] = fn.Call()
 		goto nextPC
 
@@ -301,6 +367,13 @@ opSwitch:
 		SP1 := SP + 1
 		fn := stack[SP].(data.Function)
 		arg := stack[SP1]
+		if vc, ok := fn.(*closure); ok {
+			stack[SP1] = vc.exec(vc.monitorFor(m), arg)
+			goto nextPC
+		}
+		if m != nil {
+			arg = m.bind(arg)
+		}
 		stack[SP1] = fn.Call(arg)
 		goto nextPC
 
@@ -313,7 +386,14 @@ opSwitch:
 		RES := SP1 + int(argCount)
 		args := make(data.Values, argCount)
 		copy(args, stack[SP2:])
-		stack[RES] = fn.Call(args...)
+		if vc, ok := fn.(*closure); ok {
+			stack[RES] = vc.exec(vc.monitorFor(m), args...)
+		} else {
+			if m != nil {
+				m.bindArgs(args)
+			}
+			stack[RES] = fn.Call(args...)
+		}
 		SP = RES - 1
 		goto nextPC
 
@@ -324,6 +404,12 @@ opSwitch:
 		copy(args, stack[SP1+1:])
 		val := stack[SP1]
 		if vc, ok := val.(*closure); ok {
+			if m == nil && vc.monitor != nil {
+				return vc.exec(vc.monitor, args...)
+			}
+			if m != nil {
+				m.checkDone()
+			}
 			if vc != current {
 				current = vc
 				lambda = current.lambda
@@ -338,19 +424,28 @@ opSwitch:
 			goto opSwitch
 		}
 		fn := val.(data.Function)
+		if m != nil {
+			m.bindArgs(args)
+		}
 		return fn.Call(args...)
 
 	case isa.Jump:
-		off := isa.Offset(code[PC+1])
-		PC = int(off)
+		off := int(isa.Offset(code[PC+1]))
+		if m != nil && off <= PC {
+			m.checkDone()
+		}
+		PC = off
 		goto opSwitch
 
 	case isa.CondJump:
 		SP++
 		val := stack[SP].(data.Bool)
 		if val {
-			off := isa.Offset(code[PC+1])
-			PC = int(off)
+			off := int(isa.Offset(code[PC+1]))
+			if m != nil && off <= PC {
+				m.checkDone()
+			}
+			PC = off
 			goto opSwitch
 		}
 		PC += 2
@@ -395,11 +490,16 @@ func (c *closure) Type() data.Name {
 
 func (c *closure) Equal(v data.Value) bool {
 	if v, ok := v.(*closure); ok {
-		return c == v
+		return c.unwrap() == v.unwrap()
 	}
 	return false
 }
 
+// HashCode returns the same hash code for a closure and its bound copies
+func (c *closure) HashCode() uint64 {
+	return data.HashString(c.String())
+}
+
 func (c *closure) String() string {
-	return data.DumpString(c)
+	return data.DumpString(c.unwrap())
 }
@@ -1,7 +1,10 @@
 package vm
 
 import (
+	"sync/atomic"
+
 	"github.com/kode4food/ale/compiler/encoder"
+	"github.com/kode4food/ale/compiler/ir/analysis"
 	"github.com/kode4food/ale/compiler/ir/optimize"
 	"github.com/kode4food/ale/data"
 	"github.com/kode4food/ale/env"
@@ -10,28 +13,53 @@ import (
 
 const lambdaType = "lambda"
 
-// Lambda encapsulates the initial environment of a virtual machine
+// retainSource is non-zero when Lambdas retain their unoptimized code
+var retainSource int32
+
+// Lambda encapsulates the initial environment of a virtual machine.
+// Source holds the instructions that Code was optimized from, if they
+// were retained, so that the two can be disassembled side by side
 type Lambda struct {
 	Globals      env.Namespace
 	Constants    data.Values
 	Code         []isa.Word
+	Source       isa.Instructions
 	StackSize    int
 	LocalCount   int
+	Arity        []int
 	ArityChecker data.ArityChecker
 }
 
 // LambdaFromEncoder instantiates a VM Lambda from the provided
-// Encoder's intermediate representation
+// Encoder's intermediate representation. Its stack size and local count
+// are those of the optimized code, which is what the VM runs
 func LambdaFromEncoder(e encoder.Encoder) *Lambda {
 	code := e.Code()
 	optimized := optimize.Instructions(code)
-	return &Lambda{
+	stackSize, _ := analysis.CalculateStackSize(optimized)
+	res := &Lambda{
 		Globals:    e.Globals(),
 		Constants:  e.Constants(),
-		StackSize:  e.StackSize(),
-		LocalCount: e.LocalCount(),
+		StackSize:  stackSize,
+		LocalCount: analysis.CalculateLocalCount(optimized),
 		Code:       isa.Flatten(optimized),
 	}
+	if atomic.LoadInt32(&retainSource) != 0 {
+		res.Source = code
+	}
+	return res
+}
+
+// RetainSource sets whether the Lambdas that are instantiated from then
+// on retain the instructions that their code was optimized from, and
+// returns the previous setting. Retention is off by default, because
+// the instructions are only needed for disassembly
+func RetainSource(retain bool) bool {
+	var v int32
+	if retain {
+		v = 1
+	}
+	return atomic.SwapInt32(&retainSource, v) != 0
 }
 
 // Call allows a VM Lambda to be called for the purpose
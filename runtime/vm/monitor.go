@@ -0,0 +1,180 @@
+package vm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/kode4food/ale/data"
+)
+
+type (
+	// Limits constrain an evaluation that's run by a Monitor. A zero
+	// value for any of them means that it's unlimited
+	Limits struct {
+		// Instructions is the number of VM instructions that can be
+		// performed
+		Instructions uint64
+
+		// Depth is the number of nested calls that can be made. Calls
+		// that are made by different tasks are counted together
+		Depth int
+	}
+
+	// Monitor enforces the cancellation of a Context and a set of
+	// Limits on the VM code that's run by its Call method. The Monitor
+	// is passed along from one closure to the next, rather than being
+	// associated with a task. Closures that monitored code passes to a
+	// Go function are bound to the Monitor, so it also applies when the
+	// function calls them, including from the tasks that it starts
+	Monitor struct {
+		ctx     context.Context
+		done    <-chan struct{}
+		limits  Limits
+		onError ErrorHandler
+		steps   uint64
+		depth   int32
+	}
+
+	// ErrorHandler is called with the errors that are raised by the
+	// functions that monitored code runs asynchronously, and that
+	// nothing else can observe
+	ErrorHandler func(interface{})
+
+	errorHandlerKey struct{}
+)
+
+// Limit error types
+const (
+	Interrupted    = data.Keyword("interrupted")
+	TimedOut       = data.Keyword("timed-out")
+	BudgetExceeded = data.Keyword("budget-exceeded")
+	DepthExceeded  = data.Keyword("depth-exceeded")
+)
+
+// Error messages
+const (
+	ErrInterrupted    = "evaluation was interrupted"
+	ErrTimedOut       = "evaluation timed out"
+	ErrBudgetExceeded = "instruction budget exceeded: %d"
+	ErrDepthExceeded  = "maximum call depth exceeded: %d"
+)
+
+// NewMonitor returns a Monitor that enforces the cancellation of the
+// provided Context and the specified Limits
+func NewMonitor(ctx context.Context, limits Limits) *Monitor {
+	onError, _ := ctx.Value(errorHandlerKey{}).(ErrorHandler)
+	return &Monitor{
+		ctx:     ctx,
+		done:    ctx.Done(),
+		limits:  limits,
+		onError: onError,
+	}
+}
+
+// WithErrorHandler returns a copy of the provided Context. The Monitors
+// that are created with it report asynchronous errors to the handler
+func WithErrorHandler(ctx context.Context, h ErrorHandler) context.Context {
+	return context.WithValue(ctx, errorHandlerKey{}, h)
+}
+
+// ReportError reports an error that was raised by a function that was
+// run asynchronously. It's passed to the ErrorHandler of the Monitor
+// that the function is bound to. If there's none, it's raised again
+func ReportError(fn data.Value, rec interface{}) {
+	if c, ok := fn.(*closure); ok && c.monitor != nil {
+		if h := c.monitor.onError; h != nil {
+			h(rec)
+			return
+		}
+	}
+	panic(rec)
+}
+
+// Call calls the provided function, monitoring it if it's a closure.
+// If a limit is exceeded, or the Context is done, an error is raised
+// from within the monitored code. It can be recovered, but a spent
+// budget or a done Context will raise it again at the next check
+func (m *Monitor) Call(fn data.Function, args ...data.Value) data.Value {
+	args = m.bindArgs(append(data.Values{}, args...))
+	if c, ok := fn.(*closure); ok {
+		return c.exec(m, args...)
+	}
+	return fn.Call(args...)
+}
+
+// Steps returns the number of VM instructions that have been performed
+func (m *Monitor) Steps() uint64 {
+	return atomic.LoadUint64(&m.steps)
+}
+
+// IsLimitError returns whether the provided error was raised because a
+// Monitor's limit was exceeded or its Context was done
+func IsLimitError(err error) bool {
+	var e data.Error
+	if !errors.As(err, &e) {
+		return false
+	}
+	typ, _ := e.Get(data.TypeKey)
+	switch typ {
+	case Interrupted, TimedOut, BudgetExceeded, DepthExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// bind returns a copy of a closure that's run by the Monitor when it's
+// called from Go, unless the closure already is. Other values are
+// returned as is
+func (m *Monitor) bind(v data.Value) data.Value {
+	if c, ok := v.(*closure); ok && c.monitor != m {
+		return c.bind(m)
+	}
+	return v
+}
+
+// bindArgs binds the closures among the arguments of a call from
+// monitored code to a Go function, in place
+func (m *Monitor) bindArgs(args data.Values) data.Values {
+	for i, a := range args {
+		args[i] = m.bind(a)
+	}
+	return args
+}
+
+func (m *Monitor) step() {
+	steps := atomic.AddUint64(&m.steps, 1)
+	if max := m.limits.Instructions; max != 0 && steps > max {
+		panic(data.NewError(BudgetExceeded,
+			fmt.Sprintf(ErrBudgetExceeded, max),
+		))
+	}
+}
+
+func (m *Monitor) checkDone() {
+	select {
+	case <-m.done:
+		if errors.Is(m.ctx.Err(), context.DeadlineExceeded) {
+			panic(data.NewError(TimedOut, ErrTimedOut))
+		}
+		panic(data.NewError(Interrupted, ErrInterrupted))
+	default:
+	}
+}
+
+func (m *Monitor) enter() {
+	m.checkDone()
+	depth := atomic.AddInt32(&m.depth, 1)
+	if max := m.limits.Depth; max != 0 && int(depth) > max {
+		atomic.AddInt32(&m.depth, -1)
+		panic(data.NewError(DepthExceeded,
+			fmt.Sprintf(ErrDepthExceeded, max),
+		))
+	}
+}
+
+func (m *Monitor) exit() {
+	atomic.AddInt32(&m.depth, -1)
+}
@@ -0,0 +1,72 @@
+package vm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kode4food/ale/data"
+	"github.com/kode4food/ale/internal/assert"
+	. "github.com/kode4food/ale/internal/assert/helpers"
+	"github.com/kode4food/ale/runtime/isa"
+	"github.com/kode4food/ale/runtime/vm"
+)
+
+var spin = []isa.Coder{
+	isa.Nil,
+	isa.Pop,
+	isa.Jump, isa.Offset(0),
+}
+
+func TestMonitorBudget(t *testing.T) {
+	as := assert.New(t)
+	m := vm.NewMonitor(context.Background(), vm.Limits{Instructions: 100})
+	as.Equal(I(11), m.Call(makeCode([]isa.Coder{
+		isa.Const, isa.Index(0),
+		isa.Const, isa.Index(1),
+		isa.Add,
+		isa.Return,
+	})))
+	as.Equal(uint64(4), m.Steps())
+
+	defer as.ExpectPanic("instruction budget exceeded: 100")
+	m.Call(makeCode(spin))
+}
+
+func TestMonitorCancel(t *testing.T) {
+	as := assert.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	m := vm.NewMonitor(ctx, vm.Limits{})
+
+	defer func() {
+		err := recover().(data.Error)
+		as.True(vm.IsLimitError(err))
+		as.Equal(vm.Interrupted, as.MustGet(err, data.TypeKey))
+		as.False(vm.IsLimitError(errors.New(vm.ErrInterrupted)))
+	}()
+	m.Call(makeCode(spin))
+}
+
+func TestMonitorTasks(t *testing.T) {
+	as := assert.New(t)
+	m := vm.NewMonitor(context.Background(), vm.Limits{Instructions: 100})
+	res := make(chan interface{})
+	start := data.Applicative(func(args ...data.Value) data.Value {
+		go func() {
+			defer func() { res <- recover() }()
+			args[0].(data.Function).Call()
+		}()
+		return data.Nil
+	}, 1)
+
+	// closures that are passed to Go functions remain monitored, even
+	// when they're called by the tasks that those functions start
+	m.Call(start, makeCode(spin))
+	err := (<-res).(data.Error)
+	as.Equal(vm.BudgetExceeded, as.MustGet(err, data.TypeKey))
+
+	// other closures aren't monitored, even once the budget is spent
+	add := makeCode([]isa.Coder{isa.One, isa.Two, isa.Add, isa.Return})
+	as.Equal(I(3), add.Call())
+}